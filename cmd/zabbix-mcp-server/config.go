@@ -0,0 +1,58 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools"
+)
+
+// newConfigCmd creates the config command
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the server configuration",
+	}
+
+	validateCmd := &cobra.Command{
+		Use:   "validate [file]",
+		Short: "Validate the configuration",
+		Long: `Check the configuration the server would start with: the configuration file
+given as argument or with --config, overridden by the environment and flags.
+Every invalid setting is reported.`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				if err := cmd.Flags().Set("config", args[0]); err != nil {
+					return err
+				}
+			}
+
+			cfg, err := resolveConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("invalid configuration:\n%w", err)
+			}
+
+			// Tool constructors only keep the logger for their handlers
+			logger := log.New()
+			logger.SetOutput(io.Discard)
+			selected, err := tools.SelectTools(logger, getToolsConfig(cfg))
+			if err != nil {
+				return fmt.Errorf("invalid configuration:\n%w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Configuration is valid: %s transport, %d tools, %d Zabbix instances\n",
+				cfg.Transport.Mode, len(selected), len(cfg.Zabbix.Instances))
+			return nil
+		},
+	}
+
+	cmd.AddCommand(validateCmd)
+	return cmd
+}
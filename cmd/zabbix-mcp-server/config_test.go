@@ -0,0 +1,140 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/vfcastr/Zabbix-MCP/pkg/config"
+)
+
+// newTestRootCmd returns a root command with the server's persistent flags
+func newTestRootCmd(subcommands ...*cobra.Command) *cobra.Command {
+	root := &cobra.Command{Use: "zabbix-mcp-server", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().String("config", "", "")
+	root.PersistentFlags().String("log-file", "", "")
+	root.PersistentFlags().String("log-format", "", "")
+	root.PersistentFlags().Bool("read-only", false, "")
+	root.PersistentFlags().Bool("dry-run", false, "")
+	root.PersistentFlags().String("toolsets", "", "")
+	root.PersistentFlags().String("enable-tools", "", "")
+	root.PersistentFlags().String("disable-tools", "", "")
+	root.AddCommand(subcommands...)
+	return root
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	clearEnv(t)
+	file := filepath.Join(t.TempDir(), "server.yaml")
+	content := "transport:\n  host: 0.0.0.0\n  port: 9090\n  endpoint: /zabbix\ntools:\n  toolsets: [hosts]\n"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.ConfigFile, file)
+	t.Setenv(config.TransportPort, "9191")
+	t.Setenv(config.Endpoint, "env-mcp")
+
+	var got config.Config
+	cmd := &cobra.Command{Use: "streamable-http", RunE: func(cmd *cobra.Command, _ []string) error {
+		var err error
+		got, err = resolveConfig(cmd)
+		return err
+	}}
+	addCommonFlags(cmd)
+	addHTTPFlags(cmd)
+	root := newTestRootCmd(cmd)
+	root.SetArgs([]string{"streamable-http", "--endpoint", "flag-mcp", "--toolsets", "problems"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := config.TransportConfig{
+		Host:              "0.0.0.0",   // file
+		Port:              9191,        // environment over file
+		Endpoint:          "/flag-mcp", // flag over environment and file
+		Mode:              config.DefaultTransportMode,
+		SessionIdleTTL:    config.Default().Transport.SessionIdleTTL,
+		ShutdownTimeout:   config.Default().Transport.ShutdownTimeout,
+		ReadHeaderTimeout: config.Default().Transport.ReadHeaderTimeout,
+		IdleTimeout:       config.Default().Transport.IdleTimeout,
+		ReadyCacheTTL:     config.Default().Transport.ReadyCacheTTL,
+	}
+	if got.Transport != want {
+		t.Errorf("transport:\ngot  %+v\nwant %+v", got.Transport, want)
+	}
+	if strings.Join(got.Tools.Toolsets, ",") != "problems" {
+		t.Errorf("expected the toolsets flag to win, got %v", got.Tools.Toolsets)
+	}
+}
+
+func TestConfigIgnoresCase(t *testing.T) {
+	clearEnv(t)
+	t.Setenv(config.TransportMode, "Streamable-HTTP")
+	t.Setenv(config.LogLevel, "INFO")
+
+	resolve := func(args ...string) (config.Config, error) {
+		var got config.Config
+		cmd := &cobra.Command{Use: "serve", RunE: func(cmd *cobra.Command, _ []string) error {
+			var err error
+			got, err = resolveConfig(cmd)
+			return err
+		}}
+		addCommonFlags(cmd)
+		root := newTestRootCmd(cmd)
+		root.SetArgs(append([]string{"serve"}, args...))
+		return got, root.Execute()
+	}
+
+	got, err := resolve("--transport-port", "9292")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Transport.Mode != "streamable-http" || got.Logging.Level != "info" || got.Transport.Port != 9292 {
+		t.Errorf("unexpected config: mode %q, level %q, port %d", got.Transport.Mode, got.Logging.Level, got.Transport.Port)
+	}
+	if _, err := resolve("--transport-port", "http"); err == nil || !strings.Contains(err.Error(), "--transport-port") {
+		t.Errorf("expected an invalid port error, got %v", err)
+	}
+}
+
+func TestConfigValidateCommand(t *testing.T) {
+	clearEnv(t)
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.toml")
+	invalid := filepath.Join(dir, "invalid.yaml")
+	unknownTool := filepath.Join(dir, "tools.yaml")
+	for file, content := range map[string]string{
+		valid:       "[transport]\nmode = \"streamable-http\"\n\n[tools]\ntoolsets = [\"hosts\"]\n",
+		invalid:     "transport:\n  port: 0\nlogging:\n  level: trace\n",
+		unknownTool: "tools:\n  disable_tools: [get_everything]\n",
+	} {
+		if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run := func(args ...string) (string, error) {
+		root := newTestRootCmd(newConfigCmd())
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetArgs(append([]string{"config", "validate"}, args...))
+		err := root.Execute()
+		return out.String(), err
+	}
+
+	if out, err := run(valid); err != nil || !strings.Contains(out, "Configuration is valid: streamable-http transport") {
+		t.Errorf("expected a valid configuration, got %q, %v", out, err)
+	}
+	if _, err := run("--config", invalid); err == nil || !strings.Contains(err.Error(), "transport.port") || !strings.Contains(err.Error(), "logging.level") {
+		t.Errorf("expected every invalid setting to be reported, got %v", err)
+	}
+	if _, err := run(unknownTool); err == nil || !strings.Contains(err.Error(), `unknown tool "get_everything"`) {
+		t.Errorf("expected an unknown tool error, got %v", err)
+	}
+}
@@ -0,0 +1,715 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	stdlog "log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/config"
+	"github.com/vfcastr/Zabbix-MCP/pkg/metrics"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+const goldenDir = "testdata/tools"
+
+// clearEnv unsets the environment that would otherwise leak credentials,
+// authentication or rate limits from the developer's shell into a test
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, env := range []string{
+		client.ZabbixURL, client.ZabbixToken, client.ZabbixUser, client.ZabbixPassword,
+		client.AuthAPIKeys, client.AuthKeysFile, client.AuthHMACSecret, client.OAuthIssuer, client.OAuthJWKSFile, client.OAuthJWKSURL, client.ZabbixInstancesFile, client.ZabbixInstances,
+		config.ConfigFile, config.TransportMode, config.TransportHost, config.TransportPort, config.Endpoint,
+		config.Toolsets, config.EnableTools, config.DisableTools, config.DryRun, client.ZabbixReadOnly,
+		client.ZabbixTLSCAFile, client.ZabbixTLSCertFile, client.ZabbixTLSKeyFile, client.ZabbixTLSServerName,
+	} {
+		t.Setenv(env, "")
+	}
+	t.Setenv(client.RateLimitGlobalRPS, "0")
+	t.Setenv(client.RateLimitSessionRPS, "0")
+	t.Setenv(client.ZabbixMaxRetries, "0")
+	t.Setenv(client.RateLimitZabbixRPS, "0")
+	t.Cleanup(func() { client.SetConnection(nil) })
+}
+
+// newMCPServer builds the server exactly as the stdio and HTTP commands do
+func newMCPServer(t *testing.T) *server.MCPServer {
+	t.Helper()
+	mcpServer := NewServer("test", zabbixtest.Logger())
+	if err := tools.InitTools(mcpServer, zabbixtest.Logger(), tools.Config{}); err != nil {
+		t.Fatalf("InitTools: %v", err)
+	}
+	return mcpServer
+}
+
+// startStdio serves mcpServer over in-memory pipes, configured from the
+// environment, and returns an initialized client talking to it
+func startStdio(t *testing.T, mcpServer *server.MCPServer, opts ...mcpclient.ClientOption) *mcpclient.Client {
+	t.Helper()
+	if _, err := loadConfig(nil); err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		stdio := server.NewStdioServer(mcpServer)
+		stdio.SetErrorLogger(stdlog.New(io.Discard, "", 0))
+		stdio.Listen(ctx, serverReader, serverWriter)
+	}()
+
+	c := mcpclient.NewClient(transport.NewIO(clientReader, clientWriter, io.NopCloser(strings.NewReader(""))), opts...)
+	t.Cleanup(func() {
+		c.Close()
+		cancel()
+		serverWriter.Close()
+		serverReader.Close()
+		<-done
+	})
+
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	initialize(t, c)
+	return c
+}
+
+// startHTTP serves mcpServer over streamable-http behind the production
+// middleware stack, configured from the environment
+func startHTTP(t *testing.T, mcpServer *server.MCPServer) *httptest.Server {
+	t.Helper()
+	cfg, err := loadConfig(nil)
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	handler, err := newHTTPHandler(mcpServer, zabbixtest.Logger(), cfg)
+	if err != nil {
+		t.Fatalf("newHTTPHandler: %v", err)
+	}
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// connectHTTP returns an initialized streamable-http client sending headers
+func connectHTTP(t *testing.T, ts *httptest.Server, headers map[string]string) *mcpclient.Client {
+	t.Helper()
+	c, err := mcpclient.NewStreamableHttpClient(ts.URL+config.DefaultEndpointPath, transport.WithHTTPHeaders(headers))
+	if err != nil {
+		t.Fatalf("NewStreamableHttpClient: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	initialize(t, c)
+	return c
+}
+
+func initialize(t *testing.T, c *mcpclient.Client) {
+	t.Helper()
+	req := mcp.InitializeRequest{}
+	req.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	req.Params.ClientInfo = mcp.Implementation{Name: "zabbix-mcp-e2e", Version: "test"}
+	if _, err := c.Initialize(context.Background(), req); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+}
+
+func callTool(t *testing.T, c *mcpclient.Client, name string, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	result, err := c.CallTool(ctx, req)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return result
+}
+
+func listTools(t *testing.T, c *mcpclient.Client) []mcp.Tool {
+	t.Helper()
+	result, err := c.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	return result.Tools
+}
+
+func TestStdio(t *testing.T) {
+	clearEnv(t)
+	zabbix := zabbixtest.NewServer(t)
+	zabbix.Model.Add(zabbixtest.Hosts, zabbixtest.Object{"hostid": "10084", "host": "web01", "name": "web01"})
+	t.Setenv(client.ZabbixURL, zabbix.APIURL())
+	t.Setenv(client.ZabbixToken, zabbixtest.Token)
+
+	c := startStdio(t, newMCPServer(t))
+
+	if got, want := len(listTools(t, c)), len(registeredTools(t)); got != want {
+		t.Errorf("tools/list returned %d tools, want %d", got, want)
+	}
+
+	result := callTool(t, c, "get_hosts", map[string]interface{}{"search": "web"})
+	if result.IsError || !strings.Contains(zabbixtest.ResultText(result), `"host": "web01"`) {
+		t.Fatalf("unexpected result: %s", zabbixtest.ResultText(result))
+	}
+	req, _ := zabbix.LastCall("host.get")
+	zabbixtest.AssertJSON(t, req.Params, `{"output":"extend","search":{"name":"web"},"selectHostGroups":"extend","selectParentTemplates":"extend","selectTags":"extend","selectInterfaces":"extend","selectMacros":"extend","selectInventory":"extend","limit":100}`)
+}
+
+func TestStdioHidesToolsForOldZabbix(t *testing.T) {
+	clearEnv(t)
+	zabbix := zabbixtest.NewServer(t)
+	zabbix.Version = "6.0.30"
+	t.Setenv(client.ZabbixURL, zabbix.APIURL())
+	t.Setenv(client.ZabbixToken, zabbixtest.Token)
+
+	c := startStdio(t, newMCPServer(t))
+
+	listed := make(map[string]bool)
+	for _, tool := range listTools(t, c) {
+		listed[tool.Name] = true
+	}
+	if listed["zabbix_get_proxy_groups"] {
+		t.Error("proxy group tools must be hidden on Zabbix 6.0")
+	}
+	if listed["zabbix_get_template_groups"] {
+		t.Error("template group tools must be hidden on Zabbix 6.0")
+	}
+	if !listed["zabbix_get_proxies"] {
+		t.Error("proxy tools must stay available on Zabbix 6.0")
+	}
+
+	result := callTool(t, c, "zabbix_get_proxy_groups", map[string]interface{}{})
+	if !result.IsError || !strings.Contains(zabbixtest.ResultText(result), "requires Zabbix 7.0") {
+		t.Errorf("expected the call to be refused, got: %s", zabbixtest.ResultText(result))
+	}
+
+	// Host groups are selected with the option Zabbix 6.0 knows, and come
+	// back under the field newer servers use
+	zabbix.Model.Add(zabbixtest.Hosts, zabbixtest.Object{"hostid": "1", "host": "web01", "groups": []interface{}{map[string]interface{}{"groupid": "2"}}})
+	result = callTool(t, c, "get_hosts", map[string]interface{}{})
+	if result.IsError {
+		t.Fatalf("get_hosts failed on Zabbix 6.0: %s", zabbixtest.ResultText(result))
+	}
+	req, _ := zabbix.LastCall("host.get")
+	var params map[string]interface{}
+	json.Unmarshal(req.Params, &params)
+	if _, ok := params["selectGroups"]; !ok || params["selectHostGroups"] != nil {
+		t.Errorf("expected selectGroups, got %s", req.Params)
+	}
+}
+
+func TestStdioHidesToolsPerInstance(t *testing.T) {
+	clearEnv(t)
+	eu := zabbixtest.NewServer(t)
+	us := zabbixtest.NewServer(t)
+	eu.Version = "6.0.30"
+	us.Version = "6.0.30"
+	file := filepath.Join(t.TempDir(), "instances.json")
+	config := fmt.Sprintf(`{"default": "eu", "instances": [
+		{"name": "eu", "url": %q, "token": %q},
+		{"name": "us", "url": %q, "token": %q}
+	]}`, eu.APIURL(), zabbixtest.Token, us.APIURL(), zabbixtest.Token)
+	if err := os.WriteFile(file, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(client.ZabbixInstancesFile, file)
+	t.Cleanup(func() { client.SetInstances(client.InstancesConfig{}) })
+
+	cfg, err := loadConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mcpServer := NewServer("test", zabbixtest.Logger())
+	if err := tools.InitTools(mcpServer, zabbixtest.Logger(), getToolsConfig(cfg)); err != nil {
+		t.Fatal(err)
+	}
+	c := startStdio(t, mcpServer)
+
+	listed := func() bool {
+		for _, tool := range listTools(t, c) {
+			if tool.Name == "zabbix_get_template_groups" {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Until us is connected its version is unknown, so it may serve the tool
+	callTool(t, c, "get_hosts", map[string]interface{}{"instance": "eu"})
+	if !listed() {
+		t.Error("template group tools must stay listed while an instance version is unknown")
+	}
+	result := callTool(t, c, "zabbix_get_template_groups", map[string]interface{}{"instance": "eu"})
+	if !result.IsError || !strings.Contains(zabbixtest.ResultText(result), "requires Zabbix 6.2") {
+		t.Errorf("expected the call to be refused, got: %s", zabbixtest.ResultText(result))
+	}
+
+	callTool(t, c, "get_hosts", map[string]interface{}{"instance": "us"})
+	if listed() {
+		t.Error("template group tools must be hidden once every instance runs Zabbix 6.0")
+	}
+}
+
+func TestStdioInstanceRouting(t *testing.T) {
+	clearEnv(t)
+	home := zabbixtest.NewServer(t)
+	eu := zabbixtest.NewServer(t)
+	us := zabbixtest.NewServer(t)
+	us.AddUser("automation", "secret")
+	eu.Model.Add(zabbixtest.Hosts, zabbixtest.Object{"hostid": "1", "host": "eu-web01"})
+	us.Model.Add(zabbixtest.Hosts, zabbixtest.Object{"hostid": "1", "host": "us-web01"})
+	t.Setenv(client.ZabbixURL, home.APIURL())
+	t.Setenv(client.ZabbixToken, zabbixtest.Token)
+
+	file := filepath.Join(t.TempDir(), "instances.json")
+	config := fmt.Sprintf(`{"instances": [
+		{"name": "eu", "url": %q, "token": %q},
+		{"name": "us", "url": %q, "user": "automation", "password": "secret"}
+	]}`, eu.APIURL(), zabbixtest.Token, us.APIURL())
+	if err := os.WriteFile(file, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(client.ZabbixInstancesFile, file)
+	t.Cleanup(func() { client.SetInstances(client.InstancesConfig{}) })
+
+	cfg, err := loadConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mcpServer := NewServer("test", zabbixtest.Logger())
+	if err := tools.InitTools(mcpServer, zabbixtest.Logger(), getToolsConfig(cfg)); err != nil {
+		t.Fatal(err)
+	}
+	c := startStdio(t, mcpServer)
+
+	for _, tc := range []struct {
+		instance string
+		want     string
+	}{
+		{"eu", "eu-web01"},
+		{"us", "us-web01"},
+		{"eu", "eu-web01"},
+	} {
+		result := callTool(t, c, "get_hosts", map[string]interface{}{"instance": tc.instance})
+		if text := zabbixtest.ResultText(result); result.IsError || !strings.Contains(text, tc.want) {
+			t.Errorf("instance %s: expected %s, got: %s", tc.instance, tc.want, text)
+		}
+	}
+	if got := len(us.Calls("user.login")); got != 1 {
+		t.Errorf("expected the us client to be reused, got %d logins", got)
+	}
+	home.AssertNotCalled(t, "host.get")
+
+	result := callTool(t, c, "get_hosts", map[string]interface{}{"instance": "apac"})
+	if !result.IsError || !strings.Contains(zabbixtest.ResultText(result), `unknown Zabbix instance "apac"`) {
+		t.Errorf("expected an unknown instance error, got: %s", zabbixtest.ResultText(result))
+	}
+
+	result = callTool(t, c, "list_zabbix_instances", nil)
+	if text := zabbixtest.ResultText(result); strings.Contains(text, "secret") || !strings.Contains(text, `"name": "us"`) {
+		t.Errorf("unexpected instance list: %s", text)
+	}
+}
+
+func TestStdioFederatedQuery(t *testing.T) {
+	clearEnv(t)
+	home := zabbixtest.NewServer(t)
+	eu := zabbixtest.NewServer(t)
+	us := zabbixtest.NewServer(t)
+	eu.Model.Add(zabbixtest.Hosts, zabbixtest.Object{"hostid": "1", "host": "eu-web01"})
+	us.Model.Add(zabbixtest.Hosts, zabbixtest.Object{"hostid": "1", "host": "us-web01"})
+	us.Fail("event.get", zabbixtest.Error{Code: zabbixtest.CodeInternal, Message: "Application error."}, 0)
+	t.Setenv(client.ZabbixURL, home.APIURL())
+	t.Setenv(client.ZabbixToken, zabbixtest.Token)
+	t.Setenv(client.ZabbixInstances, fmt.Sprintf("eu=%s|%s,us=%s|%s", eu.APIURL(), zabbixtest.Token, us.APIURL(), zabbixtest.Token))
+	t.Cleanup(func() { client.SetInstances(client.InstancesConfig{}) })
+
+	cfg, err := loadConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mcpServer := NewServer("test", zabbixtest.Logger())
+	if err := tools.InitTools(mcpServer, zabbixtest.Logger(), getToolsConfig(cfg)); err != nil {
+		t.Fatal(err)
+	}
+	c := startStdio(t, mcpServer)
+
+	result := callTool(t, c, "get_hosts", map[string]interface{}{"instances": "all"})
+	text := zabbixtest.ResultText(result)
+	if result.IsError || !strings.Contains(text, `"instance": "eu"`) || !strings.Contains(text, "us-web01") {
+		t.Errorf("expected hosts from both instances, got: %s", text)
+	}
+	home.AssertNotCalled(t, "host.get")
+
+	// A failing instance is reported next to the others' results
+	result = callTool(t, c, "get_events", map[string]interface{}{"instances": "eu,us"})
+	text = zabbixtest.ResultText(result)
+	if result.IsError || !strings.Contains(text, `"failures"`) || !strings.Contains(text, `"instance": "us"`) {
+		t.Errorf("expected a partial result, got: %s", text)
+	}
+}
+
+// confirmer answers elicitation requests like a user clicking a button
+type confirmer struct {
+	mu       sync.Mutex
+	action   mcp.ElicitationResponseAction
+	messages []string
+}
+
+func (c *confirmer) Elicit(ctx context.Context, req mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = append(c.messages, req.Params.Message)
+	return &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{
+		Action:  c.action,
+		Content: map[string]interface{}{"confirm": c.action == mcp.ElicitationResponseActionAccept},
+	}}, nil
+}
+
+func TestStdioElicitation(t *testing.T) {
+	clearEnv(t)
+	zabbix := zabbixtest.NewServer(t)
+	zabbix.Model.Add(zabbixtest.Hosts, zabbixtest.Object{"hostid": "10084", "host": "web01"})
+	t.Setenv(client.ZabbixURL, zabbix.APIURL())
+	t.Setenv(client.ZabbixToken, zabbixtest.Token)
+
+	user := &confirmer{action: mcp.ElicitationResponseActionDecline}
+	c := startStdio(t, newMCPServer(t), mcpclient.WithElicitationHandler(user))
+
+	// The confirm argument must not bypass a client that can ask the user
+	result := callTool(t, c, "delete_host", map[string]interface{}{"hostids": "10084", "confirm": true})
+	if !result.IsError || !strings.Contains(zabbixtest.ResultText(result), "decline") {
+		t.Fatalf("expected a declined operation, got: %s", zabbixtest.ResultText(result))
+	}
+	zabbix.AssertNotCalled(t, "host.delete")
+
+	user.action = mcp.ElicitationResponseActionAccept
+	result = callTool(t, c, "delete_host", map[string]interface{}{"hostids": "10084"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", zabbixtest.ResultText(result))
+	}
+	if len(zabbix.Calls("host.delete")) != 1 {
+		t.Errorf("expected host.delete after approval, got %v", zabbix.Methods())
+	}
+	if len(user.messages) != 2 || !strings.Contains(user.messages[0], "web01 (10084)") {
+		t.Errorf("unexpected elicitation messages: %q", user.messages)
+	}
+}
+
+func TestStdioDryRun(t *testing.T) {
+	clearEnv(t)
+	zabbix := zabbixtest.NewServer(t)
+	zabbix.Model.Add(zabbixtest.Hosts, zabbixtest.Object{"hostid": "10084", "host": "web01"})
+	t.Setenv(client.ZabbixURL, zabbix.APIURL())
+	t.Setenv(client.ZabbixToken, zabbixtest.Token)
+
+	c := startStdio(t, newMCPServer(t))
+
+	result := callTool(t, c, "update_host", map[string]interface{}{"hostid": "10084", "status": float64(1), "dry_run": true})
+	text := zabbixtest.ResultText(result)
+	if result.IsError || !strings.Contains(text, `Update host \"web01\" (10084): status`) || !strings.Contains(text, `"method": "host.update"`) {
+		t.Fatalf("expected a preview of the update, got: %s", text)
+	}
+	zabbix.AssertNotCalled(t, "host.update")
+
+	// Dry runs need no confirmation as they change nothing
+	result = callTool(t, c, "delete_host", map[string]interface{}{"hostids": "10084", "dry_run": true})
+	if text := zabbixtest.ResultText(result); result.IsError || !strings.Contains(text, `Delete host \"web01\" (10084)`) {
+		t.Fatalf("expected a preview of the delete, got: %s", text)
+	}
+	zabbix.AssertNotCalled(t, "host.delete")
+
+	result = callTool(t, c, "create_item", map[string]interface{}{
+		"hostid": "999", "name": "CPU load", "key_": "system.cpu.load", "type": float64(0), "value_type": float64(0), "dry_run": true,
+	})
+	if text := zabbixtest.ResultText(result); !result.IsError || !strings.Contains(text, "host 999 does not exist") {
+		t.Errorf("expected the unknown host to be reported, got: %s", text)
+	}
+	zabbix.AssertNotCalled(t, "item.create")
+}
+
+func TestStdioDryRunMode(t *testing.T) {
+	clearEnv(t)
+	zabbix := zabbixtest.NewServer(t)
+	zabbix.Model.Add(zabbixtest.Hosts, zabbixtest.Object{"hostid": "10084", "host": "web01"})
+	t.Setenv(client.ZabbixURL, zabbix.APIURL())
+	t.Setenv(client.ZabbixToken, zabbixtest.Token)
+	t.Setenv(config.DryRun, "true")
+
+	cfg, err := loadConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mcpServer := NewServer("test", zabbixtest.Logger())
+	if err := tools.InitTools(mcpServer, zabbixtest.Logger(), getToolsConfig(cfg)); err != nil {
+		t.Fatal(err)
+	}
+	c := startStdio(t, mcpServer)
+
+	for _, tool := range listTools(t, c) {
+		if _, ok := tool.InputSchema.Properties[tools.DryRunArgument]; ok {
+			t.Errorf("%s: the dry_run argument has no effect in dry-run mode", tool.Name)
+		}
+	}
+
+	result := callTool(t, c, "delete_host", map[string]interface{}{"hostids": "10084", "confirm": true})
+	if text := zabbixtest.ResultText(result); result.IsError || !strings.Contains(text, `"dry_run": true`) {
+		t.Fatalf("expected a preview, got: %s", text)
+	}
+	zabbix.AssertNotCalled(t, "host.delete")
+}
+
+func TestHTTPHeaderCredentials(t *testing.T) {
+	clearEnv(t)
+	zabbix := zabbixtest.NewServer(t)
+	zabbix.AddToken("header-token")
+	zabbix.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": "501", "name": "High CPU on web01"})
+
+	ts := startHTTP(t, newMCPServer(t))
+	c := connectHTTP(t, ts, map[string]string{
+		client.ZabbixHeaderURL:   zabbix.APIURL(),
+		client.ZabbixHeaderToken: "header-token",
+	})
+
+	result := callTool(t, c, "get_problems", map[string]interface{}{})
+	if result.IsError || !strings.Contains(zabbixtest.ResultText(result), "High CPU on web01") {
+		t.Fatalf("unexpected result: %s", zabbixtest.ResultText(result))
+	}
+
+	req, ok := zabbix.LastCall("problem.get")
+	if !ok {
+		t.Fatalf("expected a problem.get call, got %v", zabbix.Methods())
+	}
+	if req.Auth != "header-token" {
+		t.Errorf("Zabbix call authenticated with %q, want the header token", req.Auth)
+	}
+}
+
+func TestHTTPWithoutCredentials(t *testing.T) {
+	clearEnv(t)
+	ts := startHTTP(t, newMCPServer(t))
+	c := connectHTTP(t, ts, nil)
+
+	result := callTool(t, c, "get_hosts", map[string]interface{}{})
+	if !result.IsError || !strings.Contains(zabbixtest.ResultText(result), "not provided") {
+		t.Fatalf("expected a missing credentials error, got: %s", zabbixtest.ResultText(result))
+	}
+}
+
+func TestHTTPMetrics(t *testing.T) {
+	clearEnv(t)
+	zabbix := zabbixtest.NewServer(t)
+	zabbix.Fail("host.get", zabbixtest.Error{Code: zabbixtest.CodeInternal, Message: "Application error.", Data: "Database unavailable"}, 1)
+
+	ts := startHTTP(t, newMCPServer(t))
+	c := connectHTTP(t, ts, map[string]string{
+		client.ZabbixHeaderURL:   zabbix.APIURL(),
+		client.ZabbixHeaderToken: zabbixtest.Token,
+	})
+	callTool(t, c, "get_problems", map[string]interface{}{})
+	callTool(t, c, "get_hosts", map[string]interface{}{})
+
+	resp, err := http.Get(ts.URL + metrics.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`zabbix_mcp_tool_calls_total{status="ok",tool="get_problems"}`,
+		`zabbix_mcp_tool_calls_total{status="error",tool="get_hosts"}`,
+		`zabbix_mcp_tool_call_duration_seconds_count{tool="get_problems"}`,
+		`zabbix_mcp_zabbix_request_duration_seconds_count{instance="default",method="problem.get",status="ok"}`,
+		`zabbix_mcp_zabbix_request_duration_seconds_count{instance="default",method="host.get",status="error"}`,
+		"zabbix_mcp_active_sessions ",
+		`zabbix_mcp_build_info{build_date=`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected %s in the metrics", want)
+		}
+	}
+}
+
+func TestHTTPSessionIsolation(t *testing.T) {
+	clearEnv(t)
+	ts := startHTTP(t, newMCPServer(t))
+
+	type tenant struct {
+		name   string
+		token  string
+		zabbix *zabbixtest.Server
+		client *mcpclient.Client
+	}
+	tenants := []*tenant{{name: "alpha", token: "alpha-token"}, {name: "beta", token: "beta-token"}}
+	for _, tn := range tenants {
+		tn.zabbix = zabbixtest.NewServer(t)
+		tn.zabbix.AddToken(tn.token)
+		tn.zabbix.Model.Add(zabbixtest.Hosts, zabbixtest.Object{"host": tn.name + "-host", "name": tn.name + "-host"})
+		tn.client = connectHTTP(t, ts, map[string]string{
+			client.ZabbixHeaderURL:   tn.zabbix.APIURL(),
+			client.ZabbixHeaderToken: tn.token,
+		})
+	}
+
+	const calls = 5
+	results := make(map[string][]string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, tn := range tenants {
+		for i := 0; i < calls; i++ {
+			wg.Add(1)
+			go func(tn *tenant) {
+				defer wg.Done()
+				result := callTool(t, tn.client, "get_hosts", map[string]interface{}{})
+				mu.Lock()
+				results[tn.name] = append(results[tn.name], zabbixtest.ResultText(result))
+				mu.Unlock()
+			}(tn)
+		}
+	}
+	wg.Wait()
+
+	for i, tn := range tenants {
+		other := tenants[1-i]
+		for _, text := range results[tn.name] {
+			if !strings.Contains(text, tn.name+"-host") || strings.Contains(text, other.name+"-host") {
+				t.Errorf("%s saw another tenant's data: %s", tn.name, text)
+			}
+		}
+		calls := tn.zabbix.Calls("host.get")
+		if len(calls) != 5 {
+			t.Errorf("%s: expected 5 host.get calls on its own Zabbix, got %d", tn.name, len(calls))
+		}
+		for _, req := range calls {
+			if req.Auth != tn.token {
+				t.Errorf("%s: Zabbix call authenticated with %q", tn.name, req.Auth)
+			}
+		}
+	}
+}
+
+// registeredTools returns the tools InitTools registers, keyed by name
+func registeredTools(t *testing.T) map[string]*server.ServerTool {
+	t.Helper()
+	return newMCPServer(t).ListTools()
+}
+
+// TestToolSchemas compares every tool definition, as seen by a client, with
+// the golden files in testdata/tools. Run with -update after an intentional
+// schema change.
+func TestToolSchemas(t *testing.T) {
+	clearEnv(t)
+	ts := startHTTP(t, newMCPServer(t))
+	c := connectHTTP(t, ts, nil)
+
+	listed := listTools(t, c)
+	if *update {
+		if err := os.RemoveAll(goldenDir); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(goldenDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, tool := range listed {
+		seen[tool.Name] = true
+		got, err := json.MarshalIndent(tool, "", "  ")
+		if err != nil {
+			t.Fatalf("%s: %v", tool.Name, err)
+		}
+		got = append(got, '\n')
+
+		path := filepath.Join(goldenDir, tool.Name+".json")
+		if *update {
+			if err := os.WriteFile(path, got, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("%s: no golden file; run go test -run TestToolSchemas -update ./cmd/zabbix-mcp-server/", tool.Name)
+			continue
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s: schema differs from %s; run with -update if the change is intended\n%s", tool.Name, path, diffLines(string(want), string(got)))
+		}
+	}
+
+	golden, err := filepath.Glob(filepath.Join(goldenDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stale []string
+	for _, path := range golden {
+		if name := strings.TrimSuffix(filepath.Base(path), ".json"); !seen[name] {
+			stale = append(stale, name)
+		}
+	}
+	sort.Strings(stale)
+	if len(stale) > 0 {
+		t.Errorf("golden files for tools that are no longer registered: %v", stale)
+	}
+}
+
+// diffLines reports the lines that differ between want and got
+func diffLines(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+
+	var b strings.Builder
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			fmt.Fprintf(&b, "line %d:\n- %s\n+ %s\n", i+1, w, g)
+		}
+	}
+	return b.String()
+}
@@ -6,23 +6,32 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/config"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 )
 
 // initLogger initializes and returns a configured logger
-func initLogger(logFile string) (*log.Logger, error) {
+func initLogger(cfg config.LoggingConfig) (*log.Logger, error) {
 	logger := log.New()
 
 	// Set log format
-	logger.SetFormatter(&log.TextFormatter{
-		FullTimestamp: true,
-	})
+	if cfg.Format == "json" {
+		logger.SetFormatter(&log.JSONFormatter{})
+	} else {
+		logger.SetFormatter(&log.TextFormatter{
+			FullTimestamp: true,
+		})
+	}
+
+	// Keep credentials and other secrets out of the log
+	logger.AddHook(logging.RedactHook{})
 
-	// Set log level from environment or default to Info
-	level := os.Getenv("LOG_LEVEL")
-	switch level {
+	switch cfg.Level {
 	case "debug":
 		logger.SetLevel(log.DebugLevel)
 	case "warn":
@@ -34,8 +43,8 @@ func initLogger(logFile string) (*log.Logger, error) {
 	}
 
 	// Configure output
-	if logFile != "" {
-		file, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if cfg.File != "" {
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
 		if err != nil {
 			return nil, fmt.Errorf("failed to open log file: %v", err)
 		}
@@ -50,7 +59,15 @@ func initLogger(logFile string) (*log.Logger, error) {
 
 // addCommonFlags adds flags common to multiple commands
 func addCommonFlags(cmd *cobra.Command) {
-	cmd.Flags().String("transport-host", DefaultBindAddress, "Host to bind to")
-	cmd.Flags().String("transport-port", DefaultBindPort, "Port to bind to")
-	cmd.Flags().String("endpoint", DefaultEndPointPath, "Endpoint path for MCP")
+	cmd.Flags().String("transport-host", config.DefaultBindAddress, "Host to bind to")
+	cmd.Flags().String("transport-port", strconv.Itoa(config.DefaultBindPort), "Port to bind to")
+	cmd.Flags().String("endpoint", config.DefaultEndpointPath, "Endpoint path for MCP")
+}
+
+// addHTTPFlags adds flags specific to the HTTP transports
+func addHTTPFlags(cmd *cobra.Command) {
+	cmd.Flags().Duration("session-idle-ttl", client.DefaultSessionIdleTTL, "Evict Zabbix clients of sessions idle longer than this (0 disables)")
+	cmd.Flags().String("tls-cert", "", "PEM certificate to serve HTTPS with; reloaded when it changes or on SIGHUP")
+	cmd.Flags().String("tls-key", "", "PEM private key of --tls-cert")
+	cmd.Flags().String("tls-client-ca", "", "PEM CA bundle; clients must present a certificate signed by it")
 }
@@ -0,0 +1,73 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools"
+)
+
+// newListToolsCmd creates the list-tools command
+func newListToolsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-tools",
+		Short: "List the tools that would be registered",
+		Long: `Print the toolsets and tools that the server would register with the current
+--read-only, --toolsets, --enable-tools and --disable-tools settings.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// Tool constructors only keep the logger for their handlers
+			logger := log.New()
+			logger.SetOutput(io.Discard)
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+			selected, err := tools.SelectTools(logger, getToolsConfig(cfg))
+			if err != nil {
+				return err
+			}
+
+			showSets, _ := cmd.Flags().GetBool("toolsets-only")
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			if showSets {
+				counts := make(map[string]int)
+				for _, tool := range selected {
+					counts[tool.Toolset]++
+				}
+				fmt.Fprintln(w, "TOOLSET\tTOOLS\tDESCRIPTION")
+				for _, set := range tools.Toolsets {
+					if counts[set.Name] > 0 {
+						fmt.Fprintf(w, "%s\t%d\t%s\n", set.Name, counts[set.Name], set.Description)
+					}
+				}
+			} else {
+				fmt.Fprintln(w, "TOOLSET\tTOOL\tACCESS")
+				for _, tool := range selected {
+					access := "write"
+					switch hints := tool.Tool.Annotations; {
+					case hints.ReadOnlyHint != nil && *hints.ReadOnlyHint:
+						access = "read-only"
+					case hints.DestructiveHint != nil && *hints.DestructiveHint:
+						access = "destructive"
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\n", tool.Toolset, tool.Tool.Name, access)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "\n%d tools\n", len(selected))
+			return nil
+		},
+	}
+	cmd.Flags().Bool("toolsets-only", false, "Summarize by toolset instead of listing tools")
+	return cmd
+}
@@ -8,10 +8,12 @@ import (
 	"errors"
 	"fmt"
 	stdlog "log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"path"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -19,47 +21,75 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/vfcastr/Zabbix-MCP/pkg/audit"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/config"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/metrics"
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tracing"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 	"github.com/vfcastr/Zabbix-MCP/version"
 )
 
-const (
-	DefaultBindAddress  = "127.0.0.1"
-	DefaultBindPort     = "8080"
-	DefaultEndPointPath = "/mcp"
-)
+func runHTTPServer(logger *log.Logger, cfg config.Config) error {
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing, version.Version)
+	if err != nil {
+		return err
+	}
+	defer flushTraces(shutdownTracing, logger)
+
+	closeAudit, err := openAuditLog(cfg.Audit, logger)
+	if err != nil {
+		return err
+	}
+	defer closeAudit()
 
-func runHTTPServer(logger *log.Logger, host string, port string, endpointPath string) error {
 	mcpServer := NewServer(version.Version, logger)
-	tools.InitTools(mcpServer, logger)
+	if err := tools.InitTools(mcpServer, logger, getToolsConfig(cfg)); err != nil {
+		return fmt.Errorf("failed to register tools: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Evict Zabbix clients of sessions that went away without a DELETE
+	client.StartSessionSweeper(ctx, time.Duration(cfg.Transport.SessionIdleTTL), logger)
 
-	return httpServerInit(context.Background(), mcpServer, logger, host, port, endpointPath)
+	return httpServerInit(ctx, mcpServer, logger, cfg)
 }
 
-func httpServerInit(ctx context.Context, mcpServer *server.MCPServer, logger *log.Logger, host string, port string, endpointPath string) error {
-	addr := fmt.Sprintf("%s:%s", host, port)
+func httpServerInit(ctx context.Context, mcpServer *server.MCPServer, logger *log.Logger, cfg config.Config) error {
+	transport := cfg.Transport
+	addr := net.JoinHostPort(transport.Host, strconv.Itoa(transport.Port))
 	logger.WithFields(log.Fields{
 		"address":  addr,
-		"endpoint": endpointPath,
+		"endpoint": transport.Endpoint,
 	}).Info("Starting HTTP server")
 
-	// Create HTTP server with streaming support
-	httpServer := server.NewStreamableHTTPServer(mcpServer)
-
-	// Create mux and add routes
-	mux := http.NewServeMux()
-
-	// Health check endpoint
-	mux.HandleFunc("/health", client.HealthHandler(logger))
-
-	// MCP endpoint
-	mux.Handle(endpointPath, client.BuildMiddlewareStack(httpServer, logger))
+	handler, err := newHTTPHandler(mcpServer, logger, cfg)
+	if err != nil {
+		return err
+	}
 
 	// Create the HTTP server
 	srv := &http.Server{
-		Addr:    addr,
-		Handler: mux,
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: time.Duration(transport.ReadHeaderTimeout),
+		IdleTimeout:       time.Duration(transport.IdleTimeout),
+	}
+
+	// Serve HTTPS with a certificate that is reloaded when its files
+	// change or on SIGHUP
+	var reloader *client.CertReloader
+	if transport.TLS.Enabled() {
+		reloader, err = client.NewCertReloader(transport.TLS, logger)
+		if err != nil {
+			return err
+		}
+		srv.TLSConfig = reloader.TLSConfig()
+		go reloader.Watch(ctx, client.DefaultCertPollInterval)
 	}
 
 	// Channel to receive server errors
@@ -67,8 +97,18 @@ func httpServerInit(ctx context.Context, mcpServer *server.MCPServer, logger *lo
 
 	// Start server in goroutine
 	go func() {
-		logger.WithField("address", addr).Info("HTTP server listening")
-		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.WithFields(log.Fields{
+			"address": addr,
+			"tls":     reloader != nil,
+		}).Info("HTTP server listening")
+
+		var err error
+		if reloader != nil {
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			serverErrors <- err
 		}
 	}()
@@ -76,39 +116,136 @@ func httpServerInit(ctx context.Context, mcpServer *server.MCPServer, logger *lo
 	// Wait for interrupt signal or server error
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+
+	for {
+		select {
+		case err := <-serverErrors:
+			return fmt.Errorf("server error: %w", err)
+		case <-reloadChan:
+			if reloader == nil {
+				continue
+			}
+			if err := reloader.Reload(); err != nil {
+				logger.WithError(err).Error("Failed to reload TLS certificate, keeping the previous one")
+				continue
+			}
+			logger.Info("Reloaded TLS certificate")
+		case sig := <-sigChan:
+			logger.WithField("signal", sig).Info("Received shutdown signal")
 
-	select {
-	case err := <-serverErrors:
-		return fmt.Errorf("server error: %w", err)
-	case sig := <-sigChan:
-		logger.WithField("signal", sig).Info("Received shutdown signal")
+			// Create shutdown context with timeout
+			shutdownCtx, cancel := context.WithTimeout(ctx, time.Duration(transport.ShutdownTimeout))
+			defer cancel()
 
-		// Create shutdown context with timeout
-		shutdownCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-		defer cancel()
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				return fmt.Errorf("server shutdown error: %w", err)
+			}
 
-		if err := srv.Shutdown(shutdownCtx); err != nil {
-			return fmt.Errorf("server shutdown error: %w", err)
+			logger.Info("Server shutdown complete")
+			return nil
 		}
+	}
+}
+
+// newHTTPHandler builds the routes served in HTTP mode: the MCP endpoint
+// behind the middleware stack, the health and readiness checks, metrics and
+// OAuth metadata
+func newHTTPHandler(mcpServer *server.MCPServer, logger *log.Logger, cfg config.Config) (http.Handler, error) {
+	endpointPath := cfg.Transport.Endpoint
+	httpConfig := cfg.HTTPConfig()
 
-		logger.Info("Server shutdown complete")
+	// Create HTTP server with streaming support
+	httpServer := server.NewStreamableHTTPServer(mcpServer)
+
+	// Create mux and add routes
+	mux := http.NewServeMux()
+
+	// Health check endpoints
+	mux.HandleFunc("/health", client.HealthHandler(logger))
+	mux.HandleFunc(client.LivenessPath, client.LivenessHandler())
+	readiness := client.NewReadinessChecker(time.Duration(cfg.Transport.ReadyCacheTTL), logger)
+	mux.HandleFunc(client.ReadinessPath, client.ReadinessHandler(readiness))
+
+	// Prometheus metrics
+	mux.Handle(metrics.Path, metrics.Handler())
+
+	// OAuth protected resource metadata
+	if httpConfig.OAuth.Enabled() {
+		metadataHandler := client.ProtectedResourceMetadataHandler(httpConfig.OAuth)
+		mux.HandleFunc(client.ProtectedResourceMetadataPath, metadataHandler)
+		mux.HandleFunc(path.Join(client.ProtectedResourceMetadataPath, endpointPath), metadataHandler)
 	}
 
-	return nil
+	// MCP endpoint
+	mux.Handle(endpointPath, client.BuildMiddlewareStack(httpServer, httpConfig, logger))
+
+	return mux, nil
 }
 
-func runStdioServer(logger *log.Logger) error {
+func runStdioServer(logger *log.Logger, cfg config.Config) error {
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing, version.Version)
+	if err != nil {
+		return err
+	}
+	defer flushTraces(shutdownTracing, logger)
+
+	closeAudit, err := openAuditLog(cfg.Audit, logger)
+	if err != nil {
+		return err
+	}
+	defer closeAudit()
+
 	mcpServer := NewServer(version.Version, logger)
-	tools.InitTools(mcpServer, logger)
+	if err := tools.InitTools(mcpServer, logger, getToolsConfig(cfg)); err != nil {
+		return fmt.Errorf("failed to register tools: %w", err)
+	}
 
 	logger.Info("Starting Zabbix MCP server in stdio mode")
 	return server.ServeStdio(mcpServer)
 }
 
+// flushTraces exports the spans still buffered when the server stops
+func flushTraces(shutdown func(context.Context) error, logger *log.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		logger.WithError(err).Warn("Failed to flush traces")
+	}
+}
+
+// openAuditLog starts recording mutating tool calls when an audit file is
+// configured. The returned function closes the file.
+func openAuditLog(cfg audit.Config, logger *log.Logger) (func(), error) {
+	auditLog, err := audit.Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if auditLog != nil {
+		logger.WithField("file", cfg.File).Info("Recording mutating tool calls to the audit log")
+	}
+	audit.SetLog(auditLog)
+
+	return func() {
+		audit.SetLog(nil)
+		if err := auditLog.Close(); err != nil {
+			logger.WithError(err).Warn("Failed to close the audit log")
+		}
+	}, nil
+}
+
 // NewServer creates a new MCP server instance
 func NewServer(ver string, logger *log.Logger, opts ...server.ServerOption) *server.MCPServer {
 	defaultOpts := []server.ServerOption{
 		server.WithToolCapabilities(true),
+		server.WithHooks(client.SessionHooks(logger)),
+		server.WithElicitation(),
+		server.WithToolFilter(tools.VersionFilter(logger)),
+		server.WithToolHandlerMiddleware(metrics.ToolMiddleware),
+		server.WithToolHandlerMiddleware(tracing.ToolMiddleware),
+		server.WithToolHandlerMiddleware(logging.ToolMiddleware(logger)),
+		server.WithToolHandlerMiddleware(audit.ToolMiddleware(logger, tools.IsReadOnlyTool, client.PrincipalFromContext)),
 	}
 
 	allOpts := append(defaultOpts, opts...)
@@ -139,15 +276,8 @@ func main() {
 		Short: "Start stdio server (default)",
 		Long:  `Start a server that communicates using stdio transport. This is the default mode for MCP clients like Claude for Desktop.`,
 		Run: func(cmd *cobra.Command, _ []string) {
-			logFile, err := rootCmd.PersistentFlags().GetString("log-file")
-			if err != nil {
-				stdlog.Fatal("Failed to get log file:", err)
-			}
-			logger, err := initLogger(logFile)
-			if err != nil {
-				stdlog.Fatal("Failed to initialize logger:", err)
-			}
-			if err := runStdioServer(logger); err != nil {
+			cfg, logger := startup(cmd)
+			if err := runStdioServer(logger, cfg); err != nil {
 				logger.WithError(err).Fatal("Failed to run stdio server")
 			}
 		},
@@ -160,19 +290,8 @@ func main() {
 This mode allows clients to interact with the Zabbix MCP server over HTTP.
 You can specify the host, port, and endpoint path to customize where the server listens.`,
 		Run: func(cmd *cobra.Command, _ []string) {
-			logFile, err := rootCmd.PersistentFlags().GetString("log-file")
-			if err != nil {
-				stdlog.Fatal("Failed to get log file:", err)
-			}
-			logger, err := initLogger(logFile)
-			if err != nil {
-				stdlog.Fatal("Failed to initialize logger:", err)
-			}
-			host, _ := cmd.Flags().GetString("transport-host")
-			port, _ := cmd.Flags().GetString("transport-port")
-			endpointPath := getEndpointPath(cmd)
-
-			if err := runHTTPServer(logger, host, port, endpointPath); err != nil {
+			cfg, logger := startup(cmd)
+			if err := runHTTPServer(logger, cfg); err != nil {
 				logger.WithError(err).Fatal("Failed to run HTTP server")
 			}
 		},
@@ -189,79 +308,214 @@ You can specify the host, port, and endpoint path to customize where the server
 		},
 	}
 
+	authTokenCmd := &cobra.Command{
+		Use:   "auth-token",
+		Short: "Generate an HMAC-signed bearer token",
+		Long: `Generate a bearer token for the streamable-http endpoint, signed with the
+secret from MCP_AUTH_HMAC_SECRET or auth.hmac_secret, or the hmac_secret of
+the auth keys file.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			subject, _ := cmd.Flags().GetString("subject")
+			ttl, _ := cmd.Flags().GetDuration("ttl")
+			if subject == "" {
+				return fmt.Errorf("--subject is required")
+			}
+
+			cfg, err := resolveConfig(cmd)
+			if err != nil {
+				return err
+			}
+			token, err := client.SignBearerToken(cfg.Auth.HMACSecret, subject, ttl)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), token)
+			return nil
+		},
+	}
+	authTokenCmd.Flags().String("subject", "", "Token subject; matches the name of an entry in the auth keys file")
+	authTokenCmd.Flags().Duration("ttl", 24*time.Hour, "Token lifetime")
+
 	// Set default Run for rootCmd
 	rootCmd.Run = func(cmd *cobra.Command, _ []string) {
-		logFile, _ := cmd.PersistentFlags().GetString("log-file")
-		logger, err := initLogger(logFile)
-		if err != nil {
-			stdlog.Fatal("Failed to initialize logger:", err)
-		}
+		cfg, logger := startup(cmd)
 
-		// Check for HTTP mode from environment
-		if shouldUseHTTPMode() {
-			host := getHTTPHost()
-			port := getHTTPPort()
-			endpointPath := getEndpointPath(cmd)
-			if err := runHTTPServer(logger, host, port, endpointPath); err != nil {
+		// The transport mode comes from the environment or config file
+		if cfg.Transport.Mode != "stdio" {
+			if err := runHTTPServer(logger, cfg); err != nil {
 				logger.WithError(err).Fatal("Failed to run HTTP server")
 			}
 			return
 		}
 
 		// Default to stdio mode
-		if err := runStdioServer(logger); err != nil {
+		if err := runStdioServer(logger, cfg); err != nil {
 			logger.WithError(err).Fatal("Failed to run stdio server")
 		}
 	}
 
 	// Add persistent flags
+	rootCmd.PersistentFlags().String("config", "", "Configuration file (.yaml, .yml or .toml)")
 	rootCmd.PersistentFlags().String("log-file", "", "Log file path (defaults to stderr)")
+	rootCmd.PersistentFlags().String("log-format", config.DefaultLogFormat, "Log format (text or json)")
+	rootCmd.PersistentFlags().Bool("read-only", false, "Register only non-mutating tools and reject mutating Zabbix API calls")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Make mutating tools preview their Zabbix API requests without sending them")
+	rootCmd.PersistentFlags().String("toolsets", "", "Comma-separated list of toolsets to enable (default: all)")
+	rootCmd.PersistentFlags().String("enable-tools", "", "Comma-separated list of additional tools to enable")
+	rootCmd.PersistentFlags().String("disable-tools", "", "Comma-separated list of tools to disable")
 
 	// Add commands
 	addCommonFlags(stdioCmd)
 	addCommonFlags(streamableHTTPCmd)
 	addCommonFlags(httpCmd)
+	addHTTPFlags(streamableHTTPCmd)
+	addHTTPFlags(httpCmd)
 
 	rootCmd.AddCommand(stdioCmd)
 	rootCmd.AddCommand(streamableHTTPCmd)
 	rootCmd.AddCommand(httpCmd)
+	rootCmd.AddCommand(authTokenCmd)
+	rootCmd.AddCommand(newListToolsCmd())
+	rootCmd.AddCommand(newConfigCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
 }
 
-// shouldUseHTTPMode checks if environment variables indicate HTTP mode
-func shouldUseHTTPMode() bool {
-	mode := os.Getenv("TRANSPORT_MODE")
-	return strings.ToLower(mode) == "http" || strings.ToLower(mode) == "streamable-http"
+// startup loads the configuration and creates the logger, exiting on
+// invalid settings
+func startup(cmd *cobra.Command) (config.Config, *log.Logger) {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		stdlog.Fatal("Invalid configuration: ", err)
+	}
+	logger, err := initLogger(cfg.Logging)
+	if err != nil {
+		stdlog.Fatal("Failed to initialize logger:", err)
+	}
+	return cfg, logger
 }
 
-// getHTTPPort returns the port from environment variables or default
-func getHTTPPort() string {
-	if port := os.Getenv("TRANSPORT_PORT"); port != "" {
-		return port
+// resolveConfig builds the configuration from flags, environment, the
+// configuration file and defaults, in that order of precedence
+func resolveConfig(cmd *cobra.Command) (config.Config, error) {
+	file := os.Getenv(config.ConfigFile)
+	if cmd != nil {
+		if flag, _ := cmd.Flags().GetString("config"); flag != "" {
+			file = flag
+		}
 	}
-	return DefaultBindPort
+
+	cfg := config.Default()
+	if file != "" {
+		var err error
+		if cfg, err = config.Load(file); err != nil {
+			return cfg, err
+		}
+	}
+	if err := cfg.ApplyEnv(); err != nil {
+		return cfg, err
+	}
+	if err := applyFlags(cmd, &cfg); err != nil {
+		return cfg, err
+	}
+	if err := cfg.LoadFiles(); err != nil {
+		return cfg, err
+	}
+	// Modes and levels are case-insensitive, e.g. LOG_LEVEL=INFO
+	cfg.Transport.Mode = strings.ToLower(cfg.Transport.Mode)
+	cfg.Logging.Level = strings.ToLower(cfg.Logging.Level)
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+	cfg.Transport.Endpoint = path.Clean("/" + cfg.Transport.Endpoint)
+	return cfg, nil
 }
 
-// getHTTPHost returns the host from environment variables or default
-func getHTTPHost() string {
-	if host := os.Getenv("TRANSPORT_HOST"); host != "" {
-		return host
+// loadConfig resolves the configuration and applies the Zabbix connection,
+// read-only mode and the named Zabbix instances to every session
+func loadConfig(cmd *cobra.Command) (config.Config, error) {
+	cfg, err := resolveConfig(cmd)
+	if err != nil {
+		return cfg, err
 	}
-	return DefaultBindAddress
+	connection := cfg.ConnectionConfig()
+	client.SetConnection(&connection)
+	client.SetReadOnly(cfg.Tools.ReadOnly)
+	client.SetInstances(cfg.InstancesConfig())
+	return cfg, nil
 }
 
-// getEndpointPath returns the endpoint path from flag, environment, or default
-func getEndpointPath(cmd *cobra.Command) string {
-	if cmd != nil {
-		if endpoint, _ := cmd.Flags().GetString("endpoint"); endpoint != "" {
-			return path.Clean("/" + endpoint)
+// applyFlags overrides cfg with the flags given on the command line
+func applyFlags(cmd *cobra.Command, cfg *config.Config) error {
+	if cmd == nil {
+		return nil
+	}
+	flags := cmd.Flags()
+
+	if flags.Changed("transport-host") {
+		cfg.Transport.Host, _ = flags.GetString("transport-host")
+	}
+	if flags.Changed("transport-port") {
+		value, _ := flags.GetString("transport-port")
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid --transport-port %q", value)
 		}
+		cfg.Transport.Port = port
+	}
+	if flags.Changed("endpoint") {
+		cfg.Transport.Endpoint, _ = flags.GetString("endpoint")
 	}
-	if endpoint := os.Getenv("MCP_ENDPOINT"); endpoint != "" {
-		return path.Clean("/" + endpoint)
+	if flags.Changed("session-idle-ttl") {
+		ttl, _ := flags.GetDuration("session-idle-ttl")
+		cfg.Transport.SessionIdleTTL = config.Duration(ttl)
+	}
+	for flag, setting := range map[string]*string{
+		"tls-cert":      &cfg.Transport.TLS.CertFile,
+		"tls-key":       &cfg.Transport.TLS.KeyFile,
+		"tls-client-ca": &cfg.Transport.TLS.ClientCAFile,
+	} {
+		if flags.Changed(flag) {
+			*setting, _ = flags.GetString(flag)
+		}
+	}
+	if flags.Changed("log-file") {
+		cfg.Logging.File, _ = flags.GetString("log-file")
+	}
+	if flags.Changed("log-format") {
+		cfg.Logging.Format, _ = flags.GetString("log-format")
+	}
+	if flags.Changed("read-only") {
+		cfg.Tools.ReadOnly, _ = flags.GetBool("read-only")
+	}
+	if flags.Changed("dry-run") {
+		cfg.Tools.DryRun, _ = flags.GetBool("dry-run")
+	}
+	for flag, setting := range map[string]*[]string{
+		"toolsets":      &cfg.Tools.Toolsets,
+		"enable-tools":  &cfg.Tools.EnableTools,
+		"disable-tools": &cfg.Tools.DisableTools,
+	} {
+		if flags.Changed(flag) {
+			value, _ := flags.GetString(flag)
+			*setting = utils.SplitAndTrim(value)
+		}
+	}
+	return nil
+}
+
+// getToolsConfig returns the tool registration settings of cfg
+func getToolsConfig(cfg config.Config) tools.Config {
+	return tools.Config{
+		ReadOnly:     cfg.Tools.ReadOnly,
+		DryRun:       cfg.Tools.DryRun,
+		Toolsets:     cfg.Tools.Toolsets,
+		EnableTools:  cfg.Tools.EnableTools,
+		DisableTools: cfg.Tools.DisableTools,
+		Instances:    cfg.InstancesConfig().Names(),
 	}
-	return DefaultEndPointPath
 }
@@ -0,0 +1,244 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package audit records every mutating tool call to an append-only JSONL
+// file: who issued it, with which arguments, which Zabbix methods it called
+// and what they returned. Zabbix's own audit log only knows the API user
+// the server connects as.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+)
+
+// Audit environment variables
+const (
+	AuditFile       = "MCP_AUDIT_FILE"
+	AuditMaxSizeMB  = "MCP_AUDIT_MAX_SIZE_MB"
+	AuditMaxBackups = "MCP_AUDIT_MAX_BACKUPS"
+)
+
+// Rotation defaults
+const (
+	DefaultMaxSizeMB  = 100
+	DefaultMaxBackups = 10
+)
+
+// Record outcomes
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+)
+
+// maxErrorLength bounds the error message kept in a record
+const maxErrorLength = 1024
+
+// Config selects the audit file and its rotation
+type Config struct {
+	// File is the JSONL audit file; auditing is disabled when empty
+	File string `yaml:"file" toml:"file"`
+	// MaxSizeMB rotates the file when it would grow beyond this size; zero
+	// disables rotation
+	MaxSizeMB int `yaml:"max_size_mb" toml:"max_size_mb"`
+	// MaxBackups is the number of rotated files kept; zero keeps them all
+	MaxBackups int `yaml:"max_backups" toml:"max_backups"`
+}
+
+// Validate checks the rotation settings
+func (c Config) Validate() error {
+	if c.MaxSizeMB < 0 {
+		return fmt.Errorf("max_size_mb must not be negative")
+	}
+	if c.MaxBackups < 0 {
+		return fmt.Errorf("max_backups must not be negative")
+	}
+	return nil
+}
+
+// Record is one audited tool call
+type Record struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"request_id,omitempty"`
+	SessionID string    `json:"session_id,omitempty"`
+	// Principal is the authenticated HTTP principal, empty in stdio mode
+	// and without HTTP authentication
+	Principal string `json:"principal,omitempty"`
+	Tool      string `json:"tool"`
+	// Arguments are the tool arguments with secrets redacted
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Calls     []Call                 `json:"zabbix_calls,omitempty"`
+	// DryRun marks previews that did not change Zabbix
+	DryRun     bool   `json:"dry_run,omitempty"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Call is a mutating Zabbix API call made by an audited tool call
+type Call struct {
+	Method   string `json:"method"`
+	Instance string `json:"instance,omitempty"`
+	// IDs are the ID lists of the result, e.g. {"hostids": ["10084"]}
+	IDs   map[string][]string `json:"ids,omitempty"`
+	Error string              `json:"error,omitempty"`
+}
+
+// current is the audit log of the server, nil when auditing is disabled
+var current atomic.Pointer[Log]
+
+// SetLog makes l the audit log of every tool call; nil disables auditing
+func SetLog(l *Log) {
+	current.Store(l)
+}
+
+// CurrentLog returns the audit log, nil when auditing is disabled
+func CurrentLog() *Log {
+	return current.Load()
+}
+
+type contextKey struct{}
+
+// recorder collects the Zabbix calls of one tool call. Federated tools call
+// several instances at once.
+type recorder struct {
+	mu     sync.Mutex
+	calls  []Call
+	dryRun bool
+}
+
+// MarkDryRun marks the audit record of the tool call running in ctx, if
+// any, as a dry run
+func MarkDryRun(ctx context.Context) {
+	if rec, ok := ctx.Value(contextKey{}).(*recorder); ok {
+		rec.mu.Lock()
+		rec.dryRun = true
+		rec.mu.Unlock()
+	}
+}
+
+// RecordCall adds a mutating Zabbix call to the audit record of the tool
+// call running in ctx, if any
+func RecordCall(ctx context.Context, method, instance string, result json.RawMessage, err error) {
+	rec, ok := ctx.Value(contextKey{}).(*recorder)
+	if !ok {
+		return
+	}
+
+	call := Call{Method: method, Instance: instance}
+	if err != nil {
+		call.Error = truncate(err.Error())
+	} else {
+		call.IDs = resultIDs(result)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.calls = append(rec.calls, call)
+}
+
+// resultIDs returns the ID lists of a create, update or delete result
+func resultIDs(result json.RawMessage) map[string][]string {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(result, &fields) != nil {
+		return nil
+	}
+
+	ids := make(map[string][]string)
+	for key, value := range fields {
+		if !strings.HasSuffix(key, "ids") {
+			continue
+		}
+		var list []interface{}
+		if json.Unmarshal(value, &list) != nil {
+			continue
+		}
+		for _, id := range list {
+			ids[key] = append(ids[key], fmt.Sprint(id))
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return ids
+}
+
+// ToolMiddleware writes an audit record for every call of a tool that
+// readOnly does not report as read-only. principal returns the
+// authenticated principal of a call. Failing to write the record is logged
+// but does not fail the call, as the change has already been made.
+func ToolMiddleware(logger *log.Logger, readOnly func(tool string) bool, principal func(context.Context) string) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			auditLog := CurrentLog()
+			if auditLog == nil || readOnly(request.Params.Name) {
+				return next(ctx, request)
+			}
+
+			rec := &recorder{}
+			start := time.Now()
+			result, err := next(context.WithValue(ctx, contextKey{}, rec), request)
+
+			record := Record{
+				Time:       start.UTC(),
+				RequestID:  logging.RequestIDFromContext(ctx),
+				Principal:  principal(ctx),
+				Tool:       request.Params.Name,
+				Status:     StatusSuccess,
+				DurationMS: time.Since(start).Milliseconds(),
+			}
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				record.SessionID = session.SessionID()
+			}
+			if args := request.GetArguments(); len(args) > 0 {
+				record.Arguments = logging.RedactArguments(request.Params.Name, args)
+			}
+			rec.mu.Lock()
+			record.Calls = rec.calls
+			record.DryRun = rec.dryRun
+			rec.mu.Unlock()
+
+			switch {
+			case err != nil:
+				record.Status = StatusFailure
+				record.Error = truncate(err.Error())
+			case result != nil && result.IsError:
+				record.Status = StatusFailure
+				record.Error = truncate(resultText(result))
+			}
+
+			if writeErr := auditLog.Write(record); writeErr != nil {
+				logging.FromContext(ctx, logger).WithError(writeErr).Error("Failed to write audit record")
+			}
+			return result, err
+		}
+	}
+}
+
+// resultText returns the text content of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func truncate(s string) string {
+	if len(s) > maxErrorLength {
+		return s[:maxErrorLength] + "..."
+	}
+	return s
+}
@@ -0,0 +1,300 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package audit_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/vfcastr/Zabbix-MCP/pkg/audit"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/hostgroups"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/macros"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+// openLog enables auditing to a temporary file for the test
+func openLog(t *testing.T, config audit.Config) (*audit.Log, string) {
+	t.Helper()
+	config.File = filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := audit.Open(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit.SetLog(auditLog)
+	t.Cleanup(func() {
+		audit.SetLog(nil)
+		auditLog.Close()
+	})
+	return auditLog, config.File
+}
+
+// audited wraps tool in the audit middleware as the server does
+func audited(tool server.ServerTool) server.ServerTool {
+	tool.Handler = audit.ToolMiddleware(zabbixtest.Logger(), tools.IsReadOnlyTool, client.PrincipalFromContext)(tool.Handler)
+	return tool
+}
+
+func TestToolMiddlewareRecordsMutatingCalls(t *testing.T) {
+	auditLog, file := openLog(t, audit.Config{})
+	s := zabbixtest.NewServer(t)
+	session := zabbixtest.NewSession()
+	ctx := logging.WithRequestID(client.WithPrincipal(s.SessionContext(t, session), "alice"), "req-7")
+	client.GetZabbixClient(session.ID).Principal = "alice"
+
+	s.CallTool(t, ctx, audited(macros.CreateUserMacro(zabbixtest.Logger())), map[string]interface{}{
+		"hostid": "10084",
+		"macro":  "{$DB.PASSWORD}",
+		"value":  "s3cret",
+		"type":   float64(1),
+	})
+	s.Fail("hostgroup.create", zabbixtest.Error{Code: zabbixtest.CodeInvalidParams, Message: "Invalid params.", Data: "Host group already exists."}, 1)
+	s.CallTool(t, ctx, audited(hostgroups.CreateHostGroup(zabbixtest.Logger())), map[string]interface{}{"name": "Linux servers"})
+	s.CallTool(t, ctx, audited(hostgroups.GetHostGroups(zabbixtest.Logger())), map[string]interface{}{})
+
+	records, err := auditLog.Query(audit.Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected the two mutating calls to be audited, got %+v", records)
+	}
+
+	failed, created := records[0], records[1]
+	if created.Tool != "create_user_macro" || created.Status != audit.StatusSuccess || created.SessionID != session.ID ||
+		created.Principal != "alice" || created.RequestID != "req-7" {
+		t.Errorf("unexpected record: %+v", created)
+	}
+	if created.Arguments["value"] != logging.Redacted || created.Arguments["hostid"] != "10084" {
+		t.Errorf("expected the secret macro value to be redacted, got %v", created.Arguments)
+	}
+	if len(created.Calls) != 1 || created.Calls[0].Method != "usermacro.create" || len(created.Calls[0].IDs["hostmacroids"]) != 1 {
+		t.Errorf("expected the usermacro.create call with its ID, got %+v", created.Calls)
+	}
+
+	if failed.Tool != "zabbix_create_host_group" || failed.Status != audit.StatusFailure || !strings.Contains(failed.Error, "already exists") {
+		t.Errorf("unexpected record: %+v", failed)
+	}
+	if len(failed.Calls) != 1 || failed.Calls[0].Method != "hostgroup.create" || failed.Calls[0].Error == "" {
+		t.Errorf("expected the failed hostgroup.create call, got %+v", failed.Calls)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Error("the secret macro value was written to the audit file")
+	}
+}
+
+func TestToolMiddlewareRedactsValueOnlyMacroUpdates(t *testing.T) {
+	auditLog, file := openLog(t, audit.Config{})
+	s := zabbixtest.NewServer(t)
+	s.Model.Add(zabbixtest.UserMacros, zabbixtest.Object{"hostmacroid": "30", "hostid": "10084", "macro": "{$DB.PASSWORD}", "type": "1"})
+
+	// The update names neither the macro nor its type
+	s.CallTool(t, s.Context(t), audited(macros.UpdateUserMacro(zabbixtest.Logger())), map[string]interface{}{
+		"hostmacroid": "30",
+		"value":       "rotated-secret",
+	})
+
+	records, err := auditLog.Query(audit.Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Arguments["value"] != logging.Redacted || records[0].Arguments["hostmacroid"] != "30" {
+		t.Fatalf("expected the macro value to be redacted, got %+v", records)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "rotated-secret") {
+		t.Error("the secret macro value was written to the audit file")
+	}
+}
+
+func TestToolMiddlewareWithoutLog(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	result := s.CallTool(t, s.Context(t), audited(hostgroups.CreateHostGroup(zabbixtest.Logger())), map[string]interface{}{"name": "Linux servers"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", zabbixtest.ResultText(result))
+	}
+}
+
+func TestLogRotation(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "audit.jsonl")
+	auditLog, err := audit.Open(audit.Config{File: file, MaxSizeMB: 1, MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer auditLog.Close()
+
+	// Records of about 100 KB fill a 1 MB file with ten
+	padding := strings.Repeat("x", 100<<10)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 45; i++ {
+		record := audit.Record{
+			Time:      start.Add(time.Duration(i) * time.Minute),
+			SessionID: fmt.Sprintf("session-%d", i%2),
+			Tool:      "update_host",
+			Status:    audit.StatusSuccess,
+			Arguments: map[string]interface{}{"description": padding},
+		}
+		if err := auditLog.Write(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matches, _ := filepath.Glob(file + ".*")
+	if len(matches) != 2 {
+		t.Errorf("expected two rotated files, got %v", matches)
+	}
+	for _, path := range append(matches, file) {
+		if info, err := os.Stat(path); err != nil || info.Size() > 1<<20 {
+			t.Errorf("%s exceeds the maximum size: %v", path, err)
+		}
+	}
+
+	// Queries span the rotated files and return the newest records first
+	records, err := auditLog.Query(audit.Query{SessionID: "session-0", From: start.Add(10 * time.Minute), Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var times []int
+	for _, record := range records {
+		times = append(times, int(record.Time.Sub(start).Minutes()))
+	}
+	if fmt.Sprint(times) != "[44 42 40]" {
+		t.Errorf("unexpected records: %v", times)
+	}
+
+	all, err := auditLog.Query(audit.Query{Till: start.Add(30 * time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first twenty records were removed with the oldest rotated files
+	if len(all) != 11 || !all[len(all)-1].Time.Equal(start.Add(20*time.Minute)) {
+		t.Errorf("expected only the records of the kept files, got %d", len(all))
+	}
+}
+
+func TestLogRotationFailure(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := audit.Open(audit.Config{File: file, MaxSizeMB: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer auditLog.Close()
+
+	padding := strings.Repeat("x", 600<<10)
+	record := audit.Record{Time: time.Now(), SessionID: "session-1", Tool: "update_host", Status: audit.StatusSuccess, Arguments: map[string]interface{}{"description": padding}}
+	if err := auditLog.Write(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// The rename fails once the file is gone, and the log starts a new one
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	record.SessionID = "session-2"
+	if err := auditLog.Write(record); err == nil || !strings.Contains(err.Error(), "failed to rotate") {
+		t.Errorf("expected a rotation error, got %v", err)
+	}
+	record.SessionID = "session-3"
+	if err := auditLog.Write(record); err != nil {
+		t.Fatalf("expected writes to resume, got %v", err)
+	}
+
+	records, err := auditLog.Query(audit.Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].SessionID != "session-3" || records[1].SessionID != "session-2" {
+		t.Errorf("expected the records written after the failure, got %d", len(records))
+	}
+}
+
+func TestQueryDuringRotation(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := audit.Open(audit.Config{File: file, MaxSizeMB: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer auditLog.Close()
+
+	// Records of about 50 KB rotate the file every twenty writes
+	padding := strings.Repeat("x", 50<<10)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	const total = 200
+	var written atomic.Int64
+	done := make(chan error)
+	go func() {
+		for i := 0; i < total; i++ {
+			record := audit.Record{Time: start.Add(time.Duration(i) * time.Minute), Tool: "update_host", Status: audit.StatusSuccess, Arguments: map[string]interface{}{"description": padding}}
+			if err := auditLog.Write(record); err != nil {
+				done <- err
+				return
+			}
+			written.Add(1)
+		}
+		done <- nil
+	}()
+
+	// Queries running while the file rotates see every record written
+	// before they started, once each and in order
+	for running := true; running; {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+			running = false
+		default:
+		}
+		before := int(written.Load())
+		records, err := auditLog.Query(audit.Query{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) < before {
+			t.Fatalf("expected at least %d records, got %d", before, len(records))
+		}
+		for i, record := range records {
+			if want := start.Add(time.Duration(len(records)-1-i) * time.Minute); !record.Time.Equal(want) {
+				t.Fatalf("record %d of %d: expected %v, got %v", i, len(records), want, record.Time)
+			}
+		}
+	}
+
+	records, err := auditLog.Query(audit.Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != total {
+		t.Errorf("expected %d records, got %d", total, len(records))
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	for _, config := range []audit.Config{{MaxSizeMB: -1}, {MaxBackups: -1}} {
+		if err := config.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", config)
+		}
+	}
+	if auditLog, err := audit.Open(audit.Config{}); auditLog != nil || err != nil {
+		t.Errorf("expected auditing to be disabled without a file, got %v %v", auditLog, err)
+	}
+}
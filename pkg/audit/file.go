@@ -0,0 +1,320 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat names rotated files, e.g. audit.jsonl.20250102T150405.000000.
+// Names sort in rotation order.
+const backupTimeFormat = "20060102T150405.000000"
+
+// Log is an append-only JSONL audit file that is rotated by size
+type Log struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	closed bool
+}
+
+// Open opens the audit file of config for appending. It returns nil when
+// auditing is disabled.
+func Open(config Config) (*Log, error) {
+	if config.File == "" {
+		return nil, nil
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	l := &Log{
+		path:       config.File,
+		maxSize:    int64(config.MaxSizeMB) << 20,
+		maxBackups: config.MaxBackups,
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Log) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// Write appends record as one line, rotating the file first when the line
+// would take it beyond the maximum size
+func (l *Log) Write(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return fmt.Errorf("audit file is closed")
+	}
+	// A failed rotation may have left no file open
+	if l.file == nil {
+		if err := l.open(); err != nil {
+			return err
+		}
+	}
+
+	var rotateErr error
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(data)) > l.maxSize {
+		// The record is still written when only the rotation failed
+		if rotateErr = l.rotate(); l.file == nil {
+			return rotateErr
+		}
+	}
+
+	n, err := l.file.Write(data)
+	l.size += int64(n)
+	return errors.Join(rotateErr, err)
+}
+
+// rotate renames the current file after the rotation time, removes the
+// oldest backups beyond the limit and starts a new file. When the rename
+// fails the current file is reopened and rotation is retried on the next
+// write. Queries do not hold up rotation, as they keep reading the files
+// they opened after those are renamed or removed.
+func (l *Log) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit file: %w", err)
+	}
+	l.file = nil
+
+	backup := l.path + "." + time.Now().UTC().Format(backupTimeFormat)
+	if err := os.Rename(l.path, backup); err != nil {
+		return errors.Join(fmt.Errorf("failed to rotate audit file: %w", err), l.open())
+	}
+	if err := l.open(); err != nil {
+		return err
+	}
+
+	if l.maxBackups > 0 {
+		backups, err := l.backups()
+		if err != nil {
+			return err
+		}
+		for len(backups) > l.maxBackups {
+			if err := os.Remove(backups[0]); err != nil {
+				return fmt.Errorf("failed to remove old audit file: %w", err)
+			}
+			backups = backups[1:]
+		}
+	}
+	return nil
+}
+
+// backups returns the rotated files, oldest first
+func (l *Log) backups() ([]string, error) {
+	matches, err := filepath.Glob(l.path + ".*")
+	if err != nil {
+		return nil, err
+	}
+	backups := matches[:0]
+	for _, match := range matches {
+		if _, err := time.Parse(backupTimeFormat, match[len(l.path)+1:]); err == nil {
+			backups = append(backups, match)
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// Close closes the audit file. It is safe to call on a nil Log.
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// Query selects audit records. Empty fields match every record.
+type Query struct {
+	SessionID string
+	Principal string
+	Tool      string
+	Status    string
+	From      time.Time
+	Till      time.Time
+	// Limit is the maximum number of records returned
+	Limit int
+}
+
+func (q Query) matches(record Record) bool {
+	switch {
+	case q.SessionID != "" && record.SessionID != q.SessionID,
+		q.Principal != "" && record.Principal != q.Principal,
+		q.Tool != "" && record.Tool != q.Tool,
+		q.Status != "" && record.Status != q.Status,
+		!q.From.IsZero() && record.Time.Before(q.From),
+		!q.Till.IsZero() && record.Time.After(q.Till):
+		return false
+	}
+	return true
+}
+
+// Query returns the newest records matching q, newest first, searching the
+// rotated files as well. Lines that cannot be decoded are skipped.
+func (l *Log) Query(q Query) ([]Record, error) {
+	files, err := l.openFiles()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+
+	var matched []Record
+	for _, file := range files {
+		if err := scan(file, func(record Record) {
+			if !q.matches(record) {
+				return
+			}
+			matched = append(matched, record)
+			// Only the newest records are returned
+			if q.Limit > 0 && len(matched) > 2*q.Limit {
+				matched = append(matched[:0], matched[len(matched)-q.Limit:]...)
+			}
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[len(matched)-q.Limit:]
+	}
+	for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+		matched[i], matched[j] = matched[j], matched[i]
+	}
+	return matched, nil
+}
+
+// openFiles opens the rotated files, oldest first, and the current file.
+// The current file is opened first: when it is rotated before the backups
+// are listed, it is read in its place among them and the files started
+// since are left out, as are backups removed in the meantime.
+func (l *Log) openFiles() ([]*os.File, error) {
+	current, err := openFile(l.path)
+	if err != nil {
+		return nil, err
+	}
+	var currentInfo os.FileInfo
+	if current != nil {
+		if currentInfo, err = current.Stat(); err != nil {
+			current.Close()
+			return nil, fmt.Errorf("failed to read audit file: %w", err)
+		}
+	}
+
+	var files []*os.File
+	closeAll := func() {
+		for _, file := range append(files, current) {
+			if file != nil {
+				file.Close()
+			}
+		}
+	}
+
+	backups, err := l.backups()
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+	for _, path := range backups {
+		file, err := openFile(path)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		if file == nil {
+			continue
+		}
+		files = append(files, file)
+		if info, err := file.Stat(); err == nil && currentInfo != nil && os.SameFile(info, currentInfo) {
+			current.Close()
+			current = nil
+			break
+		}
+	}
+
+	if current != nil {
+		files = append(files, current)
+	}
+	return files, nil
+}
+
+// openFile opens the audit file at path for reading. It returns nil when
+// the file does not exist.
+func openFile(path string) (*os.File, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit file: %w", err)
+	}
+	return file, nil
+}
+
+// scan calls fn with every record of file
+func scan(file *os.File, fn func(Record)) error {
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var record Record
+			if json.Unmarshal(line, &record) == nil {
+				fn(record)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read audit file: %w", err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// UserLoginParams represents parameters for user.login API call
+type UserLoginParams struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Login authenticates with user.login and caches the resulting session ID
+func (c *ZabbixClient) Login(ctx context.Context) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	return c.loginLocked(ctx)
+}
+
+// loginLocked performs user.login; the caller must hold authMu
+func (c *ZabbixClient) loginLocked(ctx context.Context) error {
+	if c.Username == "" || c.Password == "" {
+		return fmt.Errorf("zabbix username and password are required for user.login")
+	}
+
+	// user.login must be sent without an Authorization header
+	result, err := c.callWithRetry(ctx, "user.login", UserLoginParams{
+		Username: c.Username,
+		Password: c.Password,
+	}, "")
+	if err != nil {
+		return fmt.Errorf("user.login failed: %w", err)
+	}
+
+	var sessionID string
+	if err := json.Unmarshal(result, &sessionID); err != nil {
+		return fmt.Errorf("failed to parse user.login response: %w", err)
+	}
+
+	c.sessionID = sessionID
+	c.Logger.WithFields(log.Fields{
+		"zabbix_url": c.URL,
+		"username":   c.Username,
+	}).Debug("Logged in to Zabbix")
+
+	return nil
+}
+
+// Logout ends the Zabbix session created by Login. It is a no-op for clients
+// that authenticate with an API token.
+func (c *ZabbixClient) Logout(ctx context.Context) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.sessionID == "" {
+		return nil
+	}
+
+	_, err := c.callWithRetry(ctx, "user.logout", []string{}, c.sessionID)
+	c.sessionID = ""
+	if err != nil {
+		return fmt.Errorf("user.logout failed: %w", err)
+	}
+
+	return nil
+}
+
+// relogin logs in again unless another caller already replaced the session
+// that failed
+func (c *ZabbixClient) relogin(ctx context.Context, failedSession string) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.sessionID != failedSession {
+		return nil
+	}
+
+	return c.loginLocked(ctx)
+}
+
+// authToken returns the credential to send in the Authorization header
+func (c *ZabbixClient) authToken() string {
+	if c.AuthToken != "" {
+		return c.AuthToken
+	}
+
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	return c.sessionID
+}
+
+// canRelogin reports whether the client authenticates with user/password
+func (c *ZabbixClient) canRelogin() bool {
+	return c.AuthToken == "" && c.Username != "" && c.Password != ""
+}
+
+// isSessionExpired reports whether err is Zabbix rejecting the session
+func isSessionExpired(err error) bool {
+	var zerr *ZabbixError
+	if !errors.As(err, &zerr) {
+		return false
+	}
+
+	text := strings.ToLower(zerr.Message + " " + zerr.Data)
+	return strings.Contains(text, "session terminated") ||
+		strings.Contains(text, "not authorised") ||
+		strings.Contains(text, "not authorized")
+}
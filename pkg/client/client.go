@@ -6,16 +6,27 @@ package client
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
-	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
+	"github.com/vfcastr/Zabbix-MCP/pkg/audit"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/metrics"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 )
 
 var (
@@ -28,21 +39,146 @@ const (
 	ZabbixUser          = "ZABBIX_USER"
 	ZabbixPassword      = "ZABBIX_PASSWORD"
 	ZabbixSkipTLSVerify = "ZABBIX_SKIP_VERIFY"
+	ZabbixTimeout       = "ZABBIX_TIMEOUT"
+	ZabbixMaxRetries    = "ZABBIX_MAX_RETRIES"
+	ZabbixReadOnly      = "ZABBIX_MCP_READ_ONLY"
 	ZabbixHeaderToken   = "X-Zabbix-Token"
 	ZabbixHeaderURL     = "X-Zabbix-URL"
 )
 
 const DefaultZabbixURL = "http://127.0.0.1/api_jsonrpc.php"
 
+const (
+	// DefaultCallTimeout bounds a single Zabbix API call, including retries
+	DefaultCallTimeout = 30 * time.Second
+	// DefaultMaxRetries is the number of retries for idempotent methods
+	DefaultMaxRetries = 3
+
+	retryBaseDelay = 200 * time.Millisecond
+	retryMaxDelay  = 5 * time.Second
+)
+
+// requestID is shared by all clients so JSON-RPC IDs are unique per process
+var requestID atomic.Int64
+
+// readOnly rejects mutating API methods for every client when set
+var readOnly atomic.Bool
+
+// SetReadOnly enables or disables read-only enforcement for all clients
+func SetReadOnly(enabled bool) {
+	readOnly.Store(enabled)
+}
+
+// IsReadOnly reports whether read-only enforcement is enabled
+func IsReadOnly() bool {
+	return readOnly.Load()
+}
+
+// ConnectionConfig is the default Zabbix connection and the settings every
+// Zabbix client uses
+type ConnectionConfig struct {
+	URL      string
+	Token    string
+	User     string
+	Password string
+	TLS      TLSConfig
+	// Timeout bounds a call including retries; MaxRetries is the number of
+	// retries of idempotent methods
+	Timeout    time.Duration
+	MaxRetries int
+	// MaxConnsPerHost and MaxIdleConnsPerHost size the connection pool to
+	// each Zabbix server; zero MaxConnsPerHost means no limit
+	MaxConnsPerHost     int
+	MaxIdleConnsPerHost int
+	// FederationConcurrency is the number of instances a federated query
+	// calls at once
+	FederationConcurrency int
+	// RPS and Burst limit the calls to each Zabbix URL; a non-positive RPS
+	// disables the limit
+	RPS   float64
+	Burst int
+}
+
+// DefaultConnectionConfig holds the connection settings used until
+// SetConnection is called. The server resolves the actual settings from
+// flags, environment and configuration file in pkg/config.
+var DefaultConnectionConfig = ConnectionConfig{
+	URL:                   DefaultZabbixURL,
+	Timeout:               DefaultCallTimeout,
+	MaxRetries:            DefaultMaxRetries,
+	MaxIdleConnsPerHost:   DefaultMaxIdleConnsPerHost,
+	FederationConcurrency: DefaultFederationConcurrency,
+	RPS:                   DefaultRateLimitConfig.ZabbixRPS,
+	Burst:                 DefaultRateLimitConfig.ZabbixBurst,
+}
+
+// connection holds the settings passed to SetConnection
+var connection atomic.Pointer[ConnectionConfig]
+
+// SetConnection replaces the connection settings used by new Zabbix
+// clients. A nil config restores the defaults.
+func SetConnection(config *ConnectionConfig) {
+	connection.Store(config)
+}
+
+// Connection returns the settings passed to SetConnection, or the defaults
+// when none were set
+func Connection() ConnectionConfig {
+	if config := connection.Load(); config != nil {
+		return *config
+	}
+	return DefaultConnectionConfig
+}
+
+// outboundLimiters holds one *rate.Limiter per Zabbix URL
+var outboundLimiters sync.Map
+
+// outboundLimiter returns the shared outbound limiter for a Zabbix URL, or
+// nil when outbound limiting is disabled
+func outboundLimiter(zabbixURL string, config ConnectionConfig) *rate.Limiter {
+	if config.RPS <= 0 {
+		return nil
+	}
+	limiter, _ := outboundLimiters.LoadOrStore(zabbixURL, rate.NewLimiter(rate.Limit(config.RPS), config.Burst))
+	return limiter.(*rate.Limiter)
+}
+
 // contextKey is a type alias to avoid lint warnings
 type contextKey string
 
-// ZabbixClient represents a client for the Zabbix API 7.0
+// ZabbixClient represents a client for the Zabbix API
 type ZabbixClient struct {
 	URL        string
 	AuthToken  string
 	HTTPClient *http.Client
 	Logger     *log.Logger
+
+	// Version is the Zabbix server version detected when the client was
+	// created. It is zero when detection failed.
+	Version Version
+
+	// Timeout bounds each call made through CallContext. Zero disables it.
+	Timeout time.Duration
+	// MaxRetries is the number of retries for idempotent (*.get) methods
+	MaxRetries int
+	// Limiter throttles outbound calls; it is shared by clients of one URL
+	Limiter *rate.Limiter
+
+	// Principal is the authenticated HTTP principal that owns this client
+	Principal string
+	// Instance is the name of the Zabbix instance, empty for the session's
+	// default connection
+	Instance string
+
+	// Username and Password are used for user.login when no API token is set
+	Username string
+	Password string
+
+	authMu    sync.Mutex
+	sessionID string
+
+	// lastUsed is the unix nano timestamp of the last session lookup
+	lastUsed atomic.Int64
 }
 
 // ZabbixRequest represents a JSON-RPC request to the Zabbix API
@@ -50,7 +186,9 @@ type ZabbixRequest struct {
 	JSONRPC string      `json:"jsonrpc"`
 	Method  string      `json:"method"`
 	Params  interface{} `json:"params"`
-	ID      int         `json:"id"`
+	ID      int64       `json:"id"`
+	// Auth carries the credential for Zabbix versions before 6.4
+	Auth string `json:"auth,omitempty"`
 }
 
 // ZabbixResponse represents a JSON-RPC response from the Zabbix API
@@ -58,7 +196,7 @@ type ZabbixResponse struct {
 	JSONRPC string          `json:"jsonrpc"`
 	Result  json.RawMessage `json:"result,omitempty"`
 	Error   *ZabbixError    `json:"error,omitempty"`
-	ID      int             `json:"id"`
+	ID      int64           `json:"id"`
 }
 
 // ZabbixError represents an error from the Zabbix API
@@ -72,39 +210,80 @@ func (e *ZabbixError) Error() string {
 	return fmt.Sprintf("Zabbix API error %d: %s - %s", e.Code, e.Message, e.Data)
 }
 
-// getEnv retrieves the value of an environment variable or returns a fallback value
-func getEnv(key, fallback string) string {
-	if value, ok := os.LookupEnv(key); ok {
-		return value
+// NewZabbixClient creates a new Zabbix client for the given session
+func NewZabbixClient(ctx context.Context, sessionId string, zabbixURL string, tlsConfig TLSConfig, authToken string, logger *log.Logger) (*ZabbixClient, error) {
+	client, err := newZabbixClient(zabbixURL, tlsConfig, logger)
+	if err != nil {
+		return nil, err
 	}
-	return fallback
+	client.AuthToken = authToken
+	client.detectVersion(ctx)
+
+	// Store client for session
+	activeClients.Store(sessionId, client)
+
+	return client, nil
 }
 
-// NewZabbixClient creates a new Zabbix client for the given session
-func NewZabbixClient(sessionId string, zabbixURL string, skipTLSVerify bool, authToken string, logger *log.Logger) (*ZabbixClient, error) {
-	// Create HTTP client with optional TLS skip
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: skipTLSVerify},
+// NewZabbixClientWithLogin creates a new Zabbix client for the given session
+// that authenticates with user.login instead of an API token
+func NewZabbixClientWithLogin(ctx context.Context, sessionId string, zabbixURL string, tlsConfig TLSConfig, username string, password string, logger *log.Logger) (*ZabbixClient, error) {
+	client, err := newZabbixClient(zabbixURL, tlsConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+	client.Username = username
+	client.Password = password
+
+	// The version decides how the session ID is sent, so detect it first
+	client.detectVersion(ctx)
+	if err := client.Login(ctx); err != nil {
+		return nil, err
+	}
+
+	// Store client for session
+	activeClients.Store(sessionId, client)
+
+	return client, nil
+}
+
+// newZabbixClient builds a client without registering it for a session.
+// Clients with the same TLS settings share one transport.
+func newZabbixClient(zabbixURL string, tlsConfig TLSConfig, logger *log.Logger) (*ZabbixClient, error) {
+	config := Connection()
+	transport, err := sharedTransport(tlsConfig, config)
+	if err != nil {
+		return nil, err
 	}
-	httpClient := &http.Client{Transport: tr}
+	httpClient := &http.Client{Transport: transport}
 
 	client := &ZabbixClient{
 		URL:        zabbixURL,
-		AuthToken:  authToken,
 		HTTPClient: httpClient,
 		Logger:     logger,
+		Timeout:    config.Timeout,
+		MaxRetries: config.MaxRetries,
+		Limiter:    outboundLimiter(zabbixURL, config),
 	}
-
-	// Store client for session
-	activeClients.Store(sessionId, client)
+	client.touch()
 
 	return client, nil
 }
 
+// detectVersion detects the server version, logging instead of failing so
+// servers that block apiinfo.version keep working with 7.0 semantics
+func (c *ZabbixClient) detectVersion(ctx context.Context) {
+	if err := c.DetectVersion(ctx); err != nil {
+		c.Logger.WithError(err).WithField("zabbix_url", c.URL).Warn("Could not detect Zabbix API version, assuming 7.0")
+	}
+}
+
 // GetZabbixClient retrieves the Zabbix client for the given session
 func GetZabbixClient(sessionId string) *ZabbixClient {
 	if value, ok := activeClients.Load(sessionId); ok {
-		return value.(*ZabbixClient)
+		client := value.(*ZabbixClient)
+		client.touch()
+		return client
 	}
 	return nil
 }
@@ -123,9 +302,18 @@ func GetZabbixClientFromContext(ctx context.Context, logger *log.Logger) (*Zabbi
 
 	logger.WithField("session_id", session.SessionID()).Debug("Retrieving Zabbix client for session")
 
+	// Calls that name an instance use a separate client per instance
+	if name := InstanceFromContext(ctx); name != "" {
+		return getInstanceClient(ctx, session, name, logger)
+	}
+
 	// Try to get existing client
 	client := GetZabbixClient(session.SessionID())
 	if client != nil {
+		// A session may not be reused by a different authenticated principal
+		if principal := PrincipalFromContext(ctx); principal != client.Principal {
+			return nil, fmt.Errorf("session does not belong to the authenticated principal")
+		}
 		return client, nil
 	}
 
@@ -136,32 +324,45 @@ func GetZabbixClientFromContext(ctx context.Context, logger *log.Logger) (*Zabbi
 
 // CreateZabbixClientForSession creates a new Zabbix client for a session
 func CreateZabbixClientForSession(ctx context.Context, session server.ClientSession, logger *log.Logger) (*ZabbixClient, error) {
-	// Get Zabbix URL from context or environment
+	config := Connection()
+	tlsConfig := config.TLS
+
+	// Get Zabbix URL from context or the configured connection
 	zabbixURL, ok := ctx.Value(contextKey(ZabbixURL)).(string)
-	if !ok || zabbixURL == "" {
-		zabbixURL = getEnv(ZabbixURL, DefaultZabbixURL)
+	if ok && zabbixURL != "" {
+		// The SNI override is meant for the configured server only
+		tlsConfig.ServerName = ""
+	} else {
+		zabbixURL = config.URL
 	}
 
-	// Get auth token from context or environment
+	// Get auth token from context or the configured connection. The
+	// configured credentials are never sent to a URL chosen by the caller.
 	authToken, ok := ctx.Value(contextKey(ZabbixToken)).(string)
 	if !ok || authToken == "" {
-		authToken = getEnv(ZabbixToken, "")
-		if authToken == "" {
-			// Try user/password authentication (will need to call user.login)
-			return nil, fmt.Errorf("zabbix token not provided for session")
+		if zabbixURL != config.URL && !credentialsBound(ctx) {
+			return nil, fmt.Errorf("a Zabbix token is required with a Zabbix URL from the request")
 		}
+		authToken = config.Token
 	}
 
-	// Check for TLS skip verification
-	skipTLSVerify := false
-	if skipEnv := getEnv(ZabbixSkipTLSVerify, "false"); skipEnv == "true" || skipEnv == "1" {
-		skipTLSVerify = true
+	var newClient *ZabbixClient
+	var err error
+	if authToken != "" {
+		newClient, err = NewZabbixClient(ctx, session.SessionID(), zabbixURL, tlsConfig, authToken, logger)
+	} else {
+		// Fall back to user/password authentication via user.login
+		username := config.User
+		password := config.Password
+		if username == "" || password == "" {
+			return nil, fmt.Errorf("zabbix token or user/password not provided for session")
+		}
+		newClient, err = NewZabbixClientWithLogin(ctx, session.SessionID(), zabbixURL, tlsConfig, username, password, logger)
 	}
-
-	newClient, err := NewZabbixClient(session.SessionID(), zabbixURL, skipTLSVerify, authToken, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Zabbix client: %v", err)
 	}
+	newClient.Principal = PrincipalFromContext(ctx)
 
 	logger.WithFields(log.Fields{
 		"session_id": session.SessionID(),
@@ -173,6 +374,11 @@ func CreateZabbixClientForSession(ctx context.Context, session server.ClientSess
 
 // NewSessionHandler initializes a new Zabbix client for the session
 func NewSessionHandler(ctx context.Context, session server.ClientSession, logger *log.Logger) {
+	// The client may already have been created lazily by an earlier tool call
+	if GetZabbixClient(session.SessionID()) != nil {
+		return
+	}
+
 	_, err := CreateZabbixClientForSession(ctx, session, logger)
 	if err != nil {
 		logger.WithError(err).Error("NewSessionHandler failed to create Zabbix client")
@@ -181,18 +387,145 @@ func NewSessionHandler(ctx context.Context, session server.ClientSession, logger
 }
 
 // EndSessionHandler cleans up the Zabbix client when the session ends
-func EndSessionHandler(_ context.Context, session server.ClientSession, logger *log.Logger) {
+func EndSessionHandler(ctx context.Context, session server.ClientSession, logger *log.Logger) {
+	if zabbixClient := GetZabbixClient(session.SessionID()); zabbixClient != nil {
+		if err := zabbixClient.Logout(ctx); err != nil {
+			logger.WithError(err).WithField("session_id", session.SessionID()).Warn("Failed to log out of Zabbix")
+		}
+	}
 	DeleteZabbixClient(session.SessionID())
+	deleteInstanceClients(ctx, session.SessionID(), logger)
 	logger.WithField("session_id", session.SessionID()).Info("Cleaned up Zabbix client for session")
 }
 
-// Call makes a JSON-RPC call to the Zabbix API
+// Call makes a JSON-RPC call to the Zabbix API without a caller context.
+// Prefer CallContext from tool handlers so cancellation is honored.
 func (c *ZabbixClient) Call(method string, params interface{}) (json.RawMessage, error) {
+	return c.CallContext(context.Background(), method, params)
+}
+
+// CallContext makes a JSON-RPC call to the Zabbix API. The call is aborted when
+// ctx is cancelled or the client timeout expires. Idempotent methods are
+// retried with jittered exponential backoff on 5xx and connection errors.
+func (c *ZabbixClient) CallContext(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	// A dry run records the changes instead of making them
+	if plan, ok := ctx.Value(dryRunKey{}).(*DryRun); ok && !isReadOnlyMethod(method) {
+		return plan.plan(c, method, params)
+	}
+
+	instance := c.Instance
+	if instance == "" {
+		instance = metrics.DefaultInstance
+	}
+	ctx, span := tracing.Tracer().Start(ctx, "zabbix "+method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("zabbix.method", method),
+		attribute.String("zabbix.instance", instance),
+	))
+	defer span.End()
+
+	start := time.Now()
+	result, err := c.call(ctx, method, params)
+	metrics.ObserveZabbixCall(method, c.Instance, err, time.Since(start))
+	if !isReadOnlyMethod(method) {
+		audit.RecordCall(ctx, method, c.Instance, result, err)
+	}
+
+	span.SetAttributes(attribute.Int("zabbix.response.size", len(result)))
+	if err != nil {
+		var zabbixErr *ZabbixError
+		if errors.As(err, &zabbixErr) {
+			span.SetAttributes(attribute.Int("zabbix.error.code", zabbixErr.Code))
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return result, err
+}
+
+// call performs a call for CallContext, logging in again once when the
+// Zabbix session expired
+func (c *ZabbixClient) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	if IsReadOnly() && !isReadOnlyMethod(method) {
+		return nil, fmt.Errorf("zabbix method %s is not allowed in read-only mode", method)
+	}
+
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	auth := c.authToken()
+	result, err := c.callWithRetry(ctx, method, params, auth)
+	if err != nil && c.canRelogin() && isSessionExpired(err) {
+		logging.FromContext(ctx, c.Logger).WithField("method", method).Info("Zabbix session expired, logging in again")
+		if loginErr := c.relogin(ctx, auth); loginErr != nil {
+			return nil, fmt.Errorf("re-login after expired session failed: %w", loginErr)
+		}
+		return c.callWithRetry(ctx, method, params, c.authToken())
+	}
+	return result, err
+}
+
+// callWithRetry performs a call, retrying idempotent methods on transient errors
+func (c *ZabbixClient) callWithRetry(ctx context.Context, method string, params interface{}, auth string) (json.RawMessage, error) {
+	maxAttempts := 1
+	if isIdempotentMethod(method) && c.MaxRetries > 0 {
+		maxAttempts += c.MaxRetries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			delay := backoffDelay(attempt)
+			logging.FromContext(ctx, c.Logger).WithFields(log.Fields{
+				"method":  method,
+				"attempt": attempt + 1,
+				"delay":   delay,
+			}).WithError(lastErr).Debug("Retrying Zabbix API call")
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, fmt.Errorf("zabbix call %s aborted: %w", method, ctx.Err())
+			case <-timer.C:
+			}
+		}
+
+		result, err := c.doCall(ctx, method, params, auth)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+
+		if !isRetryable(err) || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, lastErr
+}
+
+// doCall performs a single JSON-RPC round trip
+func (c *ZabbixClient) doCall(ctx context.Context, method string, params interface{}, auth string) (json.RawMessage, error) {
+	if c.Limiter != nil {
+		if err := c.Limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("zabbix rate limit: %w", err)
+		}
+	}
+
+	// Zabbix before 6.2 only knows selectGroups
+	params, groupsField := c.legacyGroupSelect(method, params)
+
 	request := ZabbixRequest{
 		JSONRPC: "2.0",
 		Method:  method,
 		Params:  params,
-		ID:      1,
+		ID:      requestID.Add(1),
+	}
+	if auth != "" && !c.Version.AtLeast(BearerAuthVersion) {
+		request.Auth = auth
 	}
 
 	requestBody, err := json.Marshal(request)
@@ -200,32 +533,40 @@ func (c *ZabbixClient) Call(method string, params interface{}) (json.RawMessage,
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	c.Logger.WithFields(log.Fields{
-		"method": method,
-		"url":    c.URL,
-	}).Debug("Making Zabbix API call")
+	if c.Logger.IsLevelEnabled(log.DebugLevel) {
+		logging.FromContext(ctx, c.Logger).WithFields(log.Fields{
+			"method": method,
+			"url":    c.URL,
+			"id":     request.ID,
+			"params": logging.RedactParams(method, params),
+		}).Debug("Making Zabbix API call")
+	}
 
-	req, err := http.NewRequest("POST", c.URL, bytes.NewBuffer(requestBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.URL, bytes.NewBuffer(requestBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json-rpc")
 
-	// Zabbix 7.0 uses Authorization header with Bearer token
-	if c.AuthToken != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AuthToken))
+	// Zabbix 6.4+ takes the token in the Authorization header
+	if auth != "" && c.Version.AtLeast(BearerAuthVersion) {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", auth))
 	}
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, &transportError{err: fmt.Errorf("failed to make request: %w", err)}
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, &transportError{err: fmt.Errorf("failed to read response: %w", err)}
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, &transportError{err: fmt.Errorf("zabbix API returned HTTP %d", resp.StatusCode)}
 	}
 
 	var zabbixResp ZabbixResponse
@@ -237,9 +578,52 @@ func (c *ZabbixClient) Call(method string, params interface{}) (json.RawMessage,
 		return nil, zabbixResp.Error
 	}
 
+	if groupsField != "" {
+		return renameGroups(zabbixResp.Result, groupsField), nil
+	}
 	return zabbixResp.Result, nil
 }
 
+// transportError marks failures that happened below the JSON-RPC layer
+// (connection errors, 5xx responses) and are therefore safe to retry
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return e.err.Error() }
+func (e *transportError) Unwrap() error { return e.err }
+
+// isRetryable reports whether err may succeed on a later attempt
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var te *transportError
+	return errors.As(err, &te)
+}
+
+// isIdempotentMethod reports whether a Zabbix API method has no side effects
+func isIdempotentMethod(method string) bool {
+	return strings.HasSuffix(method, ".get") || method == "apiinfo.version"
+}
+
+// isReadOnlyMethod reports whether a Zabbix API method may be called in
+// read-only mode
+func isReadOnlyMethod(method string) bool {
+	return isIdempotentMethod(method) || method == "user.checkAuthentication"
+}
+
+// backoffDelay returns a jittered exponential delay for the given attempt
+func backoffDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << (attempt - 1)
+	if delay <= 0 || delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	// Full jitter in [delay/2, delay)
+	half := int64(delay / 2)
+	return time.Duration(half + rand.Int63n(half))
+}
+
 // Tag represents a Zabbix tag
 type Tag struct {
 	Tag   string `json:"tag"`
@@ -0,0 +1,645 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client_test
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	stdlog "log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func newClient(t *testing.T, s *zabbixtest.Server, retries int) *client.ZabbixClient {
+	t.Helper()
+	session := zabbixtest.NewSession()
+	zabbix, err := client.NewZabbixClient(context.Background(), session.ID, s.APIURL(), client.TLSConfig{}, zabbixtest.Token, zabbixtest.Logger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.DeleteZabbixClient(session.ID) })
+	zabbix.Limiter = nil
+	zabbix.MaxRetries = retries
+	return zabbix
+}
+
+func TestCallSendsBearerToken(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	zabbix := newClient(t, s, 0)
+
+	if _, err := zabbix.CallContext(context.Background(), "host.get", map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	req, _ := s.LastCall("host.get")
+	if got := req.Header.Get("Authorization"); got != "Bearer "+zabbixtest.Token {
+		t.Errorf("Authorization = %q", got)
+	}
+}
+
+func TestCallRetriesIdempotentMethods(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	zabbix := newClient(t, s, 2)
+	s.FailHTTP("host.get", http.StatusBadGateway, 2)
+
+	if _, err := zabbix.CallContext(context.Background(), "host.get", map[string]interface{}{}); err != nil {
+		t.Fatalf("expected the third attempt to succeed: %v", err)
+	}
+	if got := len(s.Calls("host.get")); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestCallDoesNotRetryWrites(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	zabbix := newClient(t, s, 2)
+	s.FailHTTP("host.create", http.StatusServiceUnavailable, 1)
+
+	_, err := zabbix.CallContext(context.Background(), "host.create", map[string]interface{}{"host": "web01"})
+	if err == nil || !strings.Contains(err.Error(), "HTTP 503") {
+		t.Fatalf("expected HTTP 503 error, got %v", err)
+	}
+	if got := len(s.Calls("host.create")); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestCallReturnsZabbixErrors(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	zabbix := newClient(t, s, 2)
+	s.Fail("host.get", zabbixtest.Error{Code: zabbixtest.CodeInvalidParams, Message: "Invalid params.", Data: "Incorrect filter."}, 0)
+
+	_, err := zabbix.CallContext(context.Background(), "host.get", map[string]interface{}{})
+	if err == nil || !strings.Contains(err.Error(), "Incorrect filter.") {
+		t.Fatalf("expected the Zabbix error, got %v", err)
+	}
+	if got := len(s.Calls("host.get")); got != 1 {
+		t.Errorf("application errors must not be retried, got %d attempts", got)
+	}
+}
+
+func TestCallLogsInAgainWhenSessionExpires(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	s.AddUser("Admin", "zabbix")
+	session := zabbixtest.NewSession()
+	zabbix, err := client.NewZabbixClientWithLogin(context.Background(), session.ID, s.APIURL(), client.TLSConfig{}, "Admin", "zabbix", zabbixtest.Logger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.DeleteZabbixClient(session.ID) })
+	zabbix.Limiter = nil
+	s.Fail("host.get", zabbixtest.Error{Code: zabbixtest.CodeInvalidParams, Message: "Invalid params.", Data: "Session terminated, re-login, please."}, 1)
+
+	if _, err := zabbix.CallContext(context.Background(), "host.get", map[string]interface{}{}); err != nil {
+		t.Fatalf("expected the call to succeed after re-login: %v", err)
+	}
+	if got := len(s.Calls("user.login")); got != 2 {
+		t.Errorf("expected 2 logins, got %d", got)
+	}
+}
+
+func TestCallRejectsWritesInReadOnlyMode(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	zabbix := newClient(t, s, 0)
+	client.SetReadOnly(true)
+	t.Cleanup(func() { client.SetReadOnly(false) })
+
+	if _, err := zabbix.CallContext(context.Background(), "host.delete", []string{"10084"}); err == nil {
+		t.Fatal("expected host.delete to be rejected")
+	}
+	s.AssertNotCalled(t, "host.delete")
+
+	if _, err := zabbix.CallContext(context.Background(), "host.get", map[string]interface{}{}); err != nil {
+		t.Errorf("expected host.get to be allowed: %v", err)
+	}
+}
+
+func TestDryRunPlansWrites(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	s.Model.Add(zabbixtest.Hosts, zabbixtest.Object{"hostid": "10084", "host": "web01"})
+	s.Model.Add(zabbixtest.HostGroups, zabbixtest.Object{"groupid": "2", "name": "Linux servers"})
+	zabbix := newClient(t, s, 0)
+	ctx, plan := client.WithDryRun(context.Background())
+
+	params := map[string]interface{}{"hostid": "10084", "status": 1, "groups": []map[string]string{{"groupid": "2"}, {"groupid": "99"}}}
+	result, err := zabbix.CallContext(ctx, "host.update", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zabbixtest.AssertJSON(t, result, `{"hostids":["10084"]}`)
+	if _, err := zabbix.CallContext(ctx, "host.get", map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	s.AssertNotCalled(t, "host.update")
+	if len(s.Calls("host.get")) != 1 {
+		t.Error("expected reads to reach Zabbix during a dry run")
+	}
+
+	calls := plan.Calls()
+	if len(calls) != 1 || calls[0].Request.Method != "host.update" {
+		t.Fatalf("expected the planned host.update, got %+v", calls)
+	}
+	zabbixtest.AssertJSON(t, calls[0].Request.Params.(json.RawMessage), `{"hostid":"10084","status":1,"groups":[{"groupid":"2"},{"groupid":"99"}]}`)
+
+	refs, err := plan.Resolve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[string]bool)
+	for _, ref := range refs {
+		found[ref.Type+"/"+ref.ID] = ref.Found
+	}
+	if !found["host/10084"] || !found["hostgroup/2"] || found["hostgroup/99"] || len(refs) != 3 {
+		t.Errorf("unexpected references: %+v", refs)
+	}
+	if summary := plan.Summary(refs); len(summary) != 1 || summary[0] != `Update host "web01" (10084): groups, status` {
+		t.Errorf("unexpected summary: %q", summary)
+	}
+}
+
+func TestDryRunRedactsAuthBefore64(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	s.Version = "6.0.30"
+	zabbix := newClient(t, s, 0)
+	ctx, plan := client.WithDryRun(context.Background())
+
+	result, err := zabbix.CallContext(ctx, "host.delete", []string{"10084", "10085"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	zabbixtest.AssertJSON(t, result, `{"hostids":["10084","10085"]}`)
+	if calls := plan.Calls(); len(calls) != 1 || calls[0].Request.Auth != "[REDACTED]" {
+		t.Errorf("expected the token to be redacted from the planned request, got %+v", calls)
+	}
+	if summary := plan.Summary(nil); len(summary) != 1 || summary[0] != "Delete hosts 10084, 10085" {
+		t.Errorf("unexpected summary: %q", summary)
+	}
+}
+
+func TestNewClientDetectsVersion(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	s.Version = "7.2.4"
+	zabbix := newClient(t, s, 0)
+
+	if want := (client.Version{Major: 7, Minor: 2, Patch: 4}); zabbix.Version != want {
+		t.Errorf("Version = %v, want %v", zabbix.Version, want)
+	}
+	req, _ := s.LastCall("apiinfo.version")
+	if req.Auth != "" {
+		t.Errorf("apiinfo.version must be sent without auth, got %q", req.Auth)
+	}
+}
+
+func TestCallSendsAuthInBodyBefore64(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	s.Version = "6.0.30"
+	zabbix := newClient(t, s, 0)
+
+	if _, err := zabbix.CallContext(context.Background(), "host.get", map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	req, _ := s.LastCall("host.get")
+	if got := req.Header.Get("Authorization"); got != "" {
+		t.Errorf("Authorization = %q, want none", got)
+	}
+	if req.Auth != zabbixtest.Token {
+		t.Errorf("auth = %q, want the token in the request body", req.Auth)
+	}
+}
+
+func TestCallSelectsGroupsBefore62(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	s.Version = "6.0.30"
+	s.Model.Add(zabbixtest.Hosts, zabbixtest.Object{"hostid": "10084", "groups": []interface{}{map[string]interface{}{"groupid": "2"}}})
+	zabbix := newClient(t, s, 0)
+
+	result, err := zabbix.CallContext(context.Background(), "host.get", client.HostGetParams{Output: []string{"hostid"}, SelectGroups: []string{"groupid"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := s.LastCall("host.get")
+	zabbixtest.AssertJSON(t, req.Params, `{"output":["hostid"],"selectGroups":["groupid"]}`)
+	zabbixtest.AssertJSON(t, result, `[{"hostid":"10084","hostgroups":[{"groupid":"2"}]}]`)
+
+	// Newer servers get the option unchanged
+	s.Version = zabbixtest.APIVersion
+	zabbix = newClient(t, s, 0)
+	if _, err := zabbix.CallContext(context.Background(), "template.get", map[string]interface{}{"selectTemplateGroups": "extend"}); err != nil {
+		t.Fatal(err)
+	}
+	req, _ = s.LastCall("template.get")
+	zabbixtest.AssertJSON(t, req.Params, `{"selectTemplateGroups":"extend"}`)
+}
+
+func TestUnknownVersionKeepsCurrentBehavior(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	s.FailHTTP("apiinfo.version", http.StatusForbidden, 0)
+	zabbix := newClient(t, s, 0)
+
+	if !zabbix.Version.IsZero() {
+		t.Fatalf("Version = %v, want unknown", zabbix.Version)
+	}
+	if err := zabbix.Require(client.ProxyGroupsVersion, "proxy groups"); err != nil {
+		t.Errorf("unknown versions must be treated as current: %v", err)
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	for _, tc := range []struct {
+		version string
+		min     client.Version
+		want    bool
+	}{
+		{"6.0.30", client.BearerAuthVersion, false},
+		{"6.4.0", client.BearerAuthVersion, true},
+		{"6.4.12", client.ProxyGroupsVersion, false},
+		{"7.0", client.ProxyGroupsVersion, true},
+		{"7.4.1", client.ProxyGroupsVersion, true},
+		{"7.4.0beta1", client.ProxyGroupsVersion, true},
+	} {
+		v, err := client.ParseVersion(tc.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := v.AtLeast(tc.min); got != tc.want {
+			t.Errorf("%s.AtLeast(%v) = %v, want %v", tc.version, tc.min, got, tc.want)
+		}
+	}
+
+	if _, err := client.ParseVersion("seven"); err == nil {
+		t.Error("expected an error for a malformed version")
+	}
+}
+
+func TestInstancesConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config client.InstancesConfig
+		want   string
+	}{
+		{"no name", client.InstancesConfig{Instances: []client.Instance{{URL: "u", Token: "t"}}}, "has no name"},
+		{"duplicate", client.InstancesConfig{Instances: []client.Instance{{Name: "eu", URL: "u", Token: "t"}, {Name: "eu", URL: "u", Token: "t"}}}, "more than once"},
+		{"no url", client.InstancesConfig{Instances: []client.Instance{{Name: "eu", Token: "t"}}}, "has no url"},
+		{"no credentials", client.InstancesConfig{Instances: []client.Instance{{Name: "eu", URL: "u", User: "Admin"}}}, "needs a token"},
+		{"unknown default", client.InstancesConfig{Default: "us", Instances: []client.Instance{{Name: "eu", URL: "u", Token: "t"}}}, `default instance "us"`},
+	} {
+		err := tc.config.Validate()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestInstanceClientsEndWithSession(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	s.AddUser("Admin", "zabbix")
+	client.SetInstances(client.InstancesConfig{
+		Default:   "eu",
+		Instances: []client.Instance{{Name: "eu", URL: s.APIURL(), User: "Admin", Password: "zabbix"}},
+	})
+	t.Cleanup(func() { client.SetInstances(client.InstancesConfig{}) })
+
+	session := zabbixtest.NewSession()
+	ctx := s.SessionContext(t, session)
+	logger := zabbixtest.Logger()
+
+	// Calls without an instance argument use the default instance
+	zabbix, err := client.GetZabbixClientFromContext(ctx, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zabbix.Username != "Admin" {
+		t.Fatalf("expected the default instance client, got %+v", zabbix)
+	}
+
+	client.EndSessionHandler(context.Background(), session, logger)
+	if got := len(s.Calls("user.logout")); got != 1 {
+		t.Errorf("expected the instance session to be logged out, got %d logouts", got)
+	}
+}
+
+func TestClientPresentsCertificateToMutualTLSServer(t *testing.T) {
+	ca := zabbixtest.NewCA(t)
+	s := zabbixtest.NewTLSServer(t, &tls.Config{
+		Certificates: []tls.Certificate{ca.Certificate(t, "server", "zabbix.internal")},
+		ClientCAs:    ca.Pool(),
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+	certFile, keyFile := ca.Issue(t, "mcp")
+
+	// The server certificate only names zabbix.internal, so the SNI
+	// override is what makes verification succeed against 127.0.0.1
+	config := client.TLSConfig{CAFile: ca.File, CertFile: certFile, KeyFile: keyFile, MinVersion: "1.3", ServerName: "zabbix.internal"}
+	session := zabbixtest.NewSession()
+	zabbix, err := client.NewZabbixClient(context.Background(), session.ID, s.APIURL(), config, zabbixtest.Token, zabbixtest.Logger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.DeleteZabbixClient(session.ID) })
+	if zabbix.Version.String() != zabbixtest.APIVersion {
+		t.Errorf("expected the version from the TLS server, got %s", zabbix.Version)
+	}
+
+	// Without a client certificate the handshake fails
+	config.CertFile, config.KeyFile = "", ""
+	session = zabbixtest.NewSession()
+	anonymous, err := client.NewZabbixClient(context.Background(), session.ID, s.APIURL(), config, zabbixtest.Token, zabbixtest.Logger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.DeleteZabbixClient(session.ID) })
+	anonymous.MaxRetries = 0
+	if _, err := anonymous.CallContext(context.Background(), "host.get", map[string]interface{}{}); err == nil {
+		t.Error("expected the server to reject a client without a certificate")
+	}
+}
+
+func TestClientsShareTransport(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	first := newClient(t, s, 0)
+	second := newClient(t, s, 0)
+	if first.HTTPClient.Transport != second.HTTPClient.Transport {
+		t.Error("expected sessions with the same TLS settings to share a transport")
+	}
+
+	session := zabbixtest.NewSession()
+	other, err := client.NewZabbixClient(context.Background(), session.ID, s.APIURL(), client.TLSConfig{SkipVerify: true}, zabbixtest.Token, zabbixtest.Logger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.DeleteZabbixClient(session.ID) })
+	if other.HTTPClient.Transport == first.HTTPClient.Transport {
+		t.Error("expected different TLS settings to use their own transport")
+	}
+}
+
+func TestTLSConfigValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config client.TLSConfig
+		want   string
+	}{
+		"cert without key": {client.TLSConfig{CertFile: "mcp.pem"}, "set together"},
+		"unknown version":  {client.TLSConfig{MinVersion: "1.4"}, "min_version"},
+		"TLS 1.0":          {client.TLSConfig{MinVersion: "1.0"}, "expected 1.2 or 1.3"},
+		"TLS 1.1":          {client.TLSConfig{MinVersion: "TLS1.1"}, "expected 1.2 or 1.3"},
+		"TLS 1.3":          {client.TLSConfig{MinVersion: "1.3"}, ""},
+		"default version":  {client.TLSConfig{}, ""},
+		"valid":            {client.TLSConfig{CertFile: "mcp.pem", KeyFile: "mcp-key.pem", MinVersion: "TLS1.2"}, ""},
+	} {
+		err := tc.config.Validate()
+		if tc.want == "" && err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want)) {
+			t.Errorf("%s: expected an error about %s, got %v", name, tc.want, err)
+		}
+	}
+
+	if _, err := (client.TLSConfig{CAFile: "missing.pem"}).ClientConfig(); err == nil {
+		t.Error("expected a missing CA file to fail")
+	}
+}
+
+// serveTLS serves an empty response over TLS with config
+func serveTLS(t *testing.T, config *tls.Config) string {
+	t.Helper()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{
+		Handler:  http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+		ErrorLog: stdlog.New(io.Discard, "", 0),
+	}
+	go srv.Serve(listener)
+	t.Cleanup(func() { srv.Close() })
+	return "https://" + listener.Addr().String()
+}
+
+// servedSerial returns the serial number of the certificate served at url
+func servedSerial(t *testing.T, url string, config *tls.Config) (string, error) {
+	t.Helper()
+	httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: config, DisableKeepAlives: true}}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	return resp.TLS.PeerCertificates[0].SerialNumber.String(), nil
+}
+
+func TestCertReloaderRequiresClientCertificates(t *testing.T) {
+	ca := zabbixtest.NewCA(t)
+	certFile, keyFile := ca.Issue(t, "server", "127.0.0.1")
+	reloader, err := client.NewCertReloader(client.ListenerTLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: ca.File}, zabbixtest.Logger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := serveTLS(t, reloader.TLSConfig())
+
+	if _, err := servedSerial(t, url, &tls.Config{RootCAs: ca.Pool()}); err == nil {
+		t.Error("expected a client without a certificate to be rejected")
+	}
+	clientCert := ca.Certificate(t, "mcp-client")
+	if _, err := servedSerial(t, url, &tls.Config{RootCAs: ca.Pool(), Certificates: []tls.Certificate{clientCert}}); err != nil {
+		t.Errorf("expected a client certificate signed by the CA to be accepted: %v", err)
+	}
+}
+
+func TestCertReloaderPicksUpNewCertificate(t *testing.T) {
+	ca := zabbixtest.NewCA(t)
+	certFile, keyFile := ca.Issue(t, "server", "127.0.0.1")
+	reloader, err := client.NewCertReloader(client.ListenerTLSConfig{CertFile: certFile, KeyFile: keyFile}, zabbixtest.Logger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := serveTLS(t, reloader.TLSConfig())
+	clientConfig := &tls.Config{RootCAs: ca.Pool()}
+
+	before, err := servedSerial(t, url, clientConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Watch(ctx, 10*time.Millisecond)
+
+	// Issuing for the same name rewrites the same files
+	ca.Issue(t, "server", "127.0.0.1")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		after, err := servedSerial(t, url, clientConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if after != before {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the rewritten certificate to be served")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// A broken file keeps the current certificate
+	if err := os.WriteFile(certFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloader.Reload(); err == nil {
+		t.Error("expected reloading a broken certificate to fail")
+	}
+	if _, err := servedSerial(t, url, clientConfig); err != nil {
+		t.Errorf("expected the previous certificate to be served: %v", err)
+	}
+}
+
+func TestListenerTLSConfigValidate(t *testing.T) {
+	for _, config := range []client.ListenerTLSConfig{
+		{CertFile: "server.pem"},
+		{ClientCAFile: "ca.pem"},
+	} {
+		if err := config.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", config)
+		}
+	}
+	if err := (client.ListenerTLSConfig{}).Check(); err != nil {
+		t.Errorf("plain HTTP must be valid: %v", err)
+	}
+}
+
+func TestReadinessReportsEachInstance(t *testing.T) {
+	eu := zabbixtest.NewServer(t)
+	us := zabbixtest.NewServer(t)
+	us.Version = "6.0.30"
+	down := zabbixtest.NewServer(t)
+	downURL := down.APIURL()
+	down.Close()
+	client.SetInstances(client.InstancesConfig{
+		Default: "eu",
+		Instances: []client.Instance{
+			{Name: "eu", URL: eu.APIURL(), Token: zabbixtest.Token},
+			{Name: "us", URL: us.APIURL(), Token: "revoked-token"},
+			{Name: "apac", URL: downURL, Token: zabbixtest.Token},
+		},
+	})
+	t.Cleanup(func() { client.SetInstances(client.InstancesConfig{}) })
+
+	checker := client.NewReadinessChecker(time.Minute, zabbixtest.Logger())
+	ts := httptest.NewServer(client.ReadinessHandler(checker))
+	t.Cleanup(ts.Close)
+
+	get := func() (int, client.Readiness) {
+		t.Helper()
+		resp, err := http.Get(ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The endpoint is unauthenticated and must not disclose the backends
+		for _, url := range []string{eu.APIURL(), us.APIURL(), downURL} {
+			if strings.Contains(string(body), url) {
+				t.Errorf("response discloses %s: %s", url, body)
+			}
+		}
+		var readiness client.Readiness
+		if err := json.Unmarshal(body, &readiness); err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, readiness
+	}
+
+	// Only a named instance that is not the default fails
+	status, readiness := get()
+	if status != http.StatusOK || readiness.Status != client.ReadinessDegraded {
+		t.Fatalf("expected a degraded 200, got %d %+v", status, readiness)
+	}
+	checks := map[string]client.ReadinessCheck{}
+	for _, check := range readiness.Checks {
+		checks[check.Instance] = check
+	}
+	if checks["eu"].Status != client.CheckOK || checks["eu"].Version != zabbixtest.APIVersion {
+		t.Errorf("eu: %+v", checks["eu"])
+	}
+	if checks["us"].Status != client.CheckError || checks["us"].Version != "6.0.30" || checks["us"].Error != client.ProbeAuthentication {
+		t.Errorf("us: %+v", checks["us"])
+	}
+	if checks["apac"].Status != client.CheckError || checks["apac"].Error != client.ProbeUnreachable {
+		t.Errorf("apac: %+v", checks["apac"])
+	}
+
+	// Results are cached for the TTL
+	get()
+	if got := len(eu.Calls("apiinfo.version")); got != 1 {
+		t.Errorf("expected one probe within the cache TTL, got %d", got)
+	}
+}
+
+func TestReadinessUnavailableWhenDefaultFails(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	zabbixtest.UseConnection(t, func(c *client.ConnectionConfig) {
+		c.URL = s.APIURL()
+		c.Token = zabbixtest.Token
+	})
+
+	checker := client.NewReadinessChecker(0, zabbixtest.Logger())
+	if readiness := checker.Check(context.Background()); readiness.Status != client.ReadinessReady || len(readiness.Checks) != 1 {
+		t.Fatalf("expected the default connection to be ready, got %+v", readiness)
+	}
+
+	s.RevokeToken(zabbixtest.Token)
+	rec := httptest.NewRecorder()
+	client.ReadinessHandler(checker)(rec, httptest.NewRequest(http.MethodGet, client.ReadinessPath, nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), `"instance":"default"`) {
+		t.Errorf("expected 503 for the default connection, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestReadinessDoesNotLeakSessionsOfFailedProbes(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	s.AddUser("Admin", "zabbix")
+	client.SetInstances(client.InstancesConfig{
+		Instances: []client.Instance{{Name: "eu", URL: s.APIURL(), User: "Admin", Password: "zabbix"}},
+	})
+	t.Cleanup(func() { client.SetInstances(client.InstancesConfig{}) })
+
+	// Login succeeds but the authenticated call does not
+	s.Fail("host.get", zabbixtest.Error{Code: zabbixtest.CodeInternal, Message: "Application error.", Data: "Injected failure."}, 0)
+	checker := client.NewReadinessChecker(0, zabbixtest.Logger())
+	for i := 0; i < 3; i++ {
+		if readiness := checker.Check(context.Background()); readiness.Checks[0].Error != client.ProbeAPIError {
+			t.Fatalf("probe %d: expected an API error, got %+v", i+1, readiness)
+		}
+	}
+	if got := len(s.Calls("user.login")); got != 1 {
+		t.Errorf("expected failed probes to reuse their session, got %d logins", got)
+	}
+
+	// The session of a target that is no longer configured is logged out
+	client.SetInstances(client.InstancesConfig{})
+	checker.Check(context.Background())
+	if got := len(s.Calls("user.logout")); got != 1 {
+		t.Errorf("expected the dropped session to be logged out, got %d logouts", got)
+	}
+}
+
+func TestReadinessWithoutBackends(t *testing.T) {
+	// Sessions bring their own credentials, so there is nothing to probe
+	readiness := client.NewReadinessChecker(0, zabbixtest.Logger()).Check(context.Background())
+	if readiness.Status != client.ReadinessReady || len(readiness.Checks) != 0 {
+		t.Errorf("expected ready without checks, got %+v", readiness)
+	}
+}
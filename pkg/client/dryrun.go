@@ -0,0 +1,452 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/audit"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+)
+
+// objectType describes how to look up a Zabbix object type
+type objectType struct {
+	getMethod string
+	// idField is the ID field of an object, idsParam the get parameter
+	// filtering by it
+	idField  string
+	idsParam string
+	// nameField names an object in summaries
+	nameField string
+	// getParams are added to every get call
+	getParams map[string]interface{}
+}
+
+// objectTypes are the object types changed or referenced by the mutating
+// tools, keyed by API name. Global macros share usermacro.* with host macros.
+var objectTypes = map[string]objectType{
+	"host":             {getMethod: "host.get", idField: "hostid", idsParam: "hostids", nameField: "host"},
+	"hostgroup":        {getMethod: "hostgroup.get", idField: "groupid", idsParam: "groupids", nameField: "name"},
+	"templategroup":    {getMethod: "templategroup.get", idField: "groupid", idsParam: "groupids", nameField: "name"},
+	"template":         {getMethod: "template.get", idField: "templateid", idsParam: "templateids", nameField: "host"},
+	"item":             {getMethod: "item.get", idField: "itemid", idsParam: "itemids", nameField: "name"},
+	"itemprototype":    {getMethod: "itemprototype.get", idField: "itemid", idsParam: "itemids", nameField: "name"},
+	"discoveryrule":    {getMethod: "discoveryrule.get", idField: "itemid", idsParam: "itemids", nameField: "name"},
+	"trigger":          {getMethod: "trigger.get", idField: "triggerid", idsParam: "triggerids", nameField: "description"},
+	"triggerprototype": {getMethod: "triggerprototype.get", idField: "triggerid", idsParam: "triggerids", nameField: "description"},
+	"maintenance":      {getMethod: "maintenance.get", idField: "maintenanceid", idsParam: "maintenanceids", nameField: "name"},
+	"proxy":            {getMethod: "proxy.get", idField: "proxyid", idsParam: "proxyids", nameField: "name"},
+	"proxygroup":       {getMethod: "proxygroup.get", idField: "proxy_groupid", idsParam: "proxy_groupids", nameField: "name"},
+	"user":             {getMethod: "user.get", idField: "userid", idsParam: "userids", nameField: "username"},
+	"usergroup":        {getMethod: "usergroup.get", idField: "usrgrpid", idsParam: "usrgrpids", nameField: "name"},
+	"role":             {getMethod: "role.get", idField: "roleid", idsParam: "roleids", nameField: "name"},
+	"usermacro":        {getMethod: "usermacro.get", idField: "hostmacroid", idsParam: "hostmacroids", nameField: "macro"},
+	"globalmacro": {getMethod: "usermacro.get", idField: "globalmacroid", idsParam: "globalmacroids", nameField: "macro",
+		getParams: map[string]interface{}{"globalmacro": true}},
+	"event": {getMethod: "event.get", idField: "eventid", idsParam: "eventids", nameField: "name"},
+}
+
+// referenceFields maps the ID fields and ID list parameters found in
+// mutating calls to the object type they refer to
+var referenceFields = map[string]string{
+	"hostid":            "host",
+	"hostids":           "host",
+	"groupid":           "hostgroup",
+	"groupids":          "hostgroup",
+	"templateid":        "template",
+	"templateids":       "template",
+	"itemid":            "item",
+	"itemids":           "item",
+	"master_itemid":     "item",
+	"ruleid":            "discoveryrule",
+	"discoveryids":      "discoveryrule",
+	"triggerid":         "trigger",
+	"triggerids":        "trigger",
+	"maintenanceid":     "maintenance",
+	"proxyid":           "proxy",
+	"proxy_groupid":     "proxygroup",
+	"userid":            "user",
+	"userids":           "user",
+	"usrgrpid":          "usergroup",
+	"usrgrpids":         "usergroup",
+	"roleid":            "role",
+	"hostmacroid":       "usermacro",
+	"globalmacroid":     "globalmacro",
+	"eventids":          "event",
+	"templateids_clear": "template",
+}
+
+// PlannedCall is a mutating Zabbix API call that a dry run did not send
+type PlannedCall struct {
+	Instance string `json:"instance,omitempty"`
+	// Request is the JSON-RPC request that would have been sent
+	Request ZabbixRequest `json:"request"`
+
+	client *ZabbixClient
+	// params is the decoded form of the request parameters
+	params interface{}
+}
+
+// Reference is a Zabbix object that a planned call changes or refers to
+type Reference struct {
+	Type  string `json:"type"`
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Found bool   `json:"found"`
+}
+
+// DryRun collects the mutating calls of a tool call instead of sending them
+type DryRun struct {
+	mu    sync.Mutex
+	calls []PlannedCall
+}
+
+type dryRunKey struct{}
+
+// WithDryRun returns a context in which mutating Zabbix calls are recorded
+// in the returned DryRun rather than sent. Reads still reach Zabbix, so
+// tools can look up what they would change.
+func WithDryRun(ctx context.Context) (context.Context, *DryRun) {
+	plan := &DryRun{}
+	audit.MarkDryRun(ctx)
+	return context.WithValue(ctx, dryRunKey{}, plan), plan
+}
+
+// IsDryRun reports whether mutating calls made with ctx are only recorded
+func IsDryRun(ctx context.Context) bool {
+	_, ok := ctx.Value(dryRunKey{}).(*DryRun)
+	return ok
+}
+
+// Calls returns the planned calls in order
+func (d *DryRun) Calls() []PlannedCall {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]PlannedCall(nil), d.calls...)
+}
+
+// plan records a mutating call and returns the result Zabbix would give
+// for it: the IDs it changes, or placeholders for created objects
+func (d *DryRun) plan(c *ZabbixClient, method string, params interface{}) (json.RawMessage, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	request := ZabbixRequest{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  json.RawMessage(data),
+		ID:      requestID.Add(1),
+	}
+	if c.authToken() != "" && !c.Version.AtLeast(BearerAuthVersion) {
+		request.Auth = logging.Redacted
+	}
+
+	d.mu.Lock()
+	d.calls = append(d.calls, PlannedCall{Instance: c.Instance, Request: request, client: c, params: decoded})
+	d.mu.Unlock()
+
+	objType, action := methodObject(method)
+	ids := ownIDs(objType, action, decoded)
+	if action == "create" || action == "createglobal" {
+		ids = []string{"(new)"}
+	}
+	return json.Marshal(map[string][]string{objectTypes[objType].idsParam: ids})
+}
+
+// methodObject returns the object type and action of an API method
+func methodObject(method string) (objType, action string) {
+	objType, action, _ = strings.Cut(method, ".")
+	if objType == "usermacro" && strings.HasSuffix(action, "global") {
+		objType = "globalmacro"
+	}
+	return objType, action
+}
+
+// fieldType returns the object type an ID field of a call to objType
+// refers to
+func fieldType(objType, field string) (string, bool) {
+	if t, ok := objectTypes[objType]; ok && (field == t.idField || field == t.idsParam) {
+		return objType, true
+	}
+	// Templates belong to template groups
+	if (objType == "template" || objType == "templategroup") && (field == "groupid" || field == "groupids") {
+		return "templategroup", true
+	}
+	t, ok := referenceFields[field]
+	return t, ok
+}
+
+// ownIDs returns the IDs of the objects a call changes: the ID list of a
+// delete, or the ID fields of the updated objects
+func ownIDs(objType, action string, params interface{}) []string {
+	idField := objectTypes[objType].idField
+	var ids []string
+	var collect func(value interface{}, top bool)
+	collect = func(value interface{}, top bool) {
+		switch v := value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				collect(item, top)
+			}
+		case map[string]interface{}:
+			if top {
+				if id, ok := v[idField].(string); ok {
+					ids = append(ids, id)
+				}
+				if list, ok := v[objectTypes[objType].idsParam].([]interface{}); ok && action != "update" {
+					for _, id := range list {
+						ids = append(ids, fmt.Sprint(id))
+					}
+				}
+			}
+		case string:
+			if top {
+				ids = append(ids, v)
+			}
+		}
+	}
+	collect(params, true)
+	return ids
+}
+
+// references returns the objects a call changes or refers to
+func references(call PlannedCall) []Reference {
+	objType, _ := methodObject(call.Request.Method)
+	var refs []Reference
+	add := func(t, id string) {
+		if _, ok := objectTypes[t]; ok && id != "" {
+			refs = append(refs, Reference{Type: t, ID: id})
+		}
+	}
+
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case map[string]interface{}:
+			for field, fieldValue := range v {
+				t, ok := fieldType(objType, field)
+				if !ok {
+					walk(fieldValue)
+					continue
+				}
+				switch id := fieldValue.(type) {
+				case string:
+					add(t, id)
+				case []interface{}:
+					for _, item := range id {
+						if s, ok := item.(string); ok {
+							add(t, s)
+						} else {
+							walk(item)
+						}
+					}
+				default:
+					walk(fieldValue)
+				}
+			}
+		}
+	}
+
+	// Deletes take a bare list of IDs
+	if list, ok := call.params.([]interface{}); ok {
+		for _, item := range list {
+			if id, ok := item.(string); ok {
+				add(objType, id)
+			}
+		}
+	}
+	walk(call.params)
+	return refs
+}
+
+// Resolve looks up every object the planned calls change or refer to, one
+// get call per object type and Zabbix client
+func (d *DryRun) Resolve(ctx context.Context) ([]Reference, error) {
+	type lookup struct {
+		client  *ZabbixClient
+		objType string
+	}
+	ids := make(map[lookup][]string)
+	seen := make(map[string]bool)
+	var order []lookup
+	var refs []Reference
+
+	for _, call := range d.Calls() {
+		for _, ref := range references(call) {
+			key := call.Instance + "/" + ref.Type + "/" + ref.ID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			l := lookup{client: call.client, objType: ref.Type}
+			if _, ok := ids[l]; !ok {
+				order = append(order, l)
+			}
+			ids[l] = append(ids[l], ref.ID)
+		}
+	}
+
+	for _, l := range order {
+		t := objectTypes[l.objType]
+		params := map[string]interface{}{
+			"output":   "extend",
+			t.idsParam: ids[l],
+		}
+		for key, value := range t.getParams {
+			params[key] = value
+		}
+		result, err := l.client.CallContext(ctx, t.getMethod, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up %s IDs: %w", l.objType, err)
+		}
+		var objects []map[string]interface{}
+		if err := json.Unmarshal(result, &objects); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", t.getMethod, err)
+		}
+
+		names := make(map[string]string, len(objects))
+		for _, obj := range objects {
+			id, _ := obj[t.idField].(string)
+			names[id] = objectName(obj, t.nameField)
+		}
+		for _, id := range ids[l] {
+			name, found := names[id]
+			refs = append(refs, Reference{Type: l.objType, ID: id, Name: name, Found: found})
+		}
+	}
+	return refs, nil
+}
+
+// objectName returns the name of a Zabbix object
+func objectName(obj map[string]interface{}, nameField string) string {
+	for _, field := range []string{nameField, "name", "host"} {
+		if name, ok := obj[field].(string); ok && name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// Summary describes each planned call in a line, e.g.
+// `Update host "web-01" (10084): description, status`
+func (d *DryRun) Summary(refs []Reference) []string {
+	names := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		names[ref.Type+"/"+ref.ID] = ref.Name
+	}
+
+	var lines []string
+	for _, call := range d.Calls() {
+		objType, action := methodObject(call.Request.Method)
+		noun := strings.ReplaceAll(objType, "group", " group")
+		noun = strings.ReplaceAll(noun, "prototype", " prototype")
+		noun = strings.ReplaceAll(noun, "rule", " rule")
+		noun = strings.ReplaceAll(noun, "macro", " macro")
+
+		var subjects []string
+		for _, id := range ownIDs(objType, action, call.params) {
+			if name := names[objType+"/"+id]; name != "" {
+				subjects = append(subjects, fmt.Sprintf("%q (%s)", name, id))
+			} else {
+				subjects = append(subjects, id)
+			}
+		}
+		fields := changedFields(objType, call.params)
+
+		var line string
+		switch strings.TrimSuffix(action, "global") {
+		case "create":
+			line = fmt.Sprintf("Create %s", noun)
+			if name := createdName(call.params); name != "" {
+				line += fmt.Sprintf(" %q", name)
+			}
+		case "update":
+			line = fmt.Sprintf("Update %s %s", noun, strings.Join(subjects, ", "))
+		case "delete":
+			line = fmt.Sprintf("Delete %s %s", plural(noun, len(subjects)), strings.Join(subjects, ", "))
+			fields = nil
+		default:
+			line = fmt.Sprintf("Call %s", call.Request.Method)
+			if len(subjects) > 0 {
+				line += " on " + strings.Join(subjects, ", ")
+			}
+		}
+		if len(fields) > 0 {
+			line += ": " + strings.Join(fields, ", ")
+		}
+		if call.Instance != "" {
+			line += fmt.Sprintf(" [instance %s]", call.Instance)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// changedFields returns the sorted fields a call sets, without the ID of
+// the changed object
+func changedFields(objType string, params interface{}) []string {
+	fieldSet := make(map[string]bool)
+	var collect func(value interface{})
+	collect = func(value interface{}) {
+		switch v := value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				collect(item)
+			}
+		case map[string]interface{}:
+			for field := range v {
+				if field != objectTypes[objType].idField {
+					fieldSet[field] = true
+				}
+			}
+		}
+	}
+	collect(params)
+
+	fields := make([]string, 0, len(fieldSet))
+	for field := range fieldSet {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// createdName returns the name of an object to be created
+func createdName(params interface{}) string {
+	obj, ok := params.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	for _, field := range []string{"name", "host", "macro", "username", "description"} {
+		if name, ok := obj[field].(string); ok && name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+func plural(noun string, n int) string {
+	if n == 1 {
+		return noun
+	}
+	if strings.HasSuffix(noun, "y") {
+		return strings.TrimSuffix(noun, "y") + "ies"
+	}
+	return noun + "s"
+}
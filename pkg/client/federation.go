@@ -0,0 +1,67 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ZabbixFederationConcurrency is the environment variable for the number of
+// instances a federated query calls at once
+const ZabbixFederationConcurrency = "ZABBIX_FEDERATION_CONCURRENCY"
+
+// DefaultFederationConcurrency is the default number of instances a
+// federated query calls at once
+const DefaultFederationConcurrency = 4
+
+// InstanceResult is the outcome of an API call on one named instance
+type InstanceResult struct {
+	Instance string
+	Result   json.RawMessage
+	Err      error
+}
+
+// CallInstances makes the same API call on each named instance in parallel,
+// running at most the connection's FederationConcurrency calls at a time. Each call is
+// bounded by its client's timeout, so a slow instance only holds up its own
+// slot. Results are returned in the order of names.
+func CallInstances(ctx context.Context, names []string, method string, params interface{}, logger *log.Logger) []InstanceResult {
+	limit := Connection().FederationConcurrency
+	if limit < 1 {
+		limit = 1
+	}
+	slots := make(chan struct{}, limit)
+
+	results := make([]InstanceResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		results[i].Instance = name
+		wg.Add(1)
+		go func(result *InstanceResult) {
+			defer wg.Done()
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				result.Err = ctx.Err()
+				return
+			}
+
+			zabbix, err := GetZabbixClientFromContext(WithInstance(ctx, result.Instance), logger)
+			if err != nil {
+				result.Err = err
+				return
+			}
+			result.Result, result.Err = zabbix.CallContext(ctx, method, params)
+		}(&results[i])
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,336 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// HTTP authentication environment variables
+const (
+	AuthKeysFile   = "MCP_AUTH_KEYS_FILE"
+	AuthAPIKeys    = "MCP_API_KEYS"
+	AuthHMACSecret = "MCP_AUTH_HMAC_SECRET"
+	APIKeyHeader   = "X-API-Key"
+)
+
+// principalKey is the context key for the authenticated principal
+const principalKey contextKey = "mcp_principal"
+
+// credentialsBoundKey marks a request whose Zabbix credentials were pinned by
+// its auth key; header and query overrides are ignored for such requests
+const credentialsBoundKey contextKey = "mcp_credentials_bound"
+
+// adminKey marks a request authenticated with an admin key
+const adminKey contextKey = "mcp_admin"
+
+// AuthKey maps an API key or token subject to the Zabbix credentials it may use
+type AuthKey struct {
+	Name string `json:"name" yaml:"name" toml:"name"`
+	// Key is the plaintext API key; KeySHA256 is its hex encoded SHA-256 hash
+	Key       string `json:"key,omitempty" yaml:"key" toml:"key"`
+	KeySHA256 string `json:"key_sha256,omitempty" yaml:"key_sha256" toml:"key_sha256"`
+	// ZabbixURL and ZabbixToken, when set, override headers and environment.
+	// Binding either one disables header overrides of both, so a pinned token
+	// is never sent to a caller-chosen URL.
+	ZabbixURL   string `json:"zabbix_url,omitempty" yaml:"zabbix_url" toml:"zabbix_url"`
+	ZabbixToken string `json:"zabbix_token,omitempty" yaml:"zabbix_token" toml:"zabbix_token"`
+	// Admin lets the key read the audit records of every principal
+	Admin bool `json:"admin,omitempty" yaml:"admin" toml:"admin"`
+}
+
+// AuthConfig holds API key and HMAC bearer token settings
+type AuthConfig struct {
+	Keys       []AuthKey `json:"keys"`
+	HMACSecret string    `json:"hmac_secret,omitempty"`
+}
+
+// Enabled reports whether any authentication method is configured
+func (c AuthConfig) Enabled() bool {
+	return len(c.Keys) > 0 || c.HMACSecret != ""
+}
+
+// LoadAuthKeysFile reads a JSON file of API keys and an HMAC secret
+func LoadAuthKeysFile(path string) (AuthConfig, error) {
+	var config AuthConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to read auth keys file: %w", err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse auth keys file: %w", err)
+	}
+	return config, nil
+}
+
+// Validate checks that every key has a name and a plaintext key or a valid
+// SHA-256 hash
+func (c AuthConfig) Validate() error {
+	for i, key := range c.Keys {
+		if key.Name == "" {
+			return fmt.Errorf("auth key %d has no name", i)
+		}
+		if key.Key == "" && key.KeySHA256 == "" {
+			return fmt.Errorf("auth key %q has neither key nor key_sha256", key.Name)
+		}
+		if key.KeySHA256 != "" {
+			if _, err := hex.DecodeString(key.KeySHA256); err != nil || len(key.KeySHA256) != sha256.Size*2 {
+				return fmt.Errorf("auth key %q has an invalid key_sha256", key.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// CredentialAuthenticator validates the credential presented with a request
+// and returns the key entry (principal and Zabbix credentials) it maps to
+type CredentialAuthenticator interface {
+	Authenticate(credential string) (*AuthKey, error)
+}
+
+// challenger is implemented by authenticators that customize the
+// WWW-Authenticate header sent with 401 responses
+type challenger interface {
+	Challenge() string
+}
+
+// MultiAuthenticator accepts a credential if any of its authenticators does
+type MultiAuthenticator []CredentialAuthenticator
+
+// Authenticate tries each authenticator in order
+func (m MultiAuthenticator) Authenticate(credential string) (*AuthKey, error) {
+	err := fmt.Errorf("no authenticators configured")
+	for _, auth := range m {
+		var key *AuthKey
+		if key, err = auth.Authenticate(credential); err == nil {
+			return key, nil
+		}
+	}
+	return nil, err
+}
+
+// Challenge returns the challenge of the first authenticator that has one
+func (m MultiAuthenticator) Challenge() string {
+	for _, auth := range m {
+		if c, ok := auth.(challenger); ok {
+			return c.Challenge()
+		}
+	}
+	return defaultChallenge
+}
+
+const defaultChallenge = `Bearer realm="zabbix-mcp-server"`
+
+// Authenticator validates API keys and HMAC-signed bearer tokens
+type Authenticator struct {
+	keys   []AuthKey
+	hashes [][]byte
+	secret []byte
+}
+
+// NewAuthenticator creates an authenticator from the given configuration
+func NewAuthenticator(config AuthConfig) *Authenticator {
+	a := &Authenticator{
+		keys:   config.Keys,
+		hashes: make([][]byte, len(config.Keys)),
+	}
+	for i, key := range config.Keys {
+		if key.KeySHA256 != "" {
+			a.hashes[i], _ = hex.DecodeString(key.KeySHA256)
+		} else {
+			sum := sha256.Sum256([]byte(key.Key))
+			a.hashes[i] = sum[:]
+		}
+	}
+	if config.HMACSecret != "" {
+		a.secret = []byte(config.HMACSecret)
+	}
+	return a
+}
+
+// Authenticate returns the key entry matching the request credential
+func (a *Authenticator) Authenticate(credential string) (*AuthKey, error) {
+	if credential == "" {
+		return nil, fmt.Errorf("missing credentials")
+	}
+
+	sum := sha256.Sum256([]byte(credential))
+	var match *AuthKey
+	for i := range a.hashes {
+		// Compare against every key to keep timing independent of position
+		if subtle.ConstantTimeCompare(sum[:], a.hashes[i]) == 1 && match == nil {
+			match = &a.keys[i]
+		}
+	}
+	if match != nil {
+		return match, nil
+	}
+
+	// Fall back to HMAC-signed bearer tokens
+	if a.secret == nil || !strings.Contains(credential, ".") {
+		return nil, fmt.Errorf("invalid credentials")
+	}
+	subject, err := a.verifyToken(credential)
+	if err != nil {
+		return nil, err
+	}
+	for i := range a.keys {
+		if a.keys[i].Name == subject {
+			return &a.keys[i], nil
+		}
+	}
+	return &AuthKey{Name: subject}, nil
+}
+
+// bearerClaims is the payload of an HMAC-signed bearer token
+type bearerClaims struct {
+	Subject   string `json:"sub"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// SignBearerToken creates an HMAC-SHA256 signed bearer token for subject.
+// The token format is base64url(claims) "." base64url(signature).
+func SignBearerToken(secret string, subject string, ttl time.Duration) (string, error) {
+	if secret == "" {
+		return "", fmt.Errorf("hmac secret is required")
+	}
+	payload, err := json.Marshal(bearerClaims{
+		Subject:   subject,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(encoded))
+
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// verifyToken checks the signature and expiry of a bearer token and returns
+// its subject
+func (a *Authenticator) verifyToken(token string) (string, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", fmt.Errorf("malformed token")
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return "", fmt.Errorf("malformed token signature")
+	}
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(encoded))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return "", fmt.Errorf("invalid token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("malformed token payload")
+	}
+	var claims bearerClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("malformed token claims")
+	}
+	if claims.Subject == "" {
+		return "", fmt.Errorf("token has no subject")
+	}
+	if claims.ExpiresAt != 0 && time.Now().Unix() >= claims.ExpiresAt {
+		return "", fmt.Errorf("token expired")
+	}
+
+	return claims.Subject, nil
+}
+
+// AuthMiddleware rejects requests without a valid API key or bearer token and
+// binds the matching Zabbix credentials to the request context
+func AuthMiddleware(auth CredentialAuthenticator, logger *log.Logger, next http.Handler) http.Handler {
+	challenge := defaultChallenge
+	if c, ok := auth.(challenger); ok {
+		challenge = c.Challenge()
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		credential := r.Header.Get(APIKeyHeader)
+		if credential == "" {
+			if scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+				credential = strings.TrimSpace(value)
+			}
+		}
+
+		key, err := auth.Authenticate(credential)
+		if err != nil {
+			logger.WithFields(log.Fields{
+				"remote": r.RemoteAddr,
+				"path":   r.URL.Path,
+			}).WithError(err).Warn("Rejected unauthenticated request")
+
+			w.Header().Set("WWW-Authenticate", challenge)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := WithPrincipal(r.Context(), key.Name)
+		if key.Admin {
+			ctx = WithAdmin(ctx)
+		}
+		if key.ZabbixURL != "" || key.ZabbixToken != "" {
+			ctx = context.WithValue(ctx, credentialsBoundKey, true)
+		}
+		if key.ZabbixURL != "" {
+			ctx = context.WithValue(ctx, contextKey(ZabbixURL), key.ZabbixURL)
+		}
+		if key.ZabbixToken != "" {
+			ctx = context.WithValue(ctx, contextKey(ZabbixToken), key.ZabbixToken)
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// WithPrincipal returns a context carrying the authenticated principal
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal, if any
+func PrincipalFromContext(ctx context.Context) string {
+	principal, _ := ctx.Value(principalKey).(string)
+	return principal
+}
+
+// credentialsBound reports whether the request's auth key pins its Zabbix
+// credentials
+func credentialsBound(ctx context.Context) bool {
+	bound, _ := ctx.Value(credentialsBoundKey).(bool)
+	return bound
+}
+
+// WithAdmin returns a context marking the principal as an administrator
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey, true)
+}
+
+// IsAdmin reports whether the principal authenticated with an admin key
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey).(bool)
+	return admin
+}
@@ -0,0 +1,256 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	auth := client.NewAuthenticator(client.AuthConfig{
+		Keys:       []client.AuthKey{{Name: "noc", Key: "noc-key"}, {Name: "auditor", Key: "auditor-key", Admin: true}},
+		HMACSecret: "secret",
+	})
+	var principal string
+	var admin bool
+	handler := client.AuthMiddleware(auth, zabbixtest.Logger(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal = client.PrincipalFromContext(r.Context())
+		admin = client.IsAdmin(r.Context())
+	}))
+
+	signed, err := client.SignBearerToken("secret", "robot", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, _ := client.SignBearerToken("secret", "robot", -time.Minute)
+	forged, _ := client.SignBearerToken("other-secret", "robot", time.Hour)
+	payload, _, _ := strings.Cut(signed, ".")
+	_, signature, _ := strings.Cut(forged, ".")
+
+	cases := []struct {
+		name          string
+		header        string
+		value         string
+		wantPrincipal string
+		wantAdmin     bool
+	}{
+		{name: "api key header", header: client.APIKeyHeader, value: "noc-key", wantPrincipal: "noc"},
+		{name: "api key bearer", header: "Authorization", value: "Bearer noc-key", wantPrincipal: "noc"},
+		{name: "admin key", header: client.APIKeyHeader, value: "auditor-key", wantPrincipal: "auditor", wantAdmin: true},
+		{name: "signed token", header: "Authorization", value: "Bearer " + signed, wantPrincipal: "robot"},
+		{name: "missing credentials"},
+		{name: "unknown key", header: client.APIKeyHeader, value: "guess"},
+		{name: "expired token", header: "Authorization", value: "Bearer " + expired},
+		{name: "wrong secret", header: "Authorization", value: "Bearer " + forged},
+		{name: "tampered payload", header: "Authorization", value: "Bearer " + payload + "." + signature},
+		{name: "basic scheme", header: "Authorization", value: "Basic noc-key"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			principal, admin = "", false
+			r := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader("{}"))
+			if tc.header != "" {
+				r.Header.Set(tc.header, tc.value)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if tc.wantPrincipal == "" {
+				if w.Code != http.StatusUnauthorized {
+					t.Fatalf("expected HTTP 401, got %d", w.Code)
+				}
+				if got := w.Header().Get("WWW-Authenticate"); !strings.HasPrefix(got, "Bearer ") {
+					t.Errorf("expected a Bearer challenge, got %q", got)
+				}
+				return
+			}
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected HTTP 200, got %d", w.Code)
+			}
+			if principal != tc.wantPrincipal || admin != tc.wantAdmin {
+				t.Errorf("expected principal %q (admin %v), got %q (admin %v)", tc.wantPrincipal, tc.wantAdmin, principal, admin)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareIgnoresOverridesForBoundKeys(t *testing.T) {
+	pinned := zabbixtest.NewServer(t)
+	other := zabbixtest.NewServer(t)
+	other.AddToken("caller-token")
+	zabbixtest.UseConnection(t, func(c *client.ConnectionConfig) { c.URL = pinned.APIURL() })
+
+	auth := client.NewAuthenticator(client.AuthConfig{Keys: []client.AuthKey{
+		// The key pins only the token; the URL comes from the environment
+		{Name: "noc", Key: "noc-key", ZabbixToken: zabbixtest.Token},
+		{Name: "dev", Key: "dev-key"},
+	}})
+	var callErr error
+	tools := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session := zabbixtest.NewSession()
+		t.Cleanup(func() { client.DeleteZabbixClient(session.ID) })
+		zc, err := client.CreateZabbixClientForSession(r.Context(), session, zabbixtest.Logger())
+		if err == nil {
+			_, err = zc.CallContext(r.Context(), "host.get", map[string]interface{}{})
+		}
+		callErr = err
+	})
+	handler := client.AuthMiddleware(auth, zabbixtest.Logger(), client.ZabbixContextMiddleware(zabbixtest.Logger(), tools))
+
+	request := func(key, target string, header bool) {
+		t.Helper()
+		pinned.Reset()
+		other.Reset()
+		r := httptest.NewRequest(http.MethodPost, target, strings.NewReader("{}"))
+		r.Header.Set(client.APIKeyHeader, key)
+		if header {
+			r.Header.Set(client.ZabbixHeaderURL, other.APIURL())
+			r.Header.Set(client.ZabbixHeaderToken, "caller-token")
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+		if callErr != nil {
+			t.Fatalf("host.get failed: %v", callErr)
+		}
+	}
+
+	request("noc-key", "/mcp", true)
+	if len(other.Requests()) != 0 {
+		t.Error("the pinned token was sent to the X-Zabbix-URL header")
+	}
+	if calls := pinned.Calls("host.get"); len(calls) != 1 || calls[0].Auth != zabbixtest.Token {
+		t.Errorf("expected host.get with the pinned token, got %+v", calls)
+	}
+
+	request("noc-key", "/mcp?ZABBIX_URL="+other.APIURL(), false)
+	if len(other.Requests()) != 0 {
+		t.Error("the pinned token was sent to the ZABBIX_URL query parameter")
+	}
+
+	// Keys without pinned credentials still honor the headers
+	request("dev-key", "/mcp", true)
+	if calls := other.Calls("host.get"); len(calls) != 1 || calls[0].Auth != "caller-token" {
+		t.Errorf("expected host.get with the caller token, got %+v", calls)
+	}
+}
+
+func TestRequestURLRequiresRequestToken(t *testing.T) {
+	configured := zabbixtest.NewServer(t)
+	configured.AddUser("Admin", "zabbix")
+	other := zabbixtest.NewServer(t)
+	other.AddUser("Admin", "zabbix")
+
+	auth := client.NewAuthenticator(client.AuthConfig{Keys: []client.AuthKey{{Name: "dev", Key: "dev-key"}}})
+	var callErr error
+	tools := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session := zabbixtest.NewSession()
+		t.Cleanup(func() { client.DeleteZabbixClient(session.ID) })
+		_, callErr = client.CreateZabbixClientForSession(r.Context(), session, zabbixtest.Logger())
+	})
+	handler := client.AuthMiddleware(auth, zabbixtest.Logger(), client.ZabbixContextMiddleware(zabbixtest.Logger(), tools))
+
+	request := func(target string, header bool) {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodPost, target, strings.NewReader("{}"))
+		r.Header.Set(client.APIKeyHeader, "dev-key")
+		if header {
+			r.Header.Set(client.ZabbixHeaderURL, other.APIURL())
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	for _, connection := range []func(c *client.ConnectionConfig){
+		func(c *client.ConnectionConfig) { c.URL = configured.APIURL(); c.Token = zabbixtest.Token },
+		func(c *client.ConnectionConfig) { c.URL = configured.APIURL(); c.User = "Admin"; c.Password = "zabbix" },
+	} {
+		zabbixtest.UseConnection(t, connection)
+		other.Reset()
+
+		request("/mcp", true)
+		if callErr == nil || !strings.Contains(callErr.Error(), "token is required") {
+			t.Errorf("expected the X-Zabbix-URL header without a token to be refused, got %v", callErr)
+		}
+		request("/mcp?ZABBIX_URL="+other.APIURL(), false)
+		if callErr == nil {
+			t.Error("expected the ZABBIX_URL query parameter without a token to be refused")
+		}
+		if requests := other.Requests(); len(requests) != 0 {
+			t.Errorf("the configured credentials were sent to the caller's URL: %+v", requests)
+		}
+
+		// The configured server still uses the configured credentials
+		request("/mcp", false)
+		if callErr != nil {
+			t.Errorf("expected the configured connection to work, got %v", callErr)
+		}
+	}
+}
+
+func TestBoundKeysCannotUseInstances(t *testing.T) {
+	pinned := zabbixtest.NewServer(t)
+	instance := zabbixtest.NewServer(t)
+	instance.AddToken("instance-token")
+	client.SetInstances(client.InstancesConfig{
+		Default:   "eu",
+		Instances: []client.Instance{{Name: "eu", URL: instance.APIURL(), Token: "instance-token"}},
+	})
+	t.Cleanup(func() { client.SetInstances(client.InstancesConfig{}) })
+
+	auth := client.NewAuthenticator(client.AuthConfig{Keys: []client.AuthKey{
+		{Name: "noc", Key: "noc-key", ZabbixURL: pinned.APIURL(), ZabbixToken: zabbixtest.Token},
+	}})
+	var target string
+	var callErr error
+	tools := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session := zabbixtest.NewSession()
+		t.Cleanup(func() { client.DeleteZabbixClient(session.ID) })
+		ctx := zabbixtest.WithSession(r.Context(), session)
+		if target != "" {
+			ctx = client.WithInstance(ctx, target)
+		}
+		zc, err := client.GetZabbixClientFromContext(ctx, zabbixtest.Logger())
+		if err == nil {
+			_, err = zc.CallContext(ctx, "host.get", map[string]interface{}{})
+		}
+		callErr = err
+	})
+	handler := client.AuthMiddleware(auth, zabbixtest.Logger(), tools)
+
+	request := func() {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader("{}"))
+		r.Header.Set(client.APIKeyHeader, "noc-key")
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	// An explicit instance is refused
+	target = "eu"
+	request()
+	if callErr == nil {
+		t.Fatal("expected a pinned key to be refused the eu instance")
+	}
+	if len(instance.Requests()) != 0 {
+		t.Error("the pinned key reached the instance with its credentials")
+	}
+
+	// The default instance does not replace the pinned server
+	target = ""
+	request()
+	if callErr != nil {
+		t.Fatalf("host.get failed: %v", callErr)
+	}
+	if calls := pinned.Calls("host.get"); len(calls) != 1 || calls[0].Auth != zabbixtest.Token {
+		t.Errorf("expected host.get on the pinned server, got %+v", calls)
+	}
+	if len(instance.Requests()) != 0 {
+		t.Error("the pinned key was routed to the default instance")
+	}
+}
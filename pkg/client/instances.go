@@ -0,0 +1,255 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// Named instance environment variables
+const (
+	ZabbixInstancesFile = "ZABBIX_INSTANCES_FILE"
+	ZabbixInstances     = "ZABBIX_INSTANCES"
+)
+
+// instanceContextKey is the context key for the instance a tool call targets
+const instanceContextKey contextKey = "zabbix_instance"
+
+// Instance is a named Zabbix server that tools can target with their
+// instance argument
+type Instance struct {
+	Name        string `json:"name" yaml:"name" toml:"name"`
+	Description string `json:"description,omitempty" yaml:"description" toml:"description"`
+	URL         string `json:"url" yaml:"url" toml:"url"`
+	// Token is an API token; User and Password are used with user.login
+	// when it is empty
+	Token         string `json:"token,omitempty" yaml:"token" toml:"token"`
+	User          string `json:"user,omitempty" yaml:"user" toml:"user"`
+	Password      string `json:"password,omitempty" yaml:"password" toml:"password"`
+	SkipTLSVerify bool   `json:"skip_tls_verify,omitempty" yaml:"skip_tls_verify" toml:"skip_tls_verify"`
+	// TLS holds the CA bundle, client certificate and other TLS settings
+	TLS TLSConfig `json:"tls" yaml:"tls" toml:"tls"`
+}
+
+// InstancesConfig lists the named Zabbix instances
+type InstancesConfig struct {
+	// Default is the instance used by tool calls without an instance
+	// argument. When empty they use the session's own connection.
+	Default   string     `json:"default,omitempty"`
+	Instances []Instance `json:"instances"`
+}
+
+// Names returns the instance names in configuration order
+func (c InstancesConfig) Names() []string {
+	names := make([]string, 0, len(c.Instances))
+	for _, instance := range c.Instances {
+		names = append(names, instance.Name)
+	}
+	return names
+}
+
+// Lookup returns the instance called name
+func (c InstancesConfig) Lookup(name string) (Instance, bool) {
+	for _, instance := range c.Instances {
+		if instance.Name == name {
+			return instance, true
+		}
+	}
+	return Instance{}, false
+}
+
+// Validate checks that every instance has a unique name, a URL and
+// credentials, and that the default instance exists
+func (c InstancesConfig) Validate() error {
+	seen := make(map[string]bool)
+	for i, instance := range c.Instances {
+		switch {
+		case instance.Name == "":
+			return fmt.Errorf("instance %d has no name", i)
+		case seen[instance.Name]:
+			return fmt.Errorf("instance %q is defined more than once", instance.Name)
+		case instance.URL == "":
+			return fmt.Errorf("instance %q has no url", instance.Name)
+		case instance.Token == "" && (instance.User == "" || instance.Password == ""):
+			return fmt.Errorf("instance %q needs a token or a user and password", instance.Name)
+		}
+		if err := instance.TLS.Validate(); err != nil {
+			return fmt.Errorf("instance %q: %w", instance.Name, err)
+		}
+		seen[instance.Name] = true
+	}
+	if c.Default != "" && !seen[c.Default] {
+		return fmt.Errorf("default instance %q is not defined", c.Default)
+	}
+	return nil
+}
+
+// LoadInstancesConfig reads and validates a JSON instances file
+func LoadInstancesConfig(path string) (InstancesConfig, error) {
+	var config InstancesConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to read instances file: %w", err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse instances file: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return config, fmt.Errorf("invalid instances file: %w", err)
+	}
+
+	return config, nil
+}
+
+// instances holds the configured named instances
+var instances atomic.Pointer[InstancesConfig]
+
+// SetInstances replaces the named instances available to every session
+func SetInstances(config InstancesConfig) {
+	instances.Store(&config)
+}
+
+// Instances returns the configured named instances
+func Instances() InstancesConfig {
+	if config := instances.Load(); config != nil {
+		return *config
+	}
+	return InstancesConfig{}
+}
+
+// WithInstance returns a context whose tool calls target the named instance
+func WithInstance(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, instanceContextKey, name)
+}
+
+// InstanceFromContext returns the instance a tool call targets, or the
+// default instance when the call did not name one. Requests whose auth key
+// pins Zabbix credentials never fall back to the default instance.
+func InstanceFromContext(ctx context.Context) string {
+	if name, ok := ctx.Value(instanceContextKey).(string); ok && name != "" {
+		return name
+	}
+	if credentialsBound(ctx) {
+		return ""
+	}
+	return Instances().Default
+}
+
+// instanceKey identifies the client a session uses for a named instance
+type instanceKey struct {
+	sessionID string
+	instance  string
+}
+
+// getInstanceClient returns the session's client for a named instance,
+// connecting on first use. Requests whose auth key pins Zabbix credentials
+// may not use an instance's credentials instead.
+func getInstanceClient(ctx context.Context, session server.ClientSession, name string, logger *log.Logger) (*ZabbixClient, error) {
+	if credentialsBound(ctx) {
+		return nil, fmt.Errorf("the authenticated key is bound to its own Zabbix server and cannot use instance %q", name)
+	}
+
+	key := instanceKey{sessionID: session.SessionID(), instance: name}
+	principal := PrincipalFromContext(ctx)
+
+	if value, ok := activeClients.Load(key); ok {
+		client := value.(*ZabbixClient)
+		if principal != client.Principal {
+			return nil, fmt.Errorf("session does not belong to the authenticated principal")
+		}
+		client.touch()
+		return client, nil
+	}
+
+	config := Instances()
+	instance, ok := config.Lookup(name)
+	if !ok {
+		known := config.Names()
+		sort.Strings(known)
+		return nil, fmt.Errorf("unknown Zabbix instance %q (configured: %s)", name, strings.Join(known, ", "))
+	}
+
+	tlsConfig := instance.TLS
+	tlsConfig.SkipVerify = instance.SkipTLSVerify
+	client, err := newZabbixClient(instance.URL, tlsConfig, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Zabbix instance %q: %w", name, err)
+	}
+	client.AuthToken = instance.Token
+	client.Username = instance.User
+	client.Password = instance.Password
+	client.Principal = principal
+	client.Instance = name
+	client.detectVersion(ctx)
+	if client.AuthToken == "" {
+		if err := client.Login(ctx); err != nil {
+			return nil, fmt.Errorf("failed to connect to Zabbix instance %q: %w", name, err)
+		}
+	}
+
+	// A concurrent call may have connected first; keep its client
+	if value, loaded := activeClients.LoadOrStore(key, client); loaded {
+		if err := client.Logout(ctx); err != nil {
+			logger.WithError(err).WithField("instance", name).Warn("Failed to log out of duplicate Zabbix session")
+		}
+		return value.(*ZabbixClient), nil
+	}
+
+	logger.WithFields(log.Fields{
+		"session_id": session.SessionID(),
+		"instance":   name,
+		"zabbix_url": instance.URL,
+	}).Info("Created Zabbix client for instance")
+
+	return client, nil
+}
+
+// SessionVersions returns the Zabbix version behind every client a
+// session's tool calls can reach: the default client unless a default
+// instance replaces it, and each configured instance. Clients the session
+// has not connected yet report an unknown version.
+func SessionVersions(sessionID string) []Version {
+	config := Instances()
+	var versions []Version
+	if config.Default == "" {
+		var version Version
+		if client := GetZabbixClient(sessionID); client != nil {
+			version = client.Version
+		}
+		versions = append(versions, version)
+	}
+	for _, name := range config.Names() {
+		var version Version
+		if value, ok := activeClients.Load(instanceKey{sessionID: sessionID, instance: name}); ok {
+			version = value.(*ZabbixClient).Version
+		}
+		versions = append(versions, version)
+	}
+	return versions
+}
+
+// deleteInstanceClients logs out and removes every instance client of a session
+func deleteInstanceClients(ctx context.Context, sessionID string, logger *log.Logger) {
+	activeClients.Range(func(k, value any) bool {
+		key, ok := k.(instanceKey)
+		if !ok || key.sessionID != sessionID {
+			return true
+		}
+		if err := value.(*ZabbixClient).Logout(ctx); err != nil {
+			logger.WithError(err).WithField("instance", key.instance).Warn("Failed to log out of Zabbix instance")
+		}
+		activeClients.Delete(key)
+		return true
+	})
+}
@@ -0,0 +1,195 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultCertPollInterval is how often the listener certificate files are
+// checked for changes
+const DefaultCertPollInterval = 10 * time.Second
+
+// ListenerTLSConfig holds the certificate the HTTP transport serves and the
+// CA used to verify client certificates
+type ListenerTLSConfig struct {
+	CertFile string `yaml:"cert_file" toml:"cert_file"`
+	KeyFile  string `yaml:"key_file" toml:"key_file"`
+	// ClientCAFile is a PEM bundle; when set, clients must present a
+	// certificate signed by it
+	ClientCAFile string `yaml:"client_ca_file" toml:"client_ca_file"`
+}
+
+// Enabled reports whether the listener serves TLS
+func (c ListenerTLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+// Validate checks the settings without reading the files
+func (c ListenerTLSConfig) Validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("cert_file and key_file must be set together")
+	}
+	if c.ClientCAFile != "" && !c.Enabled() {
+		return fmt.Errorf("client_ca_file requires cert_file and key_file")
+	}
+	return nil
+}
+
+// load reads the certificate and client CA files
+func (c ListenerTLSConfig) load() (*tls.Certificate, *x509.CertPool, error) {
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	if c.ClientCAFile == "" {
+		return &cert, nil, nil
+	}
+
+	data, err := os.ReadFile(c.ClientCAFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read TLS client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, nil, fmt.Errorf("TLS client CA file %s contains no PEM certificates", c.ClientCAFile)
+	}
+	return &cert, pool, nil
+}
+
+// Check validates the settings and loads the files once
+func (c ListenerTLSConfig) Check() error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if !c.Enabled() {
+		return nil
+	}
+	_, _, err := c.load()
+	return err
+}
+
+// CertReloader serves the listener certificate and client CAs, reloading
+// them from disk without restarting the server
+type CertReloader struct {
+	config ListenerTLSConfig
+	logger *log.Logger
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time
+}
+
+// NewCertReloader loads the files of config
+func NewCertReloader(config ListenerTLSConfig, logger *log.Logger) (*CertReloader, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	r := &CertReloader{config: config, logger: logger}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload reads the files again. On error the previous certificate is kept.
+func (r *CertReloader) Reload() error {
+	modTimes := r.stat()
+	cert, clientCAs, err := r.config.load()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.cert = cert
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+	r.mu.Unlock()
+
+	r.logger.WithField("cert_file", r.config.CertFile).Debug("Loaded TLS certificate")
+	return nil
+}
+
+// Watch reloads the files whenever one of them changes, checking every
+// interval until ctx is done
+func (r *CertReloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !r.changed() {
+				continue
+			}
+			if err := r.Reload(); err != nil {
+				r.logger.WithError(err).Error("Failed to reload TLS certificate, keeping the previous one")
+				continue
+			}
+			r.logger.WithField("cert_file", r.config.CertFile).Info("Reloaded TLS certificate")
+		}
+	}
+}
+
+// TLSConfig returns a server configuration that always uses the latest
+// loaded certificate and client CAs
+func (r *CertReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+			if r.clientCAs != nil {
+				config.ClientCAs = r.clientCAs
+				config.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+			return config, nil
+		},
+	}
+}
+
+// stat returns the modification times of the files
+func (r *CertReloader) stat() map[string]time.Time {
+	modTimes := make(map[string]time.Time)
+	for _, file := range []string{r.config.CertFile, r.config.KeyFile, r.config.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+		if info, err := os.Stat(file); err == nil {
+			modTimes[file] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+// changed reports whether a file was modified since the last load
+func (r *CertReloader) changed() bool {
+	modTimes := r.stat()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for file, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
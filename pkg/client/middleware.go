@@ -5,11 +5,18 @@ package client
 
 import (
 	"context"
+	"math"
+	"net"
 	"net/http"
-	"os"
-	"strings"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/metrics"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tracing"
 	"golang.org/x/time/rate"
 )
 
@@ -28,33 +35,56 @@ type CORSConfig struct {
 	AllowedOrigins []string
 }
 
-// RateLimitConfig holds rate limiting configuration
+// RateLimitConfig holds rate limiting configuration. A non-positive RPS
+// disables the corresponding limiter.
 type RateLimitConfig struct {
 	GlobalRPS    float64
 	GlobalBurst  int
 	SessionRPS   float64
 	SessionBurst int
+	// IPRPS and IPBurst limit requests per client IP before authentication,
+	// so failed attempts are limited as well
+	IPRPS   float64
+	IPBurst int
+	// ZabbixRPS and ZabbixBurst limit outbound calls per Zabbix URL
+	ZabbixRPS   float64
+	ZabbixBurst int
 }
 
-// GetCORSConfig returns the CORS configuration from environment
-func GetCORSConfig() CORSConfig {
-	mode := CORSMode(getEnv("MCP_CORS_MODE", "strict"))
-	originsStr := getEnv("MCP_ALLOWED_ORIGINS", "")
-
-	var origins []string
-	if originsStr != "" {
-		origins = strings.Split(originsStr, ",")
-		for i := range origins {
-			origins[i] = strings.TrimSpace(origins[i])
-		}
-	}
+// Rate limit environment variables
+const (
+	RateLimitGlobalRPS    = "MCP_RATE_LIMIT_GLOBAL_RPS"
+	RateLimitGlobalBurst  = "MCP_RATE_LIMIT_GLOBAL_BURST"
+	RateLimitSessionRPS   = "MCP_RATE_LIMIT_SESSION_RPS"
+	RateLimitSessionBurst = "MCP_RATE_LIMIT_SESSION_BURST"
+	RateLimitIPRPS        = "MCP_RATE_LIMIT_IP_RPS"
+	RateLimitIPBurst      = "MCP_RATE_LIMIT_IP_BURST"
+	RateLimitZabbixRPS    = "ZABBIX_RATE_LIMIT_RPS"
+	RateLimitZabbixBurst  = "ZABBIX_RATE_LIMIT_BURST"
+	CORSModeEnv           = "MCP_CORS_MODE"
+	AllowedOriginsEnv     = "MCP_ALLOWED_ORIGINS"
+)
 
-	return CORSConfig{
-		Mode:           mode,
-		AllowedOrigins: origins,
-	}
+// DefaultRateLimitConfig holds the rate limits used for settings that are
+// not configured
+var DefaultRateLimitConfig = RateLimitConfig{
+	GlobalRPS:    10,
+	GlobalBurst:  20,
+	SessionRPS:   5,
+	SessionBurst: 10,
+	IPRPS:        20,
+	IPBurst:      40,
+	ZabbixRPS:    20,
+	ZabbixBurst:  40,
 }
 
+// sessionLimiterIdle is how long an unused per-session limiter is kept
+const sessionLimiterIdle = 10 * time.Minute
+
+// maxSessionLimiters caps the per-session limiters kept at once; the least
+// recently used one is dropped to make room
+const maxSessionLimiters = 10000
+
 // CORSMiddleware adds CORS headers to responses
 func CORSMiddleware(config CORSConfig, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -67,7 +97,7 @@ func CORSMiddleware(config CORSConfig, next http.Handler) http.Handler {
 			// Allow all origins in development
 			w.Header().Set("Access-Control-Allow-Origin", origin)
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Zabbix-Token, X-Zabbix-URL")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Zabbix-Token, X-Zabbix-URL")
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
 		case CORSModeStrict:
 			// Only allow configured origins
@@ -75,7 +105,7 @@ func CORSMiddleware(config CORSConfig, next http.Handler) http.Handler {
 				if origin == allowed {
 					w.Header().Set("Access-Control-Allow-Origin", origin)
 					w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-					w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Zabbix-Token, X-Zabbix-URL")
+					w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Zabbix-Token, X-Zabbix-URL")
 					w.Header().Set("Access-Control-Allow-Credentials", "true")
 					break
 				}
@@ -92,10 +122,16 @@ func CORSMiddleware(config CORSConfig, next http.Handler) http.Handler {
 	})
 }
 
-// ZabbixContextMiddleware extracts Zabbix configuration from request headers and adds to context
+// ZabbixContextMiddleware extracts Zabbix configuration from request headers and adds to context.
+// Requests whose auth key pins Zabbix credentials ignore the headers entirely,
+// so a bound token cannot be redirected to another URL.
 func ZabbixContextMiddleware(logger *log.Logger, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if credentialsBound(ctx) {
+			next.ServeHTTP(w, r)
+			return
+		}
 
 		// Extract from headers
 		if url := r.Header.Get(ZabbixHeaderURL); url != "" {
@@ -117,7 +153,7 @@ func ZabbixContextMiddleware(logger *log.Logger, next http.Handler) http.Handler
 // LoggingMiddleware logs HTTP requests
 func LoggingMiddleware(logger *log.Logger, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		logger.WithFields(log.Fields{
+		logging.FromContext(r.Context(), logger).WithFields(log.Fields{
 			"method": r.Method,
 			"path":   r.URL.Path,
 			"remote": r.RemoteAddr,
@@ -127,33 +163,199 @@ func LoggingMiddleware(logger *log.Logger, next http.Handler) http.Handler {
 	})
 }
 
-// RateLimiter provides rate limiting functionality
+// RateLimiter provides global and per-session token-bucket rate limiting
 type RateLimiter struct {
-	global  *rate.Limiter
-	session map[string]*rate.Limiter
+	config RateLimitConfig
+	global *rate.Limiter
+	// scope labels the per-caller bucket in the rate limit metric
+	scope string
+
+	mu          sync.Mutex
+	session     map[string]*sessionLimiter
+	lastCleanup time.Time
+}
+
+// sessionLimiter is a per-session limiter with its last use time
+type sessionLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
 }
 
 // NewRateLimiter creates a new rate limiter
 func NewRateLimiter(config RateLimitConfig) *RateLimiter {
-	return &RateLimiter{
-		global:  rate.NewLimiter(rate.Limit(config.GlobalRPS), config.GlobalBurst),
-		session: make(map[string]*rate.Limiter),
+	rl := &RateLimiter{
+		config:      config,
+		scope:       "session",
+		session:     make(map[string]*sessionLimiter),
+		lastCleanup: time.Now(),
 	}
+	if config.GlobalRPS > 0 {
+		rl.global = rate.NewLimiter(rate.Limit(config.GlobalRPS), config.GlobalBurst)
+	}
+	return rl
+}
+
+// NewIPRateLimiter creates a limiter with one bucket per client IP, sized by
+// the IP settings of config
+func NewIPRateLimiter(config RateLimitConfig) *RateLimiter {
+	rl := NewRateLimiter(RateLimitConfig{SessionRPS: config.IPRPS, SessionBurst: config.IPBurst})
+	rl.scope = "ip"
+	return rl
 }
 
 // Allow checks if a request is allowed
 func (rl *RateLimiter) Allow(sessionID string) bool {
-	return rl.global.Allow()
+	allowed, _ := rl.Reserve(sessionID)
+	return allowed
+}
+
+// Reserve checks the per-session and global buckets. When the request is
+// rejected it returns how long the caller should wait before retrying.
+func (rl *RateLimiter) Reserve(sessionID string) (bool, time.Duration) {
+	now := time.Now()
+
+	var sessionRes *rate.Reservation
+	if limiter := rl.sessionLimiter(sessionID, now); limiter != nil {
+		sessionRes = limiter.ReserveN(now, 1)
+		if delay := reservationDelay(sessionRes, now); delay > 0 {
+			sessionRes.CancelAt(now)
+			metrics.RateLimited(rl.scope)
+			return false, delay
+		}
+	}
+
+	if rl.global != nil {
+		globalRes := rl.global.ReserveN(now, 1)
+		if delay := reservationDelay(globalRes, now); delay > 0 {
+			globalRes.CancelAt(now)
+			if sessionRes != nil {
+				sessionRes.CancelAt(now)
+			}
+			metrics.RateLimited("global")
+			return false, delay
+		}
+	}
+
+	return true, 0
 }
 
-// GetRateLimitConfig returns rate limit configuration from environment
-func GetRateLimitConfig() RateLimitConfig {
-	return RateLimitConfig{
-		GlobalRPS:    10,
-		GlobalBurst:  20,
-		SessionRPS:   5,
-		SessionBurst: 10,
+// sessionLimiter returns the limiter for a session, creating it on first use
+func (rl *RateLimiter) sessionLimiter(sessionID string, now time.Time) *rate.Limiter {
+	if rl.config.SessionRPS <= 0 || sessionID == "" {
+		return nil
 	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if now.Sub(rl.lastCleanup) > time.Minute {
+		for id, sl := range rl.session {
+			if now.Sub(sl.lastSeen) > sessionLimiterIdle {
+				delete(rl.session, id)
+			}
+		}
+		rl.lastCleanup = now
+	}
+
+	sl, ok := rl.session[sessionID]
+	if !ok {
+		if len(rl.session) >= maxSessionLimiters {
+			rl.evictOldest()
+		}
+		sl = &sessionLimiter{
+			limiter: rate.NewLimiter(rate.Limit(rl.config.SessionRPS), rl.config.SessionBurst),
+		}
+		rl.session[sessionID] = sl
+	}
+	sl.lastSeen = now
+
+	return sl.limiter
+}
+
+// evictOldest drops the least recently used per-session limiter. The caller
+// must hold rl.mu.
+func (rl *RateLimiter) evictOldest() {
+	var oldestID string
+	var oldest time.Time
+	for id, sl := range rl.session {
+		if oldestID == "" || sl.lastSeen.Before(oldest) {
+			oldestID, oldest = id, sl.lastSeen
+		}
+	}
+	delete(rl.session, oldestID)
+}
+
+// reservationDelay returns how long a reservation must wait; an impossible
+// reservation (burst of zero) is reported as one second
+func reservationDelay(r *rate.Reservation, now time.Time) time.Duration {
+	if !r.OK() {
+		return time.Second
+	}
+	return r.DelayFrom(now)
+}
+
+// RateLimitMiddleware rejects requests over the configured rate with HTTP 429.
+// Requests are keyed on the authenticated principal and its MCP session,
+// falling back to the remote IP, so it must run after AuthMiddleware.
+func RateLimitMiddleware(limiter *RateLimiter, logger *log.Logger, next http.Handler) http.Handler {
+	return rateLimitHandler(limiter, rateLimitKey, logger, next)
+}
+
+// IPRateLimitMiddleware rejects requests over the per-IP rate with HTTP 429.
+// It runs before AuthMiddleware, so unauthenticated requests and guessed
+// credentials are limited too.
+func IPRateLimitMiddleware(limiter *RateLimiter, logger *log.Logger, next http.Handler) http.Handler {
+	return rateLimitHandler(limiter, remoteIPKey, logger, next)
+}
+
+// rateLimitHandler rejects requests whose key has no tokens left
+func rateLimitHandler(limiter *RateLimiter, keyOf func(*http.Request) string, logger *log.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := keyOf(r)
+
+		if allowed, retryAfter := limiter.Reserve(key); !allowed {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+
+			logger.WithFields(log.Fields{
+				"key":         key,
+				"retry_after": seconds,
+			}).Warn("Rate limit exceeded")
+
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitKey identifies the session of a request for per-session limiting.
+// The Mcp-Session-Id header is chosen by the client, so it is only used
+// within an authenticated principal; anonymous requests are keyed on their
+// remote IP, or a client could sidestep its limit with a new session ID for
+// every request.
+func rateLimitKey(r *http.Request) string {
+	principal := PrincipalFromContext(r.Context())
+	if principal == "" {
+		return remoteIPKey(r)
+	}
+	if session := r.Header.Get(server.HeaderKeySessionID); session != "" {
+		return "principal:" + principal + ":session:" + session
+	}
+	return "principal:" + principal
+}
+
+// remoteIPKey identifies the caller of a request by its remote IP
+func remoteIPKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
 }
 
 // HealthHandler returns a health check handler
@@ -165,22 +367,44 @@ func HealthHandler(logger *log.Logger) http.HandlerFunc {
 	}
 }
 
+// HTTPConfig configures the middleware stack of the HTTP transport
+type HTTPConfig struct {
+	CORS      CORSConfig
+	RateLimit RateLimitConfig
+	Auth      AuthConfig
+	OAuth     OAuthConfig
+}
+
 // BuildMiddlewareStack builds the complete middleware stack for HTTP mode
-func BuildMiddlewareStack(handler http.Handler, logger *log.Logger) http.Handler {
-	corsConfig := GetCORSConfig()
+func BuildMiddlewareStack(handler http.Handler, config HTTPConfig, logger *log.Logger) http.Handler {
+	rateLimiter := NewRateLimiter(config.RateLimit)
+
+	// OAuth access tokens are tried before static API keys
+	var authenticators MultiAuthenticator
+	if config.OAuth.Enabled() {
+		authenticators = append(authenticators, NewJWTValidator(config.OAuth, logger))
+	}
+	if config.Auth.Enabled() {
+		authenticators = append(authenticators, NewAuthenticator(config.Auth))
+	}
 
 	// Apply middleware from outer to inner
 	handler = LoggingMiddleware(logger, handler)
 	handler = ZabbixContextMiddleware(logger, handler)
-	handler = CORSMiddleware(corsConfig, handler)
+	// Rate limits are keyed on the principal set by authentication
+	handler = RateLimitMiddleware(rateLimiter, logger, handler)
+	if len(authenticators) > 0 {
+		handler = AuthMiddleware(authenticators, logger, handler)
+	} else {
+		logger.Warn("HTTP authentication is disabled; anyone who can reach the endpoint can use the server's Zabbix credentials")
+	}
+	// Requests that fail authentication are limited per client IP
+	handler = IPRateLimitMiddleware(NewIPRateLimiter(config.RateLimit), logger, handler)
+	handler = CORSMiddleware(config.CORS, handler)
+	// Continue the caller's trace and assign the correlation ID before
+	// anything else runs
+	handler = tracing.Middleware(handler)
+	handler = logging.Middleware(handler)
 
 	return handler
 }
-
-// GetEnvWithFallback is an exported version of getEnv
-func GetEnvWithFallback(key, fallback string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return fallback
-}
@@ -0,0 +1,161 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	limiter := client.NewRateLimiter(client.RateLimitConfig{GlobalRPS: 1, GlobalBurst: 3, SessionRPS: 1, SessionBurst: 2})
+
+	for i := 0; i < 2; i++ {
+		if allowed, _ := limiter.Reserve("alice"); !allowed {
+			t.Fatalf("request %d within the session burst was rejected", i+1)
+		}
+	}
+	allowed, retryAfter := limiter.Reserve("alice")
+	if allowed || retryAfter <= 0 || retryAfter > time.Second {
+		t.Errorf("expected the session limit to apply, got %v %v", allowed, retryAfter)
+	}
+
+	// The rejected request did not use up the global bucket
+	if allowed, _ := limiter.Reserve("bob"); !allowed {
+		t.Error("expected another caller to be allowed")
+	}
+	if allowed, retryAfter := limiter.Reserve("carol"); allowed || retryAfter <= 0 {
+		t.Errorf("expected the global limit to apply, got %v %v", allowed, retryAfter)
+	}
+}
+
+func TestRateLimiterCapsCallers(t *testing.T) {
+	limiter := client.NewRateLimiter(client.RateLimitConfig{SessionRPS: 0.001, SessionBurst: 1})
+
+	limiter.Reserve("first")
+	if allowed, _ := limiter.Reserve("first"); allowed {
+		t.Fatal("expected the second request to be limited")
+	}
+	for i := 0; i < 10000; i++ {
+		limiter.Reserve(fmt.Sprintf("caller-%d", i))
+	}
+	// The oldest limiter made room for the others and starts over
+	if allowed, _ := limiter.Reserve("first"); !allowed {
+		t.Error("expected the least recently used limiter to be dropped")
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	limiter := client.NewRateLimiter(client.RateLimitConfig{SessionRPS: 0.5, SessionBurst: 1})
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := client.RateLimitMiddleware(limiter, zabbixtest.Logger(), ok)
+	authenticated := func(principal string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler.ServeHTTP(w, r.WithContext(client.WithPrincipal(r.Context(), principal)))
+		})
+	}
+
+	request := func(h http.Handler, remote, session string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader("{}"))
+		r.RemoteAddr = remote
+		r.Header.Set("Mcp-Session-Id", session)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := request(authenticated("alice"), "10.0.0.1:1000", "s1"); w.Code != http.StatusOK {
+		t.Fatalf("first request: HTTP %d", w.Code)
+	}
+	// A new address does not reset the session's bucket
+	w := request(authenticated("alice"), "10.0.0.2:1000", "s1")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "2" {
+		t.Errorf("expected HTTP 429 with Retry-After: 2, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	// Each session of a principal has its own bucket
+	if w := request(authenticated("alice"), "10.0.0.1:1000", "s2"); w.Code != http.StatusOK {
+		t.Errorf("expected another session of the principal to be allowed, got HTTP %d", w.Code)
+	}
+	// The session ID is scoped to the principal
+	if w := request(authenticated("bob"), "10.0.0.1:1000", "s1"); w.Code != http.StatusOK {
+		t.Errorf("expected another principal to be allowed, got HTTP %d", w.Code)
+	}
+
+	// Without a principal the remote IP is the key
+	if w := request(handler, "10.0.0.3:1000", "s3"); w.Code != http.StatusOK {
+		t.Fatalf("anonymous request: HTTP %d", w.Code)
+	}
+	if w := request(handler, "10.0.0.3:2000", "s4"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected a new session ID from the same IP to be limited, got HTTP %d", w.Code)
+	}
+}
+
+func TestMiddlewareStackLimitsFailedAuthentication(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := client.BuildMiddlewareStack(ok, client.HTTPConfig{
+		RateLimit: client.RateLimitConfig{SessionRPS: 100, SessionBurst: 100, IPRPS: 0.5, IPBurst: 2},
+		Auth:      client.AuthConfig{Keys: []client.AuthKey{{Name: "noc", Key: "noc-key"}}},
+	}, zabbixtest.Logger())
+
+	request := func(remote, key string) int {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader("{}"))
+		r.RemoteAddr = remote
+		r.Header.Set(client.APIKeyHeader, key)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// Guessed keys use up the IP's bucket before they reach authentication
+	for i := 0; i < 2; i++ {
+		if code := request("10.0.0.1:1000", fmt.Sprintf("guess-%d", i)); code != http.StatusUnauthorized {
+			t.Fatalf("guess %d: expected HTTP 401, got %d", i+1, code)
+		}
+	}
+	if code := request("10.0.0.1:1000", "noc-key"); code != http.StatusTooManyRequests {
+		t.Errorf("expected the IP to be limited, got HTTP %d", code)
+	}
+	if code := request("10.0.0.2:1000", "noc-key"); code != http.StatusOK {
+		t.Errorf("expected another IP to be allowed, got HTTP %d", code)
+	}
+}
+
+func TestOutboundLimiter(t *testing.T) {
+	zabbixtest.UseConnection(t, func(c *client.ConnectionConfig) {
+		c.RPS = 1
+		c.Burst = 2
+	})
+	s := zabbixtest.NewServer(t)
+
+	// Clients of one Zabbix URL share a limiter
+	var clients []*client.ZabbixClient
+	for i := 0; i < 2; i++ {
+		session := zabbixtest.NewSession()
+		zabbix, err := client.NewZabbixClient(context.Background(), session.ID, s.APIURL(), client.TLSConfig{}, zabbixtest.Token, zabbixtest.Logger())
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { client.DeleteZabbixClient(session.ID) })
+		clients = append(clients, zabbix)
+	}
+	if clients[0].Limiter == nil || clients[0].Limiter != clients[1].Limiter {
+		t.Fatal("expected the clients to share the outbound limiter of their URL")
+	}
+
+	// Version detection of both clients used up the burst
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := clients[1].CallContext(ctx, "host.get", map[string]interface{}{}); err == nil || !strings.Contains(err.Error(), "zabbix rate limit") {
+		t.Errorf("expected the call to wait for the limiter and time out, got %v", err)
+	}
+	s.AssertNotCalled(t, "host.get")
+}
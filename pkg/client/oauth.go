@@ -0,0 +1,621 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// OAuth resource server environment variables
+const (
+	OAuthIssuer         = "MCP_OAUTH_ISSUER"
+	OAuthAudience       = "MCP_OAUTH_AUDIENCE"
+	OAuthResource       = "MCP_OAUTH_RESOURCE"
+	OAuthJWKSFile       = "MCP_OAUTH_JWKS_FILE"
+	OAuthJWKSURL        = "MCP_OAUTH_JWKS_URL"
+	OAuthRequiredScopes = "MCP_OAUTH_REQUIRED_SCOPES"
+	OAuthClaimsFile     = "MCP_OAUTH_CLAIM_MAPPINGS_FILE"
+)
+
+// ProtectedResourceMetadataPath is the RFC 9728 well-known metadata path
+const ProtectedResourceMetadataPath = "/.well-known/oauth-protected-resource"
+
+// OAuthPrincipalPrefix is prepended to token principals so they cannot
+// collide with the names of API keys, e.g. in audit records and session
+// ownership checks
+const OAuthPrincipalPrefix = "oauth:"
+
+const (
+	// jwksRefreshInterval is how long fetched keys are cached
+	jwksRefreshInterval = time.Hour
+	// jwksMinRefresh limits refetching when an unknown key ID is seen
+	jwksMinRefresh = time.Minute
+	// jwtClockSkew is the tolerance applied to exp and nbf
+	jwtClockSkew = time.Minute
+)
+
+// ClaimMapping maps a token claim value to Zabbix credentials
+type ClaimMapping struct {
+	// Claim is the claim name, e.g. "sub" or "groups"
+	Claim string `json:"claim" yaml:"claim" toml:"claim"`
+	// Value must equal the claim, or one of its elements for array claims
+	Value       string `json:"value" yaml:"value" toml:"value"`
+	ZabbixURL   string `json:"zabbix_url,omitempty" yaml:"zabbix_url" toml:"zabbix_url"`
+	ZabbixToken string `json:"zabbix_token,omitempty" yaml:"zabbix_token" toml:"zabbix_token"`
+	// Admin lets matching tokens read the audit records of every principal
+	Admin bool `json:"admin,omitempty" yaml:"admin" toml:"admin"`
+}
+
+// ClaimMappingConfig controls how validated tokens map to Zabbix credentials
+type ClaimMappingConfig struct {
+	// PrincipalClaim names the claim used as principal (default "sub")
+	PrincipalClaim string `json:"principal_claim,omitempty" yaml:"principal_claim" toml:"principal_claim"`
+	// RequireMapping rejects tokens that match no mapping
+	RequireMapping bool           `json:"require_mapping,omitempty" yaml:"require_mapping" toml:"require_mapping"`
+	Mappings       []ClaimMapping `json:"mappings" yaml:"mappings" toml:"mappings"`
+}
+
+// OAuthConfig holds OAuth 2.1 resource server configuration
+type OAuthConfig struct {
+	Issuer         string
+	Audience       string
+	Resource       string
+	JWKSFile       string
+	JWKSURL        string
+	RequiredScopes []string
+	Claims         ClaimMappingConfig
+}
+
+// Enabled reports whether OAuth resource server mode is configured
+func (c OAuthConfig) Enabled() bool {
+	return c.Issuer != "" || c.JWKSFile != "" || c.JWKSURL != ""
+}
+
+// WithDefaults fills in the principal claim, and the audience and resource
+// from each other
+func (c OAuthConfig) WithDefaults() OAuthConfig {
+	c.Issuer = strings.TrimSuffix(c.Issuer, "/")
+	// The resource identifier is the audience clients request tokens for
+	// (RFC 8707), so either one stands in for the other
+	if c.Audience == "" {
+		c.Audience = c.Resource
+	}
+	if c.Resource == "" {
+		c.Resource = c.Audience
+	}
+	if c.Claims.PrincipalClaim == "" {
+		c.Claims.PrincipalClaim = "sub"
+	}
+	return c
+}
+
+// Validate checks that an enabled configuration has an audience and a
+// readable JWKS file when one is set
+func (c OAuthConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	// Without an audience check, tokens issued for any other service of the
+	// same authorization server would be accepted
+	if c.Audience == "" && c.Resource == "" {
+		return fmt.Errorf("an audience or resource is required")
+	}
+	if c.JWKSFile != "" {
+		if _, err := loadJWKSFile(c.JWKSFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadClaimMappings reads a JSON claim mappings file
+func LoadClaimMappings(path string) (ClaimMappingConfig, error) {
+	var config ClaimMappingConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to read claim mappings file: %w", err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse claim mappings file: %w", err)
+	}
+	return config, nil
+}
+
+// jwk is a single JSON Web Key
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// jwkSet is a JSON Web Key Set document
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// parseJWKS converts a JWKS document into public keys indexed by key ID
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set jwkSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWK %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS contains no signing keys")
+	}
+	return keys, nil
+}
+
+// publicKey decodes the RSA or EC public key of a JWK
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) > 4 {
+			return nil, fmt.Errorf("invalid exponent")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("invalid coordinates")
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("point is not on curve")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// loadJWKSFile reads and parses a JWKS file
+func loadJWKSFile(path string) (map[string]crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+	return parseJWKS(data)
+}
+
+// keySource provides JWT verification keys from a file or remote JWKS
+type keySource struct {
+	config     OAuthConfig
+	httpClient *http.Client
+	logger     *log.Logger
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+// key returns the verification key for kid, refreshing the key set when the
+// cache is stale or kid is unknown
+func (s *keySource) key(kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stale := time.Since(s.fetchedAt) > jwksRefreshInterval
+	key, found := s.lookup(kid)
+	if found && !stale {
+		return key, nil
+	}
+
+	if s.keys == nil || time.Since(s.lastAttempt) > jwksMinRefresh {
+		// The initial load does not count against the refetch limit, so a
+		// key rotated just after startup is still picked up
+		if s.keys != nil {
+			s.lastAttempt = time.Now()
+		}
+		if err := s.refresh(); err != nil {
+			if s.keys == nil {
+				return nil, err
+			}
+			s.logger.WithError(err).Warn("Failed to refresh JWKS, using cached keys")
+		}
+		key, found = s.lookup(kid)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// lookup finds a key by ID; a token without kid matches a single-key set
+func (s *keySource) lookup(kid string) (crypto.PublicKey, bool) {
+	if key, ok := s.keys[kid]; ok {
+		return key, true
+	}
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// refresh reloads the key set; the caller must hold mu
+func (s *keySource) refresh() error {
+	var keys map[string]crypto.PublicKey
+	var err error
+
+	if s.config.JWKSFile != "" {
+		keys, err = loadJWKSFile(s.config.JWKSFile)
+	} else {
+		jwksURL := s.config.JWKSURL
+		if jwksURL == "" {
+			if jwksURL, err = s.discoverJWKSURL(); err != nil {
+				return err
+			}
+		}
+		var data []byte
+		if data, err = s.fetch(jwksURL); err == nil {
+			keys, err = parseJWKS(data)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil
+}
+
+// discoverJWKSURL reads jwks_uri from the issuer's authorization server or
+// OpenID Connect metadata
+func (s *keySource) discoverJWKSURL() (string, error) {
+	for _, suffix := range []string{"/.well-known/oauth-authorization-server", "/.well-known/openid-configuration"} {
+		data, err := s.fetch(s.config.Issuer + suffix)
+		if err != nil {
+			continue
+		}
+		var metadata struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := json.Unmarshal(data, &metadata); err == nil && metadata.JWKSURI != "" {
+			return metadata.JWKSURI, nil
+		}
+	}
+	return "", fmt.Errorf("failed to discover jwks_uri for issuer %s", s.config.Issuer)
+}
+
+// fetch performs a bounded GET request
+func (s *keySource) fetch(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned HTTP %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// JWTValidator validates OAuth 2.1 JWT access tokens and maps their claims
+// to Zabbix credentials
+type JWTValidator struct {
+	config OAuthConfig
+	keys   *keySource
+}
+
+// NewJWTValidator creates a validator for the given configuration
+func NewJWTValidator(config OAuthConfig, logger *log.Logger) *JWTValidator {
+	return &JWTValidator{
+		config: config,
+		keys: &keySource{
+			config:     config,
+			httpClient: &http.Client{Timeout: 10 * time.Second},
+			logger:     logger,
+		},
+	}
+}
+
+// Challenge points clients at the protected resource metadata (RFC 9728)
+func (v *JWTValidator) Challenge() string {
+	challenge := `Bearer realm="zabbix-mcp-server"`
+	if v.config.Resource != "" {
+		challenge += fmt.Sprintf(`, resource_metadata="%s"`, resourceMetadataURL(v.config.Resource))
+	}
+	return challenge
+}
+
+// Authenticate validates a JWT access token and maps it to a key entry
+func (v *JWTValidator) Authenticate(credential string) (*AuthKey, error) {
+	claims, err := v.Validate(credential)
+	if err != nil {
+		return nil, err
+	}
+	return v.mapClaims(claims)
+}
+
+// jwtHeader is the JOSE header of a JWT
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Validate verifies the signature and registered claims of a JWT and returns
+// its claims
+func (v *JWTValidator) Validate(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed JWT")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWT header")
+	}
+	var header jwtHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, fmt.Errorf("malformed JWT header")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWT signature")
+	}
+
+	key, err := v.keys.key(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyJWTSignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWT payload")
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("malformed JWT claims")
+	}
+
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// verifyJWTSignature checks an RS* or ES* signature over signingInput
+func verifyJWTSignature(alg string, key crypto.PublicKey, signingInput string, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported JWT algorithm %q", alg)
+	}
+
+	h := hash.New()
+	h.Write([]byte(signingInput))
+	digest := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return fmt.Errorf("algorithm %s does not match RSA key", alg)
+		}
+		if err := rsa.VerifyPKCS1v15(k, hash, digest, signature); err != nil {
+			return fmt.Errorf("invalid JWT signature")
+		}
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return fmt.Errorf("algorithm %s does not match EC key", alg)
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return fmt.Errorf("invalid JWT signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return fmt.Errorf("invalid JWT signature")
+		}
+	default:
+		return fmt.Errorf("unsupported key type")
+	}
+	return nil
+}
+
+// validateClaims checks issuer, audience, lifetime and scopes
+func (v *JWTValidator) validateClaims(claims map[string]interface{}) error {
+	now := time.Now()
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("token has no exp claim")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(jwtClockSkew)) {
+		return fmt.Errorf("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(jwtClockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return fmt.Errorf("token not yet valid")
+	}
+
+	if v.config.Issuer != "" {
+		if iss, _ := claims["iss"].(string); strings.TrimSuffix(iss, "/") != v.config.Issuer {
+			return fmt.Errorf("unexpected token issuer %q", iss)
+		}
+	}
+
+	if v.config.Audience == "" {
+		return fmt.Errorf("no token audience is configured")
+	}
+	if !containsClaimValue(claims["aud"], v.config.Audience) {
+		return fmt.Errorf("token audience does not include %q", v.config.Audience)
+	}
+
+	if len(v.config.RequiredScopes) > 0 {
+		scopes := tokenScopes(claims)
+		for _, required := range v.config.RequiredScopes {
+			if !scopes[required] {
+				return fmt.Errorf("token is missing required scope %q", required)
+			}
+		}
+	}
+
+	return nil
+}
+
+// mapClaims applies the claim mappings to a validated token
+func (v *JWTValidator) mapClaims(claims map[string]interface{}) (*AuthKey, error) {
+	principal, _ := claims[v.config.Claims.PrincipalClaim].(string)
+	if principal == "" {
+		return nil, fmt.Errorf("token has no %s claim", v.config.Claims.PrincipalClaim)
+	}
+	name := OAuthPrincipalPrefix + principal
+
+	for _, m := range v.config.Claims.Mappings {
+		if containsClaimValue(claims[m.Claim], m.Value) {
+			return &AuthKey{Name: name, ZabbixURL: m.ZabbixURL, ZabbixToken: m.ZabbixToken, Admin: m.Admin}, nil
+		}
+	}
+
+	if v.config.Claims.RequireMapping {
+		return nil, fmt.Errorf("no Zabbix credentials are mapped to principal %q", principal)
+	}
+	return &AuthKey{Name: name}, nil
+}
+
+// containsClaimValue reports whether a string or string array claim contains value
+func containsClaimValue(claim interface{}, value string) bool {
+	switch c := claim.(type) {
+	case string:
+		return c == value
+	case []interface{}:
+		for _, item := range c {
+			if s, ok := item.(string); ok && s == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tokenScopes collects scopes from the "scope" string or "scp" array claim
+func tokenScopes(claims map[string]interface{}) map[string]bool {
+	scopes := make(map[string]bool)
+	if scope, ok := claims["scope"].(string); ok {
+		for _, s := range strings.Fields(scope) {
+			scopes[s] = true
+		}
+	}
+	if scp, ok := claims["scp"].([]interface{}); ok {
+		for _, item := range scp {
+			if s, ok := item.(string); ok {
+				scopes[s] = true
+			}
+		}
+	}
+	return scopes
+}
+
+// resourceMetadataURL derives the RFC 9728 metadata URL for a resource
+func resourceMetadataURL(resource string) string {
+	scheme, rest, ok := strings.Cut(resource, "://")
+	if !ok {
+		return resource
+	}
+	host, path, _ := strings.Cut(rest, "/")
+	metadataURL := scheme + "://" + host + ProtectedResourceMetadataPath
+	if path != "" {
+		metadataURL += "/" + path
+	}
+	return metadataURL
+}
+
+// ProtectedResourceMetadataHandler serves OAuth 2.0 protected resource
+// metadata (RFC 9728) so MCP clients can discover the authorization server
+func ProtectedResourceMetadataHandler(config OAuthConfig) http.HandlerFunc {
+	metadata := map[string]interface{}{
+		"resource":                 config.Resource,
+		"bearer_methods_supported": []string{"header"},
+	}
+	if config.Issuer != "" {
+		metadata["authorization_servers"] = []string{config.Issuer}
+	}
+	if len(config.RequiredScopes) > 0 {
+		metadata["scopes_supported"] = config.RequiredScopes
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(metadata)
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+const testAudience = "https://mcp.example.com/mcp"
+
+// jwtSigner signs test tokens with a locally generated RSA or EC key
+type jwtSigner struct {
+	kid string
+	alg string
+	key crypto.Signer
+}
+
+func newRSASigner(t *testing.T, kid string) jwtSigner {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jwtSigner{kid: kid, alg: "RS256", key: key}
+}
+
+func newECSigner(t *testing.T, kid string) jwtSigner {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jwtSigner{kid: kid, alg: "ES256", key: key}
+}
+
+// jwk returns the public key in JWKS form
+func (s jwtSigner) jwk() map[string]string {
+	b64 := base64.RawURLEncoding.EncodeToString
+	switch pub := s.key.Public().(type) {
+	case *rsa.PublicKey:
+		return map[string]string{"kty": "RSA", "kid": s.kid, "n": b64(pub.N.Bytes()), "e": b64(big.NewInt(int64(pub.E)).Bytes())}
+	case *ecdsa.PublicKey:
+		return map[string]string{"kty": "EC", "kid": s.kid, "crv": "P-256", "x": b64(pub.X.FillBytes(make([]byte, 32))), "y": b64(pub.Y.FillBytes(make([]byte, 32)))}
+	}
+	return nil
+}
+
+// sign returns a compact JWT carrying claims
+func (s jwtSigner) sign(t *testing.T, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": s.alg, "kid": s.kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+
+	var signature []byte
+	var err error
+	switch key := s.key.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		var r, sv *big.Int
+		r, sv, err = ecdsa.Sign(rand.Reader, key, digest[:])
+		signature = append(r.FillBytes(make([]byte, 32)), sv.FillBytes(make([]byte, 32))...)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// authServer serves authorization server metadata and a JWKS that tests can
+// rotate
+type authServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	signers []jwtSigner
+	fetches int
+}
+
+func newAuthServer(t *testing.T, signers ...jwtSigner) *authServer {
+	s := &authServer{signers: signers}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": s.URL, "jwks_uri": s.URL + "/jwks"})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.fetches++
+		keys := []map[string]string{}
+		for _, signer := range s.signers {
+			keys = append(keys, signer.jwk())
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *authServer) setSigners(signers ...jwtSigner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.signers = signers
+}
+
+func (s *authServer) jwksFetches() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetches
+}
+
+func TestJWTValidator(t *testing.T) {
+	rsaSigner := newRSASigner(t, "rsa")
+	ecSigner := newECSigner(t, "ec")
+	as := newAuthServer(t, rsaSigner, ecSigner)
+
+	validator := client.NewJWTValidator(client.OAuthConfig{
+		Issuer:         as.URL,
+		Audience:       testAudience,
+		RequiredScopes: []string{"zabbix:read"},
+		Claims: client.ClaimMappingConfig{
+			PrincipalClaim: "sub",
+			Mappings: []client.ClaimMapping{
+				{Claim: "groups", Value: "noc", ZabbixURL: "https://zabbix.example.com/api_jsonrpc.php", ZabbixToken: "noc-token"},
+			},
+		},
+	}, zabbixtest.Logger())
+
+	now := time.Now().Unix()
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"iss":   as.URL,
+			"aud":   testAudience,
+			"sub":   "alice",
+			"exp":   now + 300,
+			"scope": "zabbix:read zabbix:write",
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+	other := newRSASigner(t, "rsa")
+	mismatched := jwtSigner{kid: "ec", alg: "RS256", key: rsaSigner.key}
+
+	cases := []struct {
+		name    string
+		token   string
+		want    client.AuthKey
+		wantErr string
+	}{
+		{name: "RS256", token: rsaSigner.sign(t, claims(nil)), want: client.AuthKey{Name: "oauth:alice"}},
+		{name: "ES256", token: ecSigner.sign(t, claims(nil)), want: client.AuthKey{Name: "oauth:alice"}},
+		{name: "wrong key", token: other.sign(t, claims(nil)), wantErr: "invalid JWT signature"},
+		{name: "algorithm mismatch", token: mismatched.sign(t, claims(nil)), wantErr: "does not match EC key"},
+		{name: "malformed", token: "not-a-jwt", wantErr: "malformed JWT"},
+		{name: "expired", token: rsaSigner.sign(t, claims(map[string]interface{}{"exp": now - 3600})), wantErr: "token expired"},
+		{name: "expired within skew", token: rsaSigner.sign(t, claims(map[string]interface{}{"exp": now - 10})), want: client.AuthKey{Name: "oauth:alice"}},
+		{name: "no exp", token: rsaSigner.sign(t, claims(map[string]interface{}{"exp": nil})), wantErr: "no exp claim"},
+		{name: "not yet valid", token: rsaSigner.sign(t, claims(map[string]interface{}{"nbf": now + 3600})), wantErr: "not yet valid"},
+		{name: "wrong issuer", token: rsaSigner.sign(t, claims(map[string]interface{}{"iss": "https://evil.example.com"})), wantErr: "unexpected token issuer"},
+		{name: "wrong audience", token: rsaSigner.sign(t, claims(map[string]interface{}{"aud": "https://other.example.com"})), wantErr: "audience"},
+		{name: "no audience", token: rsaSigner.sign(t, claims(map[string]interface{}{"aud": nil})), wantErr: "audience"},
+		{name: "audience list", token: rsaSigner.sign(t, claims(map[string]interface{}{"aud": []string{"https://other.example.com", testAudience}})), want: client.AuthKey{Name: "oauth:alice"}},
+		{name: "missing scope", token: rsaSigner.sign(t, claims(map[string]interface{}{"scope": "zabbix:write"})), wantErr: `missing required scope "zabbix:read"`},
+		{name: "scp claim", token: rsaSigner.sign(t, claims(map[string]interface{}{"scope": nil, "scp": []string{"zabbix:read"}})), want: client.AuthKey{Name: "oauth:alice"}},
+		{name: "no subject", token: rsaSigner.sign(t, claims(map[string]interface{}{"sub": nil})), wantErr: "no sub claim"},
+		{
+			name:  "mapped group",
+			token: rsaSigner.sign(t, claims(map[string]interface{}{"groups": []string{"dev", "noc"}})),
+			want:  client.AuthKey{Name: "oauth:alice", ZabbixURL: "https://zabbix.example.com/api_jsonrpc.php", ZabbixToken: "noc-token"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := validator.Authenticate(tc.token)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *key != tc.want {
+				t.Errorf("expected %+v, got %+v", tc.want, *key)
+			}
+		})
+	}
+}
+
+func TestJWTValidatorRequireMapping(t *testing.T) {
+	signer := newRSASigner(t, "rsa")
+	as := newAuthServer(t, signer)
+	validator := client.NewJWTValidator(client.OAuthConfig{
+		JWKSURL:  as.URL + "/jwks",
+		Audience: testAudience,
+		Claims: client.ClaimMappingConfig{
+			PrincipalClaim: "email",
+			RequireMapping: true,
+			Mappings:       []client.ClaimMapping{{Claim: "email", Value: "noc@example.com", ZabbixToken: "noc-token", Admin: true}},
+		},
+	}, zabbixtest.Logger())
+
+	exp := time.Now().Add(time.Hour).Unix()
+	key, err := validator.Authenticate(signer.sign(t, map[string]interface{}{"aud": testAudience, "exp": exp, "email": "noc@example.com"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Name != "oauth:noc@example.com" || key.ZabbixToken != "noc-token" || !key.Admin {
+		t.Errorf("unexpected key %+v", *key)
+	}
+
+	_, err = validator.Authenticate(signer.sign(t, map[string]interface{}{"aud": testAudience, "exp": exp, "email": "dev@example.com"}))
+	if err == nil || !strings.Contains(err.Error(), "no Zabbix credentials") {
+		t.Errorf("expected an unmapped principal to be rejected, got %v", err)
+	}
+}
+
+func TestJWTPrincipalsDoNotCollideWithKeyNames(t *testing.T) {
+	signer := newRSASigner(t, "rsa")
+	as := newAuthServer(t, signer)
+	auth := client.MultiAuthenticator{
+		client.NewAuthenticator(client.AuthConfig{Keys: []client.AuthKey{{Name: "noc", Key: "noc-key", Admin: true}}}),
+		client.NewJWTValidator(client.OAuthConfig{
+			JWKSURL:  as.URL + "/jwks",
+			Audience: testAudience,
+			Claims:   client.ClaimMappingConfig{PrincipalClaim: "sub"},
+		}, zabbixtest.Logger()),
+	}
+
+	apiKey, err := auth.Authenticate("noc-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A token whose subject equals the API key's name is a different principal
+	token, err := auth.Authenticate(signer.sign(t, map[string]interface{}{"sub": "noc", "aud": testAudience, "exp": time.Now().Add(time.Hour).Unix()}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Name == apiKey.Name || token.Name != "oauth:noc" || token.Admin {
+		t.Errorf("expected a separate principal without admin rights, got %+v", *token)
+	}
+}
+
+func TestJWTValidatorRefetchesUnknownKeys(t *testing.T) {
+	first := newRSASigner(t, "first")
+	second := newECSigner(t, "second")
+	third := newECSigner(t, "third")
+	as := newAuthServer(t, first)
+	validator := client.NewJWTValidator(client.OAuthConfig{
+		JWKSURL:  as.URL + "/jwks",
+		Audience: testAudience,
+		Claims:   client.ClaimMappingConfig{PrincipalClaim: "sub"},
+	}, zabbixtest.Logger())
+
+	claims := map[string]interface{}{"sub": "alice", "aud": testAudience, "exp": time.Now().Add(time.Hour).Unix()}
+	if _, err := validator.Authenticate(first.sign(t, claims)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A rotated key is fetched when a token references it
+	as.setSigners(first, second)
+	if _, err := validator.Authenticate(second.sign(t, claims)); err != nil {
+		t.Fatalf("rotated key: %v", err)
+	}
+	if fetches := as.jwksFetches(); fetches != 2 {
+		t.Errorf("expected 2 JWKS fetches, got %d", fetches)
+	}
+
+	// Further unknown key IDs do not refetch within the minimum interval
+	as.setSigners(first, second, third)
+	if _, err := validator.Authenticate(third.sign(t, claims)); err == nil || !strings.Contains(err.Error(), "unknown signing key") {
+		t.Errorf("expected an unknown key error, got %v", err)
+	}
+	if fetches := as.jwksFetches(); fetches != 2 {
+		t.Errorf("expected no refetch, got %d fetches", fetches)
+	}
+}
+
+func TestOAuthConfigValidate(t *testing.T) {
+	config := client.OAuthConfig{JWKSURL: "https://auth.example.com/jwks"}.WithDefaults()
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "audience") {
+		t.Errorf("expected a missing audience error, got %v", err)
+	}
+
+	config = client.OAuthConfig{JWKSURL: "https://auth.example.com/jwks", Resource: testAudience}.WithDefaults()
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if config.Audience != testAudience || config.Claims.PrincipalClaim != "sub" {
+		t.Errorf("expected the resource to be the audience, got %+v", config)
+	}
+}
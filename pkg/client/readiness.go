@@ -0,0 +1,339 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// LivenessPath and ReadinessPath are the probe endpoints of the HTTP
+	// transport
+	LivenessPath  = "/livez"
+	ReadinessPath = "/readyz"
+
+	// ReadyCacheTTL is the environment variable for how long a readiness
+	// result is reused
+	ReadyCacheTTL = "MCP_READY_CACHE_TTL"
+	// DefaultReadyCacheTTL keeps frequent probes from loading Zabbix
+	DefaultReadyCacheTTL = 10 * time.Second
+
+	// DefaultConnection names the check of the connection configured with
+	// ZABBIX_URL and ZABBIX_TOKEN or ZABBIX_USER
+	DefaultConnection = "default"
+
+	readyProbeTimeout = 5 * time.Second
+)
+
+// Readiness states
+const (
+	ReadinessReady       = "ready"
+	ReadinessDegraded    = "degraded"
+	ReadinessUnavailable = "unavailable"
+)
+
+// States of a single readiness check
+const (
+	CheckOK    = "ok"
+	CheckError = "error"
+)
+
+// Causes of a failed check reported in ReadinessCheck.Error. /readyz is
+// served without authentication, so the full error is only logged.
+const (
+	ProbeTimeout        = "timeout"
+	ProbeTLS            = "tls"
+	ProbeUnreachable    = "unreachable"
+	ProbeAuthentication = "authentication"
+	ProbeAPIError       = "api_error"
+)
+
+// ReadinessCheck is the result of probing one Zabbix backend
+type ReadinessCheck struct {
+	Instance  string `json:"instance"`
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Version   string `json:"version,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Readiness is the readiness of the server to handle tool calls. It is
+// unavailable when the default backend, or every backend, fails, and
+// degraded when only some named instances fail.
+type Readiness struct {
+	Status    string           `json:"status"`
+	CheckedAt time.Time        `json:"checked_at"`
+	Checks    []ReadinessCheck `json:"checks"`
+}
+
+// readinessTarget is a Zabbix backend probed for readiness
+type readinessTarget struct {
+	name     string
+	url      string
+	tls      TLSConfig
+	token    string
+	username string
+	password string
+}
+
+// ReadinessChecker probes the configured Zabbix backends with
+// apiinfo.version and an authenticated call, caching the result
+type ReadinessChecker struct {
+	ttl    time.Duration
+	logger *log.Logger
+
+	mu      sync.Mutex
+	clients map[readinessTarget]*ZabbixClient
+	last    *Readiness
+}
+
+// NewReadinessChecker creates a checker that reuses results for ttl
+func NewReadinessChecker(ttl time.Duration, logger *log.Logger) *ReadinessChecker {
+	return &ReadinessChecker{
+		ttl:     ttl,
+		logger:  logger,
+		clients: make(map[readinessTarget]*ZabbixClient),
+	}
+}
+
+// Check returns the cached readiness, probing the backends when it expired.
+// Concurrent callers wait for one probe.
+func (rc *ReadinessChecker) Check(ctx context.Context) Readiness {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.last != nil && time.Since(rc.last.CheckedAt) < rc.ttl {
+		return *rc.last
+	}
+
+	// A client that disconnects must not cache a failed probe
+	ctx = context.WithoutCancel(ctx)
+
+	targets, primary := readinessTargets()
+	checks := make([]ReadinessCheck, len(targets))
+	clients := make([]*ZabbixClient, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target readinessTarget) {
+			defer wg.Done()
+			checks[i], clients[i] = rc.probe(ctx, target, rc.clients[target])
+		}(i, target)
+	}
+	wg.Wait()
+
+	// Keep the clients so logins are not repeated; only a rejected login
+	// or session starts over. Dropped clients, including those of targets
+	// no longer configured, are logged out so their sessions do not pile
+	// up in Zabbix.
+	previous := rc.clients
+	rc.clients = make(map[readinessTarget]*ZabbixClient)
+	for i, target := range targets {
+		delete(previous, target)
+		if clients[i] == nil {
+			continue
+		}
+		if checks[i].Error == ProbeAuthentication {
+			rc.logout(ctx, target, clients[i])
+			continue
+		}
+		rc.clients[target] = clients[i]
+	}
+	for target, client := range previous {
+		rc.logout(ctx, target, client)
+	}
+
+	readiness := Readiness{
+		Status:    readinessStatus(checks, primary),
+		CheckedAt: time.Now(),
+		Checks:    checks,
+	}
+	if readiness.Status != ReadinessReady {
+		rc.logger.WithField("checks", checks).Warn("Zabbix backend is not ready")
+	}
+	rc.last = &readiness
+	return readiness
+}
+
+// logout ends the Zabbix session of a dropped probe client
+func (rc *ReadinessChecker) logout(ctx context.Context, target readinessTarget, client *ZabbixClient) {
+	ctx, cancel := context.WithTimeout(ctx, readyProbeTimeout)
+	defer cancel()
+
+	if err := client.Logout(ctx); err != nil {
+		rc.logger.WithField("instance", target.name).WithError(err).Debug("Failed to log out of readiness probe session")
+	}
+}
+
+// probe checks one backend, creating its client when client is nil. It
+// returns the client, nil when it could not be created, whether or not the
+// probe succeeded.
+func (rc *ReadinessChecker) probe(ctx context.Context, target readinessTarget, client *ZabbixClient) (ReadinessCheck, *ZabbixClient) {
+	ctx, cancel := context.WithTimeout(ctx, readyProbeTimeout)
+	defer cancel()
+
+	check := ReadinessCheck{Instance: target.name, Status: CheckError}
+	start := time.Now()
+	fail := func(err error, cause string) (ReadinessCheck, *ZabbixClient) {
+		check.Error = cause
+		check.LatencyMS = time.Since(start).Milliseconds()
+		rc.logger.WithFields(log.Fields{
+			"instance": target.name,
+			"url":      target.url,
+			"cause":    check.Error,
+		}).WithError(err).Warn("Zabbix readiness probe failed")
+		return check, client
+	}
+
+	if client == nil {
+		var err error
+		if client, err = newZabbixClient(target.url, target.tls, rc.logger); err != nil {
+			// Only the TLS files can make the client fail
+			return fail(err, ProbeTLS)
+		}
+		client.AuthToken = target.token
+		client.Username = target.username
+		client.Password = target.password
+		client.Instance = target.name
+		client.MaxRetries = 0
+	}
+
+	if err := client.DetectVersion(ctx); err != nil {
+		return fail(err, probeFailure(err, ProbeAPIError))
+	}
+	check.Version = client.Version.String()
+
+	if client.authToken() == "" {
+		if err := client.Login(ctx); err != nil {
+			return fail(err, probeFailure(err, ProbeAuthentication))
+		}
+	}
+	if _, err := client.CallContext(ctx, "host.get", map[string]interface{}{"output": []string{"hostid"}, "limit": 1}); err != nil {
+		if isSessionExpired(err) {
+			return fail(err, ProbeAuthentication)
+		}
+		return fail(err, probeFailure(err, ProbeAPIError))
+	}
+
+	check.Status = CheckOK
+	check.LatencyMS = time.Since(start).Milliseconds()
+	return check, client
+}
+
+// probeFailure reduces a probe error to its cause. Errors returned by the
+// Zabbix API are reported as rejected.
+func probeFailure(err error, rejected string) string {
+	var netErr net.Error
+	var certErr *tls.CertificateVerificationError
+	var zabbixErr *ZabbixError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ProbeTimeout
+	case errors.As(err, &certErr), errors.As(err, &unknownAuthority), errors.As(err, &hostnameErr):
+		return ProbeTLS
+	case errors.As(err, &zabbixErr):
+		return rejected
+	case errors.As(err, &netErr), isRetryable(err):
+		return ProbeUnreachable
+	default:
+		return ProbeAPIError
+	}
+}
+
+// readinessTargets returns the backends to probe: every named instance and
+// the default connection when it has credentials. primary is the backend
+// used by calls without an instance, empty when sessions bring their own.
+func readinessTargets() (targets []readinessTarget, primary string) {
+	config := Instances()
+	for _, instance := range config.Instances {
+		tlsConfig := instance.TLS
+		tlsConfig.SkipVerify = instance.SkipTLSVerify
+		targets = append(targets, readinessTarget{
+			name:     instance.Name,
+			url:      instance.URL,
+			tls:      tlsConfig,
+			token:    instance.Token,
+			username: instance.User,
+			password: instance.Password,
+		})
+	}
+	primary = config.Default
+
+	connection := Connection()
+	if primary == "" && (connection.Token != "" || connection.User != "") {
+		targets = append(targets, readinessTarget{
+			name:     DefaultConnection,
+			url:      connection.URL,
+			tls:      connection.TLS,
+			token:    connection.Token,
+			username: connection.User,
+			password: connection.Password,
+		})
+		primary = DefaultConnection
+	}
+	return targets, primary
+}
+
+// readinessStatus summarizes the checks
+func readinessStatus(checks []ReadinessCheck, primary string) string {
+	failed := 0
+	for _, check := range checks {
+		if check.Status == CheckOK {
+			continue
+		}
+		if check.Instance == primary {
+			return ReadinessUnavailable
+		}
+		failed++
+	}
+
+	switch {
+	case failed == 0:
+		return ReadinessReady
+	case failed == len(checks):
+		return ReadinessUnavailable
+	default:
+		return ReadinessDegraded
+	}
+}
+
+// ReadinessHandler reports the readiness as JSON, with status 503 when the
+// server is unavailable
+func ReadinessHandler(checker *ReadinessChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		readiness := checker.Check(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if readiness.Status == ReadinessUnavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+		json.NewEncoder(w).Encode(readiness)
+	}
+}
+
+// LivenessHandler reports that the process is serving requests. It does not
+// depend on Zabbix, so a Zabbix outage does not restart the server.
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status": "alive"}`))
+	}
+}
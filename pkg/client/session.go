@@ -0,0 +1,134 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/vfcastr/Zabbix-MCP/pkg/metrics"
+)
+
+const (
+	// SessionIdleTTL is the environment variable for the idle session TTL
+	SessionIdleTTL = "MCP_SESSION_IDLE_TTL"
+
+	// DefaultSessionIdleTTL is how long an unused session client is kept
+	DefaultSessionIdleTTL = 30 * time.Minute
+
+	// logoutTimeout bounds user.logout when evicting a session
+	logoutTimeout = 5 * time.Second
+)
+
+func init() {
+	metrics.RegisterGauge("active_sessions", "MCP sessions with a Zabbix client.", func() float64 {
+		return float64(ActiveSessionCount())
+	})
+}
+
+// SessionHooks returns MCP server hooks that create and clean up the Zabbix
+// client for each registered session
+func SessionHooks(logger *log.Logger) *server.Hooks {
+	hooks := &server.Hooks{}
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		NewSessionHandler(ctx, session, logger)
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		EndSessionHandler(ctx, session, logger)
+	})
+	return hooks
+}
+
+// ActiveSessionCount returns the number of sessions with a Zabbix client.
+// Clients for named instances are not counted.
+func ActiveSessionCount() int {
+	count := 0
+	activeClients.Range(func(key, _ any) bool {
+		if _, ok := key.(string); ok {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
+// SweepIdleSessions evicts Zabbix clients that have not been used within ttl,
+// along with the instance clients of evicted sessions, and returns the number
+// of evicted clients
+func SweepIdleSessions(ttl time.Duration, logger *log.Logger) int {
+	cutoff := time.Now().Add(-ttl).UnixNano()
+	evicted := 0
+
+	activeClients.Range(func(key, value any) bool {
+		zabbixClient := value.(*ZabbixClient)
+		if zabbixClient.lastUsed.Load() >= cutoff {
+			return true
+		}
+
+		// Only evict if the entry was not replaced concurrently
+		if !activeClients.CompareAndDelete(key, value) {
+			return true
+		}
+		evicted++
+
+		ctx, cancel := context.WithTimeout(context.Background(), logoutTimeout)
+		if err := zabbixClient.Logout(ctx); err != nil {
+			logger.WithError(err).WithField("session_id", key).Warn("Failed to log out of Zabbix for idle session")
+		}
+		if sessionID, ok := key.(string); ok {
+			deleteInstanceClients(ctx, sessionID, logger)
+		}
+		cancel()
+
+		logger.WithField("session_id", key).Debug("Evicted idle Zabbix client")
+		return true
+	})
+
+	return evicted
+}
+
+// StartSessionSweeper periodically evicts idle Zabbix clients until ctx is
+// cancelled. A non-positive ttl disables the sweeper.
+func StartSessionSweeper(ctx context.Context, ttl time.Duration, logger *log.Logger) {
+	if ttl <= 0 {
+		logger.Info("Idle session sweeper disabled")
+		return
+	}
+
+	interval := ttl / 2
+	if interval > time.Minute {
+		interval = time.Minute
+	}
+
+	logger.WithFields(log.Fields{
+		"ttl":      ttl,
+		"interval": interval,
+	}).Info("Starting idle session sweeper")
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if evicted := SweepIdleSessions(ttl, logger); evicted > 0 {
+					logger.WithFields(log.Fields{
+						"evicted": evicted,
+						"active":  ActiveSessionCount(),
+					}).Info("Evicted idle Zabbix sessions")
+				}
+			}
+		}
+	}()
+}
+
+// touch records that the client was just used
+func (c *ZabbixClient) touch() {
+	c.lastUsed.Store(time.Now().UnixNano())
+}
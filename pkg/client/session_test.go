@@ -0,0 +1,77 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+// sessionWithInstance returns a session with a default client and a client
+// for the eu instance, which logs in with a password so its logout shows
+func sessionWithInstance(t *testing.T, s *zabbixtest.Server) *zabbixtest.Session {
+	t.Helper()
+	s.AddUser("Admin", "zabbix")
+	client.SetInstances(client.InstancesConfig{
+		Instances: []client.Instance{{Name: "eu", URL: s.APIURL(), User: "Admin", Password: "zabbix"}},
+	})
+	t.Cleanup(func() { client.SetInstances(client.InstancesConfig{}) })
+
+	session := zabbixtest.NewSession()
+	ctx := s.SessionContext(t, session)
+	if _, err := client.GetZabbixClientFromContext(client.WithInstance(ctx, "eu"), zabbixtest.Logger()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.EndSessionHandler(context.Background(), session, zabbixtest.Logger()) })
+	return session
+}
+
+func TestSessionSweeperEvictsIdleSessions(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	session := sessionWithInstance(t, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client.StartSessionSweeper(ctx, 20*time.Millisecond, zabbixtest.Logger())
+
+	// Polling must not touch the session, so it is counted rather than looked up
+	deadline := time.Now().Add(2 * time.Second)
+	for client.ActiveSessionCount() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the idle session was not evicted")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if client.GetZabbixClient(session.ID) != nil {
+		t.Error("the idle session was not evicted")
+	}
+	if got := len(s.Calls("user.logout")); got != 1 {
+		t.Errorf("expected the instance client to be logged out, got %d logouts", got)
+	}
+
+	// The next call on the instance connects again
+	if _, err := client.GetZabbixClientFromContext(client.WithInstance(s.SessionContext(t, session), "eu"), zabbixtest.Logger()); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(s.Calls("user.login")); got != 2 {
+		t.Errorf("expected the evicted instance client to be replaced, got %d logins", got)
+	}
+}
+
+func TestSweepIdleSessionsKeepsActiveSessions(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	session := sessionWithInstance(t, s)
+
+	if evicted := client.SweepIdleSessions(time.Hour, zabbixtest.Logger()); evicted != 0 {
+		t.Errorf("expected no evictions, got %d", evicted)
+	}
+	if client.GetZabbixClient(session.ID) == nil {
+		t.Error("the active session was evicted")
+	}
+	s.AssertNotCalled(t, "user.logout")
+}
@@ -0,0 +1,161 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Zabbix TLS and connection pool environment variables
+const (
+	ZabbixTLSCAFile           = "ZABBIX_TLS_CA_FILE"
+	ZabbixTLSCertFile         = "ZABBIX_TLS_CERT_FILE"
+	ZabbixTLSKeyFile          = "ZABBIX_TLS_KEY_FILE"
+	ZabbixTLSMinVersion       = "ZABBIX_TLS_MIN_VERSION"
+	ZabbixTLSServerName       = "ZABBIX_TLS_SERVER_NAME"
+	ZabbixMaxConnsPerHost     = "ZABBIX_MAX_CONNS_PER_HOST"
+	ZabbixMaxIdleConnsPerHost = "ZABBIX_MAX_IDLE_CONNS_PER_HOST"
+)
+
+// DefaultMaxIdleConnsPerHost is the number of idle keep-alive connections
+// kept per Zabbix server
+const DefaultMaxIdleConnsPerHost = 10
+
+const (
+	maxIdleConns          = 100
+	idleConnTimeout       = 90 * time.Second
+	dialTimeout           = 10 * time.Second
+	tcpKeepAlive          = 30 * time.Second
+	tlsHandshakeTimeout   = 10 * time.Second
+	expectContinueTimeout = time.Second
+)
+
+// TLSConfig holds the TLS settings for connections to a Zabbix server
+type TLSConfig struct {
+	// SkipVerify disables certificate verification. It is set from
+	// ZABBIX_SKIP_VERIFY or the instance's skip_tls_verify.
+	SkipVerify bool `json:"-" yaml:"-" toml:"-"`
+	// CAFile is a PEM bundle that replaces the system roots
+	CAFile string `json:"ca_file,omitempty" yaml:"ca_file" toml:"ca_file"`
+	// CertFile and KeyFile are the PEM client certificate and key sent to
+	// servers that require mutual TLS
+	CertFile string `json:"cert_file,omitempty" yaml:"cert_file" toml:"cert_file"`
+	KeyFile  string `json:"key_file,omitempty" yaml:"key_file" toml:"key_file"`
+	// MinVersion is the minimum TLS version, "1.2" or "1.3"
+	MinVersion string `json:"min_version,omitempty" yaml:"min_version" toml:"min_version"`
+	// ServerName overrides the name used for SNI and certificate
+	// verification
+	ServerName string `json:"server_name,omitempty" yaml:"server_name" toml:"server_name"`
+}
+
+// Validate checks the settings without reading the files
+func (c TLSConfig) Validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("TLS cert_file and key_file must be set together")
+	}
+	if _, err := parseTLSVersion(c.MinVersion); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ClientConfig loads the CA bundle and client certificate and returns the
+// resulting crypto/tls configuration
+func (c TLSConfig) ClientConfig() (*tls.Config, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	minVersion, _ := parseTLSVersion(c.MinVersion)
+
+	config := &tls.Config{
+		InsecureSkipVerify: c.SkipVerify,
+		ServerName:         c.ServerName,
+		MinVersion:         minVersion,
+	}
+
+	if c.CAFile != "" {
+		data, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("TLS CA file %s contains no PEM certificates", c.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// parseTLSVersion parses "1.2", "1.3" or "TLS1.2" style versions. An empty
+// version selects the Go default. TLS 1.0 and 1.1 are deprecated and
+// rejected.
+func parseTLSVersion(version string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(version)), "TLS") {
+	case "":
+		return 0, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("invalid TLS min_version %q, expected 1.2 or 1.3", version)
+}
+
+// transports holds one *http.Transport per TLSConfig, shared by every
+// client with those settings so connections are pooled across sessions
+var transports sync.Map
+
+// sharedTransport returns the transport for config, creating it on first use
+// with the connection pool limits of connection
+func sharedTransport(config TLSConfig, connection ConnectionConfig) (*http.Transport, error) {
+	if transport, ok := transports.Load(config); ok {
+		return transport.(*http.Transport), nil
+	}
+
+	tlsConfig, err := config.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport, _ := transports.LoadOrStore(config, newTransport(tlsConfig, connection))
+	return transport.(*http.Transport), nil
+}
+
+// newTransport returns a transport with keep-alives, pool limits and the
+// proxy from HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+func newTransport(tlsConfig *tls.Config, connection ConnectionConfig) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: tcpKeepAlive,
+	}
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          maxIdleConns,
+		MaxIdleConnsPerHost:   connection.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       connection.MaxConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ExpectContinueTimeout: expectContinueTimeout,
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Version is a Zabbix server version as reported by apiinfo.version
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// Minimum Zabbix versions of version-dependent API features
+var (
+	// BearerAuthVersion accepts the API token in the Authorization header.
+	// Older versions expect it in the "auth" field of the request body.
+	BearerAuthVersion = Version{Major: 6, Minor: 4}
+	// HostGroupsVersion split template groups from host groups. The
+	// selectGroups option of the get methods became selectHostGroups and
+	// selectTemplateGroups.
+	HostGroupsVersion = Version{Major: 6, Minor: 2}
+	// ProxyGroupsVersion introduced proxy groups and renamed the proxy
+	// fields (host became name, status became operating_mode, ...)
+	ProxyGroupsVersion = Version{Major: 7, Minor: 0}
+)
+
+// ParseVersion parses a version such as "7.0.5" or "6.0". A pre-release
+// suffix such as the "beta1" in "7.4.0beta1" is ignored.
+func ParseVersion(s string) (Version, error) {
+	parts := strings.Split(strings.TrimSpace(s), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid Zabbix version %q", s)
+	}
+	last := parts[len(parts)-1]
+	if i := strings.IndexFunc(last, func(r rune) bool { return r < '0' || r > '9' }); i > 0 {
+		parts[len(parts)-1] = last[:i]
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid Zabbix version %q", s)
+		}
+		numbers[i] = n
+	}
+
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// String returns the version as major.minor.patch
+func (v Version) String() string {
+	if v.IsZero() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// IsZero reports whether the version is unknown
+func (v Version) IsZero() bool {
+	return v == Version{}
+}
+
+// AtLeast reports whether v is min or newer. An unknown version is assumed
+// to be current, so clients that could not detect the version keep the
+// Zabbix 7.0 behavior.
+func (v Version) AtLeast(min Version) bool {
+	if v.IsZero() {
+		return true
+	}
+	if v.Major != min.Major {
+		return v.Major > min.Major
+	}
+	if v.Minor != min.Minor {
+		return v.Minor > min.Minor
+	}
+	return v.Patch >= min.Patch
+}
+
+// Require returns an error naming feature when the connected Zabbix server
+// is older than min
+func (c *ZabbixClient) Require(min Version, feature string) error {
+	if c.Version.AtLeast(min) {
+		return nil
+	}
+	return fmt.Errorf("%s requires Zabbix %d.%d or later, but %s runs %s", feature, min.Major, min.Minor, c.URL, c.Version)
+}
+
+// groupSelects maps the select options added by HostGroupsVersion to the
+// result fields they fill
+var groupSelects = map[string]string{
+	"selectHostGroups":     "hostgroups",
+	"selectTemplateGroups": "templategroups",
+}
+
+// legacyGroupSelect rewrites selectHostGroups or selectTemplateGroups in the
+// params of a get method to the selectGroups option of servers older than
+// HostGroupsVersion. It returns the rewritten params and the result field
+// that selectGroups' "groups" field must be renamed to, or params unchanged
+// and "" when no rewrite is needed.
+func (c *ZabbixClient) legacyGroupSelect(method string, params interface{}) (interface{}, string) {
+	if c.Version.AtLeast(HostGroupsVersion) || !strings.HasSuffix(method, ".get") {
+		return params, ""
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return params, ""
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return params, ""
+	}
+	for option, field := range groupSelects {
+		if value, ok := fields[option]; ok {
+			delete(fields, option)
+			fields["selectGroups"] = value
+			return fields, field
+		}
+	}
+	return params, ""
+}
+
+// renameGroups renames the "groups" field of every object in a get result
+func renameGroups(result json.RawMessage, field string) json.RawMessage {
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(result, &objects); err != nil {
+		return result
+	}
+	for _, obj := range objects {
+		if groups, ok := obj["groups"]; ok {
+			delete(obj, "groups")
+			obj[field] = groups
+		}
+	}
+	renamed, err := json.Marshal(objects)
+	if err != nil {
+		return result
+	}
+	return renamed
+}
+
+// DetectVersion calls apiinfo.version and stores the result on the client.
+// On failure the version is left unknown.
+func (c *ZabbixClient) DetectVersion(ctx context.Context) error {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	// apiinfo.version must be called without authentication
+	result, err := c.callWithRetry(ctx, "apiinfo.version", []string{}, "")
+	if err != nil {
+		return fmt.Errorf("apiinfo.version failed: %w", err)
+	}
+
+	var raw string
+	if err := json.Unmarshal(result, &raw); err != nil {
+		return fmt.Errorf("failed to parse apiinfo.version response: %w", err)
+	}
+	version, err := ParseVersion(raw)
+	if err != nil {
+		return err
+	}
+
+	c.Version = version
+	c.Logger.WithFields(log.Fields{
+		"zabbix_url":     c.URL,
+		"zabbix_version": version.String(),
+	}).Debug("Detected Zabbix API version")
+
+	return nil
+}
@@ -0,0 +1,469 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package config loads the server settings. Each setting is taken from the
+// command line, the environment, the configuration file or its default, in
+// that order of precedence.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/vfcastr/Zabbix-MCP/pkg/audit"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tracing"
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables for settings that are not owned by pkg/client
+const (
+	ConfigFile        = "ZABBIX_MCP_CONFIG"
+	TransportMode     = "TRANSPORT_MODE"
+	TransportHost     = "TRANSPORT_HOST"
+	TransportPort     = "TRANSPORT_PORT"
+	Endpoint          = "MCP_ENDPOINT"
+	ShutdownTimeout   = "MCP_SHUTDOWN_TIMEOUT"
+	ReadHeaderTimeout = "MCP_READ_HEADER_TIMEOUT"
+	IdleTimeout       = "MCP_IDLE_TIMEOUT"
+	TLSCertFile       = "MCP_TLS_CERT_FILE"
+	TLSKeyFile        = "MCP_TLS_KEY_FILE"
+	TLSClientCAFile   = "MCP_TLS_CLIENT_CA_FILE"
+	LogLevel          = "LOG_LEVEL"
+	LogFile           = "LOG_FILE"
+	LogFormat         = "LOG_FORMAT"
+	Toolsets          = "ZABBIX_MCP_TOOLSETS"
+	EnableTools       = "ZABBIX_MCP_ENABLE_TOOLS"
+	DisableTools      = "ZABBIX_MCP_DISABLE_TOOLS"
+	DryRun            = "ZABBIX_MCP_DRY_RUN"
+)
+
+// Defaults of settings that are not owned by pkg/client
+const (
+	DefaultTransportMode     = "stdio"
+	DefaultBindAddress       = "127.0.0.1"
+	DefaultBindPort          = 8080
+	DefaultEndpointPath      = "/mcp"
+	DefaultShutdownTimeout   = 30 * time.Second
+	DefaultReadHeaderTimeout = 10 * time.Second
+	DefaultIdleTimeout       = 120 * time.Second
+	DefaultLogLevel          = "info"
+	DefaultLogFormat         = "text"
+)
+
+// Config is the server configuration
+type Config struct {
+	Transport TransportConfig `yaml:"transport" toml:"transport"`
+	CORS      CORSConfig      `yaml:"cors" toml:"cors"`
+	RateLimit RateLimitConfig `yaml:"rate_limit" toml:"rate_limit"`
+	Logging   LoggingConfig   `yaml:"logging" toml:"logging"`
+	Tools     ToolsConfig     `yaml:"tools" toml:"tools"`
+	Tracing   tracing.Config  `yaml:"tracing" toml:"tracing"`
+	Audit     audit.Config    `yaml:"audit" toml:"audit"`
+	Auth      AuthConfig      `yaml:"auth" toml:"auth"`
+	OAuth     OAuthConfig     `yaml:"oauth" toml:"oauth"`
+	Zabbix    ZabbixConfig    `yaml:"zabbix" toml:"zabbix"`
+}
+
+// TransportConfig selects how MCP clients connect
+type TransportConfig struct {
+	// Mode is stdio or streamable-http
+	Mode            string   `yaml:"mode" toml:"mode"`
+	Host            string   `yaml:"host" toml:"host"`
+	Port            int      `yaml:"port" toml:"port"`
+	Endpoint        string   `yaml:"endpoint" toml:"endpoint"`
+	SessionIdleTTL  Duration `yaml:"session_idle_ttl" toml:"session_idle_ttl"`
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	// ReadHeaderTimeout bounds reading request headers, IdleTimeout how long
+	// keep-alive connections wait for the next request
+	ReadHeaderTimeout Duration `yaml:"read_header_timeout" toml:"read_header_timeout"`
+	IdleTimeout       Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+	// ReadyCacheTTL is how long a /readyz result is reused
+	ReadyCacheTTL Duration `yaml:"ready_cache_ttl" toml:"ready_cache_ttl"`
+	// TLS serves the HTTP transport over HTTPS when a certificate is set
+	TLS client.ListenerTLSConfig `yaml:"tls" toml:"tls"`
+}
+
+// CORSConfig controls cross-origin requests to the HTTP transport
+type CORSConfig struct {
+	// Mode is strict, development or disabled
+	Mode           string   `yaml:"mode" toml:"mode"`
+	AllowedOrigins []string `yaml:"allowed_origins" toml:"allowed_origins"`
+}
+
+// RateLimitConfig limits inbound MCP requests and outbound Zabbix calls.
+// A rate of zero disables the limit.
+type RateLimitConfig struct {
+	GlobalRPS    float64 `yaml:"global_rps" toml:"global_rps"`
+	GlobalBurst  int     `yaml:"global_burst" toml:"global_burst"`
+	SessionRPS   float64 `yaml:"session_rps" toml:"session_rps"`
+	SessionBurst int     `yaml:"session_burst" toml:"session_burst"`
+	IPRPS        float64 `yaml:"ip_rps" toml:"ip_rps"`
+	IPBurst      int     `yaml:"ip_burst" toml:"ip_burst"`
+	ZabbixRPS    float64 `yaml:"zabbix_rps" toml:"zabbix_rps"`
+	ZabbixBurst  int     `yaml:"zabbix_burst" toml:"zabbix_burst"`
+}
+
+// LoggingConfig controls the server log
+type LoggingConfig struct {
+	// Level is debug, info, warn or error
+	Level string `yaml:"level" toml:"level"`
+	// File is the log file; the log goes to stderr when empty
+	File string `yaml:"file" toml:"file"`
+	// Format is text or json
+	Format string `yaml:"format" toml:"format"`
+}
+
+// ToolsConfig selects the registered tools
+type ToolsConfig struct {
+	ReadOnly     bool     `yaml:"read_only" toml:"read_only"`
+	DryRun       bool     `yaml:"dry_run" toml:"dry_run"`
+	Toolsets     []string `yaml:"toolsets" toml:"toolsets"`
+	EnableTools  []string `yaml:"enable_tools" toml:"enable_tools"`
+	DisableTools []string `yaml:"disable_tools" toml:"disable_tools"`
+}
+
+// AuthConfig holds the API keys and HMAC bearer token secret of the HTTP
+// transport
+type AuthConfig struct {
+	// KeysFile is a JSON file whose keys are added to Keys
+	KeysFile   string           `yaml:"keys_file" toml:"keys_file"`
+	Keys       []client.AuthKey `yaml:"keys" toml:"keys"`
+	HMACSecret string           `yaml:"hmac_secret" toml:"hmac_secret"`
+}
+
+// OAuthConfig makes the HTTP transport an OAuth 2.1 protected resource
+type OAuthConfig struct {
+	Issuer         string   `yaml:"issuer" toml:"issuer"`
+	Audience       string   `yaml:"audience" toml:"audience"`
+	Resource       string   `yaml:"resource" toml:"resource"`
+	JWKSFile       string   `yaml:"jwks_file" toml:"jwks_file"`
+	JWKSURL        string   `yaml:"jwks_url" toml:"jwks_url"`
+	RequiredScopes []string `yaml:"required_scopes" toml:"required_scopes"`
+	// ClaimMappingsFile is a JSON file that replaces Claims
+	ClaimMappingsFile string                    `yaml:"claim_mappings_file" toml:"claim_mappings_file"`
+	Claims            client.ClaimMappingConfig `yaml:"claims" toml:"claims"`
+}
+
+// ZabbixConfig is the default Zabbix connection and the named instances
+type ZabbixConfig struct {
+	URL             string            `yaml:"url" toml:"url"`
+	Token           string            `yaml:"token" toml:"token"`
+	User            string            `yaml:"user" toml:"user"`
+	Password        string            `yaml:"password" toml:"password"`
+	SkipTLSVerify   bool              `yaml:"skip_tls_verify" toml:"skip_tls_verify"`
+	TLS             client.TLSConfig  `yaml:"tls" toml:"tls"`
+	Timeout         Duration          `yaml:"timeout" toml:"timeout"`
+	MaxRetries      int               `yaml:"max_retries" toml:"max_retries"`
+	DefaultInstance string            `yaml:"default_instance" toml:"default_instance"`
+	Instances       []client.Instance `yaml:"instances" toml:"instances"`
+	// InstancesFile is a JSON file whose instances are added to Instances
+	InstancesFile string `yaml:"instances_file" toml:"instances_file"`
+	// MaxConnsPerHost limits the connections to each Zabbix server; zero
+	// means no limit
+	MaxConnsPerHost     int `yaml:"max_conns_per_host" toml:"max_conns_per_host"`
+	MaxIdleConnsPerHost int `yaml:"max_idle_conns_per_host" toml:"max_idle_conns_per_host"`
+	// FederationConcurrency is the number of instances a federated query
+	// calls at once
+	FederationConcurrency int `yaml:"federation_concurrency" toml:"federation_concurrency"`
+}
+
+// Default returns the built-in configuration
+func Default() Config {
+	limits := client.DefaultRateLimitConfig
+	return Config{
+		Transport: TransportConfig{
+			Mode:              DefaultTransportMode,
+			Host:              DefaultBindAddress,
+			Port:              DefaultBindPort,
+			Endpoint:          DefaultEndpointPath,
+			SessionIdleTTL:    Duration(client.DefaultSessionIdleTTL),
+			ShutdownTimeout:   Duration(DefaultShutdownTimeout),
+			ReadHeaderTimeout: Duration(DefaultReadHeaderTimeout),
+			IdleTimeout:       Duration(DefaultIdleTimeout),
+			ReadyCacheTTL:     Duration(client.DefaultReadyCacheTTL),
+		},
+		CORS: CORSConfig{Mode: string(client.CORSModeStrict)},
+		RateLimit: RateLimitConfig{
+			GlobalRPS:    limits.GlobalRPS,
+			GlobalBurst:  limits.GlobalBurst,
+			SessionRPS:   limits.SessionRPS,
+			SessionBurst: limits.SessionBurst,
+			IPRPS:        limits.IPRPS,
+			IPBurst:      limits.IPBurst,
+			ZabbixRPS:    limits.ZabbixRPS,
+			ZabbixBurst:  limits.ZabbixBurst,
+		},
+		Logging: LoggingConfig{Level: DefaultLogLevel, Format: DefaultLogFormat},
+		Tracing: tracing.Config{Exporter: tracing.ExporterNone},
+		Audit:   audit.Config{MaxSizeMB: audit.DefaultMaxSizeMB, MaxBackups: audit.DefaultMaxBackups},
+		Zabbix: ZabbixConfig{
+			URL:                   client.DefaultZabbixURL,
+			Timeout:               Duration(client.DefaultCallTimeout),
+			MaxRetries:            client.DefaultMaxRetries,
+			MaxIdleConnsPerHost:   client.DefaultMaxIdleConnsPerHost,
+			FederationConcurrency: client.DefaultFederationConcurrency,
+		},
+	}
+}
+
+// Load reads a YAML (.yaml, .yml) or TOML (.toml) configuration file on top
+// of the defaults. Unknown settings are rejected.
+func Load(path string) (Config, error) {
+	config := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+			return config, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), &config)
+		if err != nil {
+			return config, fmt.Errorf("failed to parse config file: %w", err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return config, fmt.Errorf("failed to parse config file: unknown setting %q", undecoded[0].String())
+		}
+	default:
+		return config, fmt.Errorf("unsupported config file extension %q, use .yaml, .yml or .toml", ext)
+	}
+
+	return config, nil
+}
+
+// ApplyEnv overrides settings with the environment variables that are set
+// to a non-empty value
+func (c *Config) ApplyEnv() error {
+	for _, b := range c.bindings() {
+		value := os.Getenv(b.env)
+		if value == "" {
+			continue
+		}
+		if err := parse(b.value, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", b.env, err)
+		}
+	}
+	return nil
+}
+
+// LoadFiles adds the API keys, claim mappings and instances of the JSON
+// files the configuration names. Call it once, after the environment and
+// flags are applied.
+func (c *Config) LoadFiles() error {
+	if c.Auth.KeysFile != "" {
+		auth, err := client.LoadAuthKeysFile(c.Auth.KeysFile)
+		if err != nil {
+			return fmt.Errorf("auth.keys_file: %w", err)
+		}
+		c.Auth.Keys = append(auth.Keys, c.Auth.Keys...)
+		if c.Auth.HMACSecret == "" {
+			c.Auth.HMACSecret = auth.HMACSecret
+		}
+	}
+
+	if c.OAuth.ClaimMappingsFile != "" {
+		claims, err := client.LoadClaimMappings(c.OAuth.ClaimMappingsFile)
+		if err != nil {
+			return fmt.Errorf("oauth.claim_mappings_file: %w", err)
+		}
+		c.OAuth.Claims = claims
+	}
+
+	if c.Zabbix.InstancesFile != "" {
+		instances, err := client.LoadInstancesConfig(c.Zabbix.InstancesFile)
+		if err != nil {
+			return fmt.Errorf("zabbix.instances_file: %w", err)
+		}
+		c.Zabbix.Instances = append(instances.Instances, c.Zabbix.Instances...)
+		if c.Zabbix.DefaultInstance == "" {
+			c.Zabbix.DefaultInstance = instances.Default
+		}
+	}
+
+	return nil
+}
+
+// InstancesConfig returns the named Zabbix instances
+func (c Config) InstancesConfig() client.InstancesConfig {
+	return client.InstancesConfig{Default: c.Zabbix.DefaultInstance, Instances: c.Zabbix.Instances}
+}
+
+// ConnectionConfig returns the default Zabbix connection and the settings
+// every Zabbix client uses
+func (c Config) ConnectionConfig() client.ConnectionConfig {
+	tlsConfig := c.Zabbix.TLS
+	tlsConfig.SkipVerify = c.Zabbix.SkipTLSVerify
+	return client.ConnectionConfig{
+		URL:                   c.Zabbix.URL,
+		Token:                 c.Zabbix.Token,
+		User:                  c.Zabbix.User,
+		Password:              c.Zabbix.Password,
+		TLS:                   tlsConfig,
+		Timeout:               time.Duration(c.Zabbix.Timeout),
+		MaxRetries:            c.Zabbix.MaxRetries,
+		MaxConnsPerHost:       c.Zabbix.MaxConnsPerHost,
+		MaxIdleConnsPerHost:   c.Zabbix.MaxIdleConnsPerHost,
+		FederationConcurrency: c.Zabbix.FederationConcurrency,
+		RPS:                   c.RateLimit.ZabbixRPS,
+		Burst:                 c.RateLimit.ZabbixBurst,
+	}
+}
+
+// AuthConfig returns the API keys and HMAC secret of the HTTP transport
+func (c Config) AuthConfig() client.AuthConfig {
+	return client.AuthConfig{Keys: c.Auth.Keys, HMACSecret: c.Auth.HMACSecret}
+}
+
+// OAuthConfig returns the OAuth resource server settings with their defaults
+func (c Config) OAuthConfig() client.OAuthConfig {
+	return client.OAuthConfig{
+		Issuer:   c.OAuth.Issuer,
+		Audience: c.OAuth.Audience,
+		Resource: c.OAuth.Resource,
+		JWKSFile: c.OAuth.JWKSFile,
+		JWKSURL:  c.OAuth.JWKSURL,
+		// Scopes may also be separated by spaces, as in the scope claim
+		RequiredScopes: strings.Fields(strings.Join(c.OAuth.RequiredScopes, " ")),
+		Claims:         c.OAuth.Claims,
+	}.WithDefaults()
+}
+
+// HTTPConfig returns the middleware settings of the HTTP transport
+func (c Config) HTTPConfig() client.HTTPConfig {
+	return client.HTTPConfig{
+		CORS: client.CORSConfig{Mode: client.CORSMode(c.CORS.Mode), AllowedOrigins: c.CORS.AllowedOrigins},
+		RateLimit: client.RateLimitConfig{
+			GlobalRPS:    c.RateLimit.GlobalRPS,
+			GlobalBurst:  c.RateLimit.GlobalBurst,
+			SessionRPS:   c.RateLimit.SessionRPS,
+			SessionBurst: c.RateLimit.SessionBurst,
+			IPRPS:        c.RateLimit.IPRPS,
+			IPBurst:      c.RateLimit.IPBurst,
+			ZabbixRPS:    c.RateLimit.ZabbixRPS,
+			ZabbixBurst:  c.RateLimit.ZabbixBurst,
+		},
+		Auth:  c.AuthConfig(),
+		OAuth: c.OAuthConfig(),
+	}
+}
+
+// Validate reports every invalid setting
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	switch c.Transport.Mode {
+	case "stdio", "streamable-http", "http":
+	default:
+		check(false, "transport.mode: must be stdio or streamable-http, got %q", c.Transport.Mode)
+	}
+	check(c.Transport.Host != "", "transport.host: must not be empty")
+	check(c.Transport.Port > 0 && c.Transport.Port < 65536, "transport.port: must be between 1 and 65535, got %d", c.Transport.Port)
+	check(c.Transport.Endpoint != "", "transport.endpoint: must not be empty")
+	check(c.Transport.SessionIdleTTL >= 0, "transport.session_idle_ttl: must not be negative")
+	check(c.Transport.ShutdownTimeout > 0, "transport.shutdown_timeout: must be positive")
+	check(c.Transport.ReadHeaderTimeout > 0, "transport.read_header_timeout: must be positive")
+	check(c.Transport.IdleTimeout >= 0, "transport.idle_timeout: must not be negative")
+	check(c.Transport.ReadyCacheTTL >= 0, "transport.ready_cache_ttl: must not be negative")
+	if err := c.Transport.TLS.Check(); err != nil {
+		check(false, "transport.tls: %v", err)
+	}
+
+	switch client.CORSMode(c.CORS.Mode) {
+	case client.CORSModeStrict, client.CORSModeDevelopment, client.CORSModeDisabled:
+	default:
+		check(false, "cors.mode: must be strict, development or disabled, got %q", c.CORS.Mode)
+	}
+
+	limits := c.RateLimit
+	check(limits.GlobalRPS >= 0 && limits.SessionRPS >= 0 && limits.IPRPS >= 0 && limits.ZabbixRPS >= 0, "rate_limit: rates must not be negative")
+	check(limits.GlobalBurst >= 0 && limits.SessionBurst >= 0 && limits.IPBurst >= 0 && limits.ZabbixBurst >= 0, "rate_limit: bursts must not be negative")
+
+	if err := c.Tracing.Validate(); err != nil {
+		check(false, "tracing.exporter: %v", err)
+	}
+	if c.Tracing.Endpoint != "" {
+		if u, err := url.Parse(c.Tracing.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			check(false, "tracing.endpoint: must be an http or https URL, got %q", c.Tracing.Endpoint)
+		}
+	}
+
+	if err := c.Audit.Validate(); err != nil {
+		check(false, "audit.%v", err)
+	}
+
+	if err := c.AuthConfig().Validate(); err != nil {
+		check(false, "auth.keys: %v", err)
+	}
+	if err := c.OAuthConfig().Validate(); err != nil {
+		check(false, "oauth: %v", err)
+	}
+
+	switch c.Logging.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		check(false, "logging.level: must be debug, info, warn or error, got %q", c.Logging.Level)
+	}
+	check(c.Logging.Format == "text" || c.Logging.Format == "json", "logging.format: must be text or json, got %q", c.Logging.Format)
+
+	if u, err := url.Parse(c.Zabbix.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		check(false, "zabbix.url: must be an http or https URL, got %q", c.Zabbix.URL)
+	}
+	check((c.Zabbix.User == "") == (c.Zabbix.Password == ""), "zabbix.user and zabbix.password must be set together")
+	check(c.Zabbix.Timeout >= 0, "zabbix.timeout: must not be negative")
+	check(c.Zabbix.MaxRetries >= 0, "zabbix.max_retries: must not be negative")
+	if _, err := c.Zabbix.TLS.ClientConfig(); err != nil {
+		check(false, "zabbix.tls: %v", err)
+	}
+	check(c.Zabbix.MaxConnsPerHost >= 0, "zabbix.max_conns_per_host: must not be negative")
+	check(c.Zabbix.MaxIdleConnsPerHost >= 0, "zabbix.max_idle_conns_per_host: must not be negative")
+	check(c.Zabbix.FederationConcurrency > 0, "zabbix.federation_concurrency: must be positive")
+	if err := c.InstancesConfig().Validate(); err != nil {
+		check(false, "zabbix.instances: %v", err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Duration is a time.Duration written as "30s" or as whole seconds
+type Duration time.Duration
+
+// UnmarshalText parses a duration such as "1m30s" or "90"
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := parseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// UnmarshalYAML parses a duration from a YAML string or number
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	return d.UnmarshalText([]byte(node.Value))
+}
+
+// MarshalText formats the duration like time.Duration
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
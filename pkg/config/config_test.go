@@ -0,0 +1,315 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/audit"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/config"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tracing"
+)
+
+const yamlConfig = `
+transport:
+  mode: streamable-http
+  port: 9090
+  shutdown_timeout: 10
+cors:
+  allowed_origins: [https://app.example.com]
+rate_limit:
+  session_rps: 2.5
+tools:
+  toolsets: [hosts, problems]
+zabbix:
+  url: https://zabbix.example.com/api_jsonrpc.php
+  timeout: 1m
+  default_instance: eu
+  instances:
+    - name: eu
+      url: https://zabbix-eu.example.com/api_jsonrpc.php
+      token: eu-token
+      skip_tls_verify: true
+      tls:
+        min_version: "1.3"
+        server_name: zabbix-eu.internal
+`
+
+const tomlConfig = `
+[transport]
+mode = "streamable-http"
+port = 9090
+shutdown_timeout = 10
+
+[cors]
+allowed_origins = ["https://app.example.com"]
+
+[rate_limit]
+session_rps = 2.5
+
+[tools]
+toolsets = ["hosts", "problems"]
+
+[zabbix]
+url = "https://zabbix.example.com/api_jsonrpc.php"
+timeout = "1m"
+default_instance = "eu"
+
+[[zabbix.instances]]
+name = "eu"
+url = "https://zabbix-eu.example.com/api_jsonrpc.php"
+token = "eu-token"
+skip_tls_verify = true
+
+[zabbix.instances.tls]
+min_version = "1.3"
+server_name = "zabbix-eu.internal"
+`
+
+// clearEnv unsets the variables of every setting for the test
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, env := range []string{
+		config.ConfigFile, config.TransportMode, config.TransportHost, config.TransportPort, config.Endpoint,
+		config.ShutdownTimeout, config.ReadHeaderTimeout, config.IdleTimeout, config.TLSCertFile, config.TLSKeyFile, config.TLSClientCAFile,
+		config.LogLevel, tracing.TracingExporter, tracing.TracingEndpoint, tracing.TracingFile, audit.AuditFile, audit.AuditMaxSizeMB, audit.AuditMaxBackups, config.LogFile, config.LogFormat, config.Toolsets, config.EnableTools, config.DisableTools, config.DryRun,
+		client.SessionIdleTTL, client.ReadyCacheTTL, client.CORSModeEnv, client.AllowedOriginsEnv,
+		client.RateLimitGlobalRPS, client.RateLimitGlobalBurst, client.RateLimitSessionRPS, client.RateLimitSessionBurst, client.RateLimitIPRPS, client.RateLimitIPBurst,
+		client.RateLimitZabbixRPS, client.RateLimitZabbixBurst, client.ZabbixReadOnly,
+		client.ZabbixURL, client.ZabbixToken, client.ZabbixUser, client.ZabbixPassword, client.ZabbixSkipTLSVerify,
+		client.ZabbixTLSCAFile, client.ZabbixTLSCertFile, client.ZabbixTLSKeyFile, client.ZabbixTLSMinVersion, client.ZabbixTLSServerName,
+		client.ZabbixTimeout, client.ZabbixMaxRetries, client.ZabbixMaxConnsPerHost, client.ZabbixMaxIdleConnsPerHost,
+		client.ZabbixInstancesFile, client.ZabbixInstances, client.AuthKeysFile, client.AuthAPIKeys, client.AuthHMACSecret,
+		client.OAuthIssuer, client.OAuthAudience, client.OAuthResource, client.OAuthJWKSFile, client.OAuthJWKSURL, client.OAuthRequiredScopes, client.OAuthClaimsFile,
+	} {
+		t.Setenv(env, "")
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoad(t *testing.T) {
+	want := config.Default()
+	want.Transport.Mode = "streamable-http"
+	want.Transport.Port = 9090
+	want.Transport.ShutdownTimeout = config.Duration(10 * time.Second)
+	want.CORS.AllowedOrigins = []string{"https://app.example.com"}
+	want.RateLimit.SessionRPS = 2.5
+	want.Tools.Toolsets = []string{"hosts", "problems"}
+	want.Zabbix.URL = "https://zabbix.example.com/api_jsonrpc.php"
+	want.Zabbix.Timeout = config.Duration(time.Minute)
+	want.Zabbix.DefaultInstance = "eu"
+	want.Zabbix.Instances = []client.Instance{{Name: "eu", URL: "https://zabbix-eu.example.com/api_jsonrpc.php", Token: "eu-token", SkipTLSVerify: true,
+		TLS: client.TLSConfig{MinVersion: "1.3", ServerName: "zabbix-eu.internal"}}}
+
+	for name, content := range map[string]string{"server.yaml": yamlConfig, "server.toml": tomlConfig} {
+		got, err := config.Load(writeFile(t, name, content))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", name, got, want)
+		}
+		if err := got.Validate(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestLoadRejectsUnknownSettings(t *testing.T) {
+	for name, content := range map[string]string{
+		"server.yaml": "transport:\n  prot: 9090\n",
+		"server.toml": "[transport]\nprot = 9090\n",
+		"server.ini":  "[transport]\n",
+	} {
+		if _, err := config.Load(writeFile(t, name, content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	clearEnv(t)
+	cfg, err := config.Load(writeFile(t, "server.yaml", yamlConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(config.TransportPort, "9191")
+	t.Setenv(client.ZabbixTimeout, "15")
+	t.Setenv(config.Toolsets, "events")
+	if err := cfg.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The environment wins over the file, which wins over the defaults
+	if cfg.Transport.Port != 9191 || cfg.Zabbix.Timeout != config.Duration(15*time.Second) || !reflect.DeepEqual(cfg.Tools.Toolsets, []string{"events"}) {
+		t.Errorf("environment not applied: %+v", cfg)
+	}
+	if cfg.Transport.Mode != "streamable-http" || cfg.Transport.Host != config.DefaultBindAddress {
+		t.Errorf("file and defaults not kept: %+v", cfg.Transport)
+	}
+
+	t.Setenv(config.TransportPort, "http")
+	if err := cfg.ApplyEnv(); err == nil || !strings.Contains(err.Error(), config.TransportPort) {
+		t.Errorf("expected an error naming %s, got %v", config.TransportPort, err)
+	}
+}
+
+func TestConnectionConfig(t *testing.T) {
+	clearEnv(t)
+	cfg, err := config.Load(writeFile(t, "server.yaml", yamlConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Zabbix.SkipTLSVerify = true
+
+	connection := cfg.ConnectionConfig()
+	if connection.URL != "https://zabbix.example.com/api_jsonrpc.php" || connection.Timeout != time.Minute || !connection.TLS.SkipVerify {
+		t.Errorf("unexpected connection: %+v", connection)
+	}
+	if connection.RPS != cfg.RateLimit.ZabbixRPS || connection.FederationConcurrency != cfg.Zabbix.FederationConcurrency {
+		t.Errorf("limits not applied: %+v", connection)
+	}
+
+	httpConfig := cfg.HTTPConfig()
+	if httpConfig.RateLimit.SessionRPS != 2.5 || !reflect.DeepEqual(httpConfig.CORS.AllowedOrigins, []string{"https://app.example.com"}) {
+		t.Errorf("unexpected HTTP config: %+v", httpConfig)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := config.Default()
+	cfg.Transport.Mode = "sse"
+	cfg.Transport.Port = 70000
+	cfg.Transport.TLS.ClientCAFile = "ca.pem"
+	cfg.CORS.Mode = "open"
+	cfg.Logging.Level = "trace"
+	cfg.Logging.Format = "logfmt"
+	cfg.Tracing.Exporter = "jaeger"
+	cfg.Audit.MaxBackups = -1
+	cfg.Zabbix.URL = "zabbix.example.com"
+	cfg.Zabbix.User = "Admin"
+	cfg.Zabbix.TLS.CAFile = filepath.Join(t.TempDir(), "missing.pem")
+	cfg.Zabbix.Instances = []client.Instance{{Name: "eu"}}
+	cfg.Auth.Keys = []client.AuthKey{{Name: "noc"}}
+	cfg.OAuth.JWKSURL = "https://auth.example.com/jwks"
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	for _, want := range []string{"transport.mode", "transport.port", "transport.tls", "cors.mode", "logging.level", "logging.format", "tracing.exporter", "audit.max_backups", "zabbix.url", "zabbix.password", "zabbix.tls", `instance "eu" has no url`, "auth.keys", "oauth: an audience"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected an error about %s, got:\n%v", want, err)
+		}
+	}
+
+	if err := config.Default().Validate(); err != nil {
+		t.Errorf("defaults must be valid: %v", err)
+	}
+}
+
+func TestApplyEnvLists(t *testing.T) {
+	clearEnv(t)
+	cfg := config.Default()
+	t.Setenv(client.ZabbixInstances, "eu=https://zabbix-eu.example.com/api_jsonrpc.php|eu-token, us=https://zabbix-us.example.com/api_jsonrpc.php|us-token")
+	t.Setenv(client.AuthAPIKeys, "noc:noc-key,dev:dev-key")
+	t.Setenv(client.OAuthRequiredScopes, "zabbix:read, zabbix:write")
+	if err := cfg.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+
+	us, ok := cfg.InstancesConfig().Lookup("us")
+	if len(cfg.Zabbix.Instances) != 2 || !ok || us.URL != "https://zabbix-us.example.com/api_jsonrpc.php" || us.Token != "us-token" {
+		t.Errorf("unexpected instances: %+v", cfg.Zabbix.Instances)
+	}
+	if want := []client.AuthKey{{Name: "noc", Key: "noc-key"}, {Name: "dev", Key: "dev-key"}}; !reflect.DeepEqual(cfg.Auth.Keys, want) {
+		t.Errorf("unexpected keys: %+v", cfg.Auth.Keys)
+	}
+	if scopes := cfg.OAuthConfig().RequiredScopes; !reflect.DeepEqual(scopes, []string{"zabbix:read", "zabbix:write"}) {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+
+	for env, values := range map[string][]string{
+		client.ZabbixInstances: {"eu", "eu=https://zabbix-eu.example.com"},
+		client.AuthAPIKeys:     {"noc", "noc:", ":noc-key"},
+	} {
+		for _, value := range values {
+			t.Setenv(env, value)
+			if err := cfg.ApplyEnv(); err == nil || !strings.Contains(err.Error(), env) {
+				t.Errorf("%s=%q: expected an error naming %s, got %v", env, value, env, err)
+			}
+			t.Setenv(env, "")
+		}
+	}
+}
+
+func TestLoadFiles(t *testing.T) {
+	clearEnv(t)
+	keysFile := writeFile(t, "keys.json", `{"hmac_secret": "file-secret", "keys": [{"name": "noc", "key": "noc-key", "admin": true}]}`)
+	claimsFile := writeFile(t, "claims.json", `{"principal_claim": "email", "mappings": [{"claim": "groups", "value": "noc", "zabbix_token": "noc-token"}]}`)
+	instancesFile := writeFile(t, "instances.json", `{"default": "us", "instances": [{"name": "us", "url": "https://zabbix-us.example.com/api_jsonrpc.php", "token": "us-token"}]}`)
+
+	cfg, err := config.Load(writeFile(t, "server.yaml", yamlConfig+`
+  instances_file: `+instancesFile+`
+auth:
+  keys_file: `+keysFile+`
+  keys:
+    - name: dev
+      key: dev-key
+oauth:
+  jwks_url: https://auth.example.com/jwks
+  resource: https://mcp.example.com
+  required_scopes: [zabbix:read]
+  claims:
+    principal_claim: sub
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(client.OAuthClaimsFile, claimsFile)
+	if err := cfg.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.LoadFiles(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The files add to the settings of the configuration file
+	auth := cfg.HTTPConfig().Auth
+	if len(auth.Keys) != 2 || auth.Keys[0].Name != "noc" || !auth.Keys[0].Admin || auth.Keys[1].Name != "dev" || auth.HMACSecret != "file-secret" {
+		t.Errorf("unexpected auth config: %+v", auth)
+	}
+	if names := cfg.InstancesConfig().Names(); !reflect.DeepEqual(names, []string{"us", "eu"}) || cfg.Zabbix.DefaultInstance != "eu" {
+		t.Errorf("unexpected instances %v, default %q", names, cfg.Zabbix.DefaultInstance)
+	}
+
+	// The claim mappings file replaces the claims of the configuration file
+	oauth := cfg.HTTPConfig().OAuth
+	if !oauth.Enabled() || oauth.Audience != "https://mcp.example.com" || oauth.Claims.PrincipalClaim != "email" || len(oauth.Claims.Mappings) != 1 {
+		t.Errorf("unexpected OAuth config: %+v", oauth)
+	}
+
+	cfg.Auth.KeysFile = filepath.Join(t.TempDir(), "missing.json")
+	if err := cfg.LoadFiles(); err == nil || !strings.Contains(err.Error(), "auth.keys_file") {
+		t.Errorf("expected an error naming auth.keys_file, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/audit"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tracing"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
+)
+
+// binding ties a setting to its environment variable
+type binding struct {
+	env string
+	// value points to the setting in a Config
+	value interface{}
+}
+
+// bindings returns the settings of c that can be set from the environment
+func (c *Config) bindings() []binding {
+	return []binding{
+		{TransportMode, &c.Transport.Mode},
+		{TransportHost, &c.Transport.Host},
+		{TransportPort, &c.Transport.Port},
+		{Endpoint, &c.Transport.Endpoint},
+		{client.SessionIdleTTL, &c.Transport.SessionIdleTTL},
+		{ShutdownTimeout, &c.Transport.ShutdownTimeout},
+		{ReadHeaderTimeout, &c.Transport.ReadHeaderTimeout},
+		{IdleTimeout, &c.Transport.IdleTimeout},
+		{client.ReadyCacheTTL, &c.Transport.ReadyCacheTTL},
+		{TLSCertFile, &c.Transport.TLS.CertFile},
+		{TLSKeyFile, &c.Transport.TLS.KeyFile},
+		{TLSClientCAFile, &c.Transport.TLS.ClientCAFile},
+		{client.CORSModeEnv, &c.CORS.Mode},
+		{client.AllowedOriginsEnv, &c.CORS.AllowedOrigins},
+		{client.RateLimitGlobalRPS, &c.RateLimit.GlobalRPS},
+		{client.RateLimitGlobalBurst, &c.RateLimit.GlobalBurst},
+		{client.RateLimitSessionRPS, &c.RateLimit.SessionRPS},
+		{client.RateLimitSessionBurst, &c.RateLimit.SessionBurst},
+		{client.RateLimitIPRPS, &c.RateLimit.IPRPS},
+		{client.RateLimitIPBurst, &c.RateLimit.IPBurst},
+		{client.RateLimitZabbixRPS, &c.RateLimit.ZabbixRPS},
+		{client.RateLimitZabbixBurst, &c.RateLimit.ZabbixBurst},
+		{LogLevel, &c.Logging.Level},
+		{LogFile, &c.Logging.File},
+		{LogFormat, &c.Logging.Format},
+		{tracing.TracingExporter, &c.Tracing.Exporter},
+		{tracing.TracingEndpoint, &c.Tracing.Endpoint},
+		{tracing.TracingFile, &c.Tracing.File},
+		{audit.AuditFile, &c.Audit.File},
+		{audit.AuditMaxSizeMB, &c.Audit.MaxSizeMB},
+		{audit.AuditMaxBackups, &c.Audit.MaxBackups},
+		{client.AuthKeysFile, &c.Auth.KeysFile},
+		{client.AuthAPIKeys, &c.Auth.Keys},
+		{client.AuthHMACSecret, &c.Auth.HMACSecret},
+		{client.OAuthIssuer, &c.OAuth.Issuer},
+		{client.OAuthAudience, &c.OAuth.Audience},
+		{client.OAuthResource, &c.OAuth.Resource},
+		{client.OAuthJWKSFile, &c.OAuth.JWKSFile},
+		{client.OAuthJWKSURL, &c.OAuth.JWKSURL},
+		{client.OAuthRequiredScopes, &c.OAuth.RequiredScopes},
+		{client.OAuthClaimsFile, &c.OAuth.ClaimMappingsFile},
+		{client.ZabbixReadOnly, &c.Tools.ReadOnly},
+		{DryRun, &c.Tools.DryRun},
+		{Toolsets, &c.Tools.Toolsets},
+		{EnableTools, &c.Tools.EnableTools},
+		{DisableTools, &c.Tools.DisableTools},
+		{client.ZabbixURL, &c.Zabbix.URL},
+		{client.ZabbixToken, &c.Zabbix.Token},
+		{client.ZabbixUser, &c.Zabbix.User},
+		{client.ZabbixPassword, &c.Zabbix.Password},
+		{client.ZabbixSkipTLSVerify, &c.Zabbix.SkipTLSVerify},
+		{client.ZabbixTLSCAFile, &c.Zabbix.TLS.CAFile},
+		{client.ZabbixTLSCertFile, &c.Zabbix.TLS.CertFile},
+		{client.ZabbixTLSKeyFile, &c.Zabbix.TLS.KeyFile},
+		{client.ZabbixTLSMinVersion, &c.Zabbix.TLS.MinVersion},
+		{client.ZabbixTLSServerName, &c.Zabbix.TLS.ServerName},
+		{client.ZabbixTimeout, &c.Zabbix.Timeout},
+		{client.ZabbixMaxRetries, &c.Zabbix.MaxRetries},
+		{client.ZabbixMaxConnsPerHost, &c.Zabbix.MaxConnsPerHost},
+		{client.ZabbixMaxIdleConnsPerHost, &c.Zabbix.MaxIdleConnsPerHost},
+		{client.ZabbixFederationConcurrency, &c.Zabbix.FederationConcurrency},
+		{client.ZabbixInstancesFile, &c.Zabbix.InstancesFile},
+		{client.ZabbixInstances, &c.Zabbix.Instances},
+	}
+}
+
+// parse sets the setting value points to from its environment form
+func parse(value interface{}, s string) error {
+	switch v := value.(type) {
+	case *string:
+		*v = s
+	case *[]string:
+		*v = utils.SplitAndTrim(s)
+	case *int:
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", s)
+		}
+		*v = i
+	case *float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", s)
+		}
+		*v = f
+	case *bool:
+		switch strings.ToLower(s) {
+		case "true", "1":
+			*v = true
+		case "false", "0":
+			*v = false
+		default:
+			return fmt.Errorf("%q is not true or false", s)
+		}
+	case *Duration:
+		return v.UnmarshalText([]byte(s))
+	case *[]client.AuthKey:
+		// A comma-separated list of name:key pairs
+		var keys []client.AuthKey
+		for _, entry := range utils.SplitAndTrim(s) {
+			name, key, ok := strings.Cut(entry, ":")
+			if !ok || name == "" || key == "" {
+				return fmt.Errorf("entry %q is not name:key", entry)
+			}
+			keys = append(keys, client.AuthKey{Name: name, Key: key})
+		}
+		*v = keys
+	case *[]client.Instance:
+		// A comma-separated list of name=url|token entries
+		var instances []client.Instance
+		for _, entry := range utils.SplitAndTrim(s) {
+			name, endpoint, _ := strings.Cut(entry, "=")
+			separator := strings.LastIndex(endpoint, "|")
+			if name == "" || separator < 0 {
+				return fmt.Errorf("entry %q is not name=url|token", entry)
+			}
+			instances = append(instances, client.Instance{Name: name, URL: endpoint[:separator], Token: endpoint[separator+1:]})
+		}
+		*v = instances
+	default:
+		return fmt.Errorf("unsupported setting type %T", value)
+	}
+	return nil
+}
+
+// parseDuration parses a Go duration; plain integers are seconds, as for
+// the duration environment variables read by pkg/client
+func parseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if seconds, err := strconv.Atoi(s); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a duration", s)
+	}
+	return d, nil
+}
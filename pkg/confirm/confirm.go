@@ -0,0 +1,199 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package confirm asks a human to approve destructive tool calls, using MCP
+// elicitation when the client supports it and a confirm argument otherwise.
+package confirm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+)
+
+// Argument is the boolean tool argument used to confirm an operation when
+// the client does not support elicitation
+const Argument = "confirm"
+
+// WithConfirm adds the confirm argument to a tool definition
+func WithConfirm() mcp.ToolOption {
+	return mcp.WithBoolean(Argument,
+		mcp.Description("Set to true once the user has approved the operation. Only used when the client does not support interactive confirmation."),
+	)
+}
+
+// Object is a Zabbix object affected by a destructive operation
+type Object struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	// Details describes dependent objects, e.g. "12 items, 4 triggers"
+	Details string `json:"details,omitempty"`
+}
+
+// Operation describes a destructive operation awaiting confirmation
+type Operation struct {
+	// Action is a short summary such as "Delete 2 hosts"
+	Action  string   `json:"action"`
+	Objects []Object `json:"objects"`
+	// Warning is an optional note about side effects
+	Warning string `json:"warning,omitempty"`
+}
+
+// Message renders the operation as the text shown to the user
+func (op Operation) Message() string {
+	var b strings.Builder
+	b.WriteString(op.Action)
+	b.WriteString(":\n")
+	for _, obj := range op.Objects {
+		b.WriteString("  - ")
+		if obj.Name != "" {
+			fmt.Fprintf(&b, "%s (%s)", obj.Name, obj.ID)
+		} else {
+			b.WriteString(obj.ID)
+		}
+		if obj.Details != "" {
+			fmt.Fprintf(&b, ": %s", obj.Details)
+		}
+		b.WriteString("\n")
+	}
+	if op.Warning != "" {
+		b.WriteString(op.Warning)
+		b.WriteString("\n")
+	}
+	b.WriteString("This cannot be undone. Proceed?")
+	return b.String()
+}
+
+// Request asks the user to confirm op. It returns nil when the operation may
+// proceed; otherwise it returns the tool result the handler should return.
+//
+// When the client supports elicitation the user is always asked, and the
+// confirm argument is ignored so the model cannot approve on its own. Dry
+// runs proceed without asking, as they change nothing.
+func Request(ctx context.Context, req mcp.CallToolRequest, op Operation, logger *log.Logger) *mcp.CallToolResult {
+	if client.IsDryRun(ctx) {
+		return nil
+	}
+
+	if supportsElicitation(ctx) {
+		result, err := elicit(ctx, op)
+		if err == nil {
+			return result
+		}
+		if !errors.Is(err, server.ErrElicitationNotSupported) && !errors.Is(err, server.ErrNoActiveSession) {
+			logger.WithError(err).Error("Failed to request confirmation")
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to request confirmation: %v", err))
+		}
+	}
+
+	if req.GetBool(Argument, false) {
+		logger.WithField("action", op.Action).Info("Operation confirmed by argument")
+		return nil
+	}
+
+	jsonData, _ := json.MarshalIndent(map[string]interface{}{
+		"message":   fmt.Sprintf("Confirmation required. Show the user the affected objects and call this tool again with %s: true once they approve.", Argument),
+		"operation": op,
+	}, "", "  ")
+	return mcp.NewToolResultError(string(jsonData))
+}
+
+// elicit sends an elicitation request and maps the user's answer to a result
+func elicit(ctx context.Context, op Operation) (*mcp.CallToolResult, error) {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithElicitation)
+	if !ok {
+		return nil, server.ErrElicitationNotSupported
+	}
+
+	result, err := session.RequestElicitation(ctx, mcp.ElicitationRequest{
+		Params: mcp.ElicitationParams{
+			Message: op.Message(),
+			RequestedSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					Argument: map[string]interface{}{
+						"type":        "boolean",
+						"title":       "Confirm",
+						"description": op.Action,
+					},
+				},
+				"required": []string{Argument},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Action != mcp.ElicitationResponseActionAccept {
+		return mcp.NewToolResultError(fmt.Sprintf("Operation cancelled: user chose to %s", result.Action)), nil
+	}
+	if content, ok := result.Content.(map[string]interface{}); ok {
+		if confirmed, _ := content[Argument].(bool); confirmed {
+			return nil, nil
+		}
+	}
+	return mcp.NewToolResultError("Operation cancelled: user did not confirm"), nil
+}
+
+// supportsElicitation reports whether the calling client advertised the
+// elicitation capability
+func supportsElicitation(ctx context.Context) bool {
+	session := server.ClientSessionFromContext(ctx)
+	if _, ok := session.(server.SessionWithElicitation); !ok {
+		return false
+	}
+	if info, ok := session.(server.SessionWithClientInfo); ok {
+		return info.GetClientCapabilities().Elicitation != nil
+	}
+	return false
+}
+
+// Count decodes the object counts Zabbix returns for select*: "count",
+// which are encoded as strings
+type Count int
+
+// UnmarshalJSON accepts both quoted and bare numbers
+func (c *Count) UnmarshalJSON(data []byte) error {
+	n, err := strconv.Atoi(strings.Trim(string(data), `"`))
+	if err != nil {
+		return fmt.Errorf("invalid count %s: %w", data, err)
+	}
+	*c = Count(n)
+	return nil
+}
+
+// Plural formats a count with a singular or plural noun
+func Plural[N ~int](n N, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// Resolve orders found objects by ids and adds an entry for every ID that
+// does not exist, so the user sees exactly what was requested
+func Resolve(ids []string, found []Object) []Object {
+	byID := make(map[string]Object, len(found))
+	for _, obj := range found {
+		byID[obj.ID] = obj
+	}
+
+	objects := make([]Object, 0, len(ids))
+	for _, id := range ids {
+		obj, ok := byID[id]
+		if !ok {
+			obj = Object{ID: id, Details: "not found"}
+		}
+		objects = append(objects, obj)
+	}
+	return objects
+}
@@ -0,0 +1,161 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package confirm_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vfcastr/Zabbix-MCP/pkg/confirm"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+var operation = confirm.Operation{
+	Action:  "Delete 2 hosts",
+	Objects: []confirm.Object{{ID: "10084", Name: "web01", Details: "3 items"}, {ID: "10085", Details: "not found"}},
+	Warning: "History will be lost.",
+}
+
+func request(args map[string]interface{}) mcp.CallToolRequest {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+	return req
+}
+
+// eliciting returns a session that supports elicitation and answers with
+// result or err
+func eliciting(result *mcp.ElicitationResult, err error) *zabbixtest.Session {
+	session := zabbixtest.NewSession()
+	session.Capabilities.Elicitation = &struct{}{}
+	session.Elicit = func(ctx context.Context, req mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+		return result, err
+	}
+	return session
+}
+
+func answer(action mcp.ElicitationResponseAction, content interface{}) *mcp.ElicitationResult {
+	return &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{Action: action, Content: content}}
+}
+
+func TestMessage(t *testing.T) {
+	want := "Delete 2 hosts:\n  - web01 (10084): 3 items\n  - 10085: not found\nHistory will be lost.\nThis cannot be undone. Proceed?"
+	if got := operation.Message(); got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+}
+
+func TestRequestWithoutElicitation(t *testing.T) {
+	ctx := zabbixtest.NewServer(t).Context(t)
+
+	result := confirm.Request(ctx, request(map[string]interface{}{}), operation, zabbixtest.Logger())
+	if result == nil || !result.IsError {
+		t.Fatalf("expected a confirmation error, got %+v", result)
+	}
+	var body struct {
+		Message   string            `json:"message"`
+		Operation confirm.Operation `json:"operation"`
+	}
+	if err := json.Unmarshal([]byte(zabbixtest.ResultText(result)), &body); err != nil {
+		t.Fatalf("invalid confirmation body: %v", err)
+	}
+	if !strings.Contains(body.Message, "confirm: true") || body.Operation.Action != operation.Action || len(body.Operation.Objects) != 2 {
+		t.Errorf("unexpected confirmation body: %+v", body)
+	}
+
+	if result := confirm.Request(ctx, request(map[string]interface{}{"confirm": true}), operation, zabbixtest.Logger()); result != nil {
+		t.Errorf("expected confirm: true to proceed, got %s", zabbixtest.ResultText(result))
+	}
+}
+
+func TestRequestWithElicitation(t *testing.T) {
+	cases := []struct {
+		name      string
+		result    *mcp.ElicitationResult
+		err       error
+		args      map[string]interface{}
+		wantError string
+	}{
+		{
+			name:   "accepted",
+			result: answer(mcp.ElicitationResponseActionAccept, map[string]interface{}{"confirm": true}),
+		},
+		{
+			name:      "accepted without confirming",
+			result:    answer(mcp.ElicitationResponseActionAccept, map[string]interface{}{"confirm": false}),
+			wantError: "user did not confirm",
+		},
+		{
+			name:      "declined",
+			result:    answer(mcp.ElicitationResponseActionDecline, nil),
+			wantError: "user chose to decline",
+		},
+		{
+			name:      "cancelled",
+			result:    answer(mcp.ElicitationResponseActionCancel, nil),
+			wantError: "user chose to cancel",
+		},
+		{
+			name:      "confirm argument is ignored",
+			result:    answer(mcp.ElicitationResponseActionDecline, nil),
+			args:      map[string]interface{}{"confirm": true},
+			wantError: "user chose to decline",
+		},
+		{
+			name:      "elicitation fails",
+			err:       errors.New("transport closed"),
+			wantError: "Failed to request confirmation: transport closed",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			session := eliciting(tc.result, tc.err)
+			ctx := zabbixtest.NewServer(t).SessionContext(t, session)
+
+			result := confirm.Request(ctx, request(tc.args), operation, zabbixtest.Logger())
+			switch {
+			case tc.wantError == "" && result != nil:
+				t.Fatalf("expected to proceed, got %s", zabbixtest.ResultText(result))
+			case tc.wantError != "" && (result == nil || !strings.Contains(zabbixtest.ResultText(result), tc.wantError)):
+				t.Fatalf("expected error containing %q, got %+v", tc.wantError, result)
+			}
+
+			elicitations := session.Elicitations()
+			if len(elicitations) != 1 {
+				t.Fatalf("expected 1 elicitation, got %d", len(elicitations))
+			}
+			if elicitations[0].Params.Message != operation.Message() {
+				t.Errorf("unexpected elicitation message: %q", elicitations[0].Params.Message)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	got := confirm.Resolve([]string{"2", "1"}, []confirm.Object{{ID: "1", Name: "one"}})
+	want := []confirm.Object{{ID: "2", Details: "not found"}, {ID: "1", Name: "one"}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Resolve() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCount(t *testing.T) {
+	var counts struct {
+		Quoted confirm.Count `json:"quoted"`
+		Bare   confirm.Count `json:"bare"`
+	}
+	if err := json.Unmarshal([]byte(`{"quoted":"12","bare":3}`), &counts); err != nil {
+		t.Fatal(err)
+	}
+	if counts.Quoted != 12 || counts.Bare != 3 {
+		t.Errorf("unexpected counts: %+v", counts)
+	}
+	if got := confirm.Plural(counts.Bare, "item", "items"); got != "3 items" {
+		t.Errorf("Plural() = %q", got)
+	}
+}
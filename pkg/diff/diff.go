@@ -0,0 +1,313 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package diff compares a Zabbix object before and after an update, so
+// update tools can report exactly which fields changed, down to single
+// tags, macros and interfaces.
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+)
+
+// Kinds of change
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change is one field or list element that differs between two versions of
+// an object
+type Change struct {
+	// Field is the path of the field, e.g. "status", "inventory.os",
+	// "tags[env:prod]" or "macros[{$DB.USER}].value"
+	Field  string      `json:"field"`
+	Kind   string      `json:"change"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// listKeys are the fields identifying the elements of object lists, so
+// reordering a list is not reported as a change. Elements of other lists
+// are compared by position.
+var listKeys = map[string][]string{
+	"tags":            {"tag", "value"},
+	"macros":          {"macro"},
+	"interfaces":      {"interfaceid"},
+	"groups":          {"groupid"},
+	"hostgroups":      {"groupid"},
+	"templategroups":  {"groupid"},
+	"parentTemplates": {"templateid"},
+	"templates":       {"templateid"},
+	"hosts":           {"hostid"},
+	"dependencies":    {"triggerid"},
+}
+
+// secretMacroType is the user macro type whose value is secret
+const secretMacroType = "1"
+
+// Compare returns the changes from before to after. Object fields are
+// visited in sorted order; within a list of objects, changed and removed
+// elements come first in their old order, then added elements in their new
+// order. Fields in ignore are skipped; nested fields are named by their path
+// without list labels, e.g. "interfaces.available". Secret values are
+// redacted.
+func Compare(before, after map[string]interface{}, ignore ...string) []Change {
+	c := comparer{ignore: make(map[string]bool, len(ignore)), changes: []Change{}}
+	for _, field := range ignore {
+		c.ignore[field] = true
+	}
+	c.maps("", "", before, after)
+	return c.changes
+}
+
+type comparer struct {
+	ignore  map[string]bool
+	changes []Change
+}
+
+// maps compares two objects. path names the object in changes and pattern
+// in the ignore list.
+func (c *comparer) maps(path, pattern string, before, after map[string]interface{}) {
+	keys := make(map[string]bool, len(before)+len(after))
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	secret := isSecretMacro(before) || isSecretMacro(after)
+	for _, key := range sorted {
+		fieldPattern := join(pattern, key)
+		if c.ignore[fieldPattern] {
+			continue
+		}
+		fieldPath := join(path, key)
+		oldValue, inBefore := before[key]
+		newValue, inAfter := after[key]
+		switch {
+		case !inBefore:
+			c.add(Change{Field: fieldPath, Kind: Added, After: newValue}, key, secret)
+		case !inAfter:
+			c.add(Change{Field: fieldPath, Kind: Removed, Before: oldValue}, key, secret)
+		default:
+			c.values(fieldPath, fieldPattern, key, oldValue, newValue, secret)
+		}
+	}
+}
+
+// values compares two values of the field key
+func (c *comparer) values(path, pattern, key string, before, after interface{}, secret bool) {
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+
+	oldMap, oldIsMap := before.(map[string]interface{})
+	newMap, newIsMap := after.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		c.maps(path, pattern, oldMap, newMap)
+		return
+	}
+
+	oldList, oldIsList := before.([]interface{})
+	newList, newIsList := after.([]interface{})
+	if oldIsList && newIsList && isObjectList(oldList) && isObjectList(newList) {
+		c.lists(path, pattern, key, oldList, newList)
+		return
+	}
+
+	c.add(Change{Field: path, Kind: Changed, Before: before, After: after}, key, secret)
+}
+
+// lists compares two lists of objects, matching their elements by the keys
+// of the list field, or by position
+func (c *comparer) lists(path, pattern, key string, before, after []interface{}) {
+	oldElems, oldOrder := label(key, before)
+	newElems, newOrder := label(key, after)
+
+	for _, l := range oldOrder {
+		elemPath := fmt.Sprintf("%s[%s]", path, l)
+		if newElem, ok := newElems[l]; ok {
+			c.maps(elemPath, pattern, oldElems[l], newElem)
+		} else {
+			c.add(Change{Field: elemPath, Kind: Removed, Before: oldElems[l]}, "", false)
+		}
+	}
+	for _, l := range newOrder {
+		if _, ok := oldElems[l]; !ok {
+			c.add(Change{Field: fmt.Sprintf("%s[%s]", path, l), Kind: Added, After: newElems[l]}, "", false)
+		}
+	}
+}
+
+// label names the elements of a list by their key fields, or their position
+// when the list has no keys or an element lacks them
+func label(key string, list []interface{}) (map[string]map[string]interface{}, []string) {
+	elems := make(map[string]map[string]interface{}, len(list))
+	order := make([]string, 0, len(list))
+	for i, item := range list {
+		elem := item.(map[string]interface{})
+		var parts []string
+		for _, field := range listKeys[key] {
+			value, ok := elem[field]
+			if !ok {
+				parts = nil
+				break
+			}
+			parts = append(parts, fmt.Sprint(value))
+		}
+		l := strings.Join(parts, ":")
+		if len(parts) == 0 || elems[l] != nil {
+			l = fmt.Sprint(i)
+		}
+		elems[l] = elem
+		order = append(order, l)
+	}
+	return elems, order
+}
+
+// add records a change, redacting the values of secret fields and of the
+// value of secret macros. Added and removed objects are redacted as a
+// whole.
+func (c *comparer) add(change Change, key string, secretMacro bool) {
+	if logging.IsSecretKey(key) || (secretMacro && key == "value") {
+		if change.Before != nil {
+			change.Before = logging.Redacted
+		}
+		if change.After != nil {
+			change.After = logging.Redacted
+		}
+	} else {
+		change.Before = logging.Redact(change.Before)
+		change.After = logging.Redact(change.After)
+	}
+	c.changes = append(c.changes, change)
+}
+
+func isSecretMacro(obj map[string]interface{}) bool {
+	_, isMacro := obj["macro"]
+	return isMacro && fmt.Sprint(obj["type"]) == secretMacroType
+}
+
+func isObjectList(list []interface{}) bool {
+	for _, item := range list {
+		if _, ok := item.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func join(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// Getter fetches the objects of one type that an update tool changes
+type Getter struct {
+	// Method is the get method, e.g. host.get
+	Method string
+	// IDsParam filters the get call by ID, e.g. hostids
+	IDsParam string
+	// Params are added to the get call, e.g. select options for tags and
+	// macros
+	Params map[string]interface{}
+	// Ignore lists fields that change without an update, e.g. lastvalue
+	Ignore []string
+}
+
+// Fetch returns the object with the given ID, or nil when it does not
+// exist. Nothing is fetched in a dry run, as nothing will change.
+func (g Getter) Fetch(ctx context.Context, zabbix *client.ZabbixClient, id string) (map[string]interface{}, error) {
+	if client.IsDryRun(ctx) {
+		return nil, nil
+	}
+
+	params := map[string]interface{}{
+		"output":   "extend",
+		g.IDsParam: []string{id},
+	}
+	for key, value := range g.Params {
+		params[key] = value
+	}
+	result, err := zabbix.CallContext(ctx, g.Method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(result, &objects); err != nil {
+		return nil, fmt.Errorf("failed to parse %s response: %w", g.Method, err)
+	}
+	if len(objects) == 0 {
+		return nil, nil
+	}
+	return objects[0], nil
+}
+
+// Update tracks an object through an update, so its changes can be
+// reported
+type Update struct {
+	getter Getter
+	zabbix *client.ZabbixClient
+	id     string
+	before map[string]interface{}
+	err    error
+}
+
+// Track fetches the object with the given ID before it is updated. A failed
+// fetch does not stop the update; Report then says why there is no diff.
+func (g Getter) Track(ctx context.Context, zabbix *client.ZabbixClient, id string) *Update {
+	u := &Update{getter: g, zabbix: zabbix, id: id}
+	if client.IsDryRun(ctx) {
+		return u
+	}
+	if u.before, u.err = g.Fetch(ctx, zabbix, id); u.err != nil {
+		u.err = fmt.Errorf("%s before the update failed: %w", g.Method, u.err)
+	} else if u.before == nil {
+		u.err = fmt.Errorf("%s returned no object with ID %s before the update", g.Method, id)
+	}
+	return u
+}
+
+// Report fetches the object again and sets "changes" in result. When the
+// object cannot be compared, it sets "changes_unavailable" to the reason
+// instead and returns the error. Nothing is reported in a dry run.
+func (u *Update) Report(ctx context.Context, result map[string]interface{}) error {
+	if client.IsDryRun(ctx) {
+		return nil
+	}
+	err := u.err
+	if err == nil {
+		var after map[string]interface{}
+		after, err = u.getter.Fetch(ctx, u.zabbix, u.id)
+		switch {
+		case err != nil:
+			err = fmt.Errorf("%s after the update failed: %w", u.getter.Method, err)
+		case after == nil:
+			err = fmt.Errorf("%s returned no object with ID %s after the update", u.getter.Method, u.id)
+		default:
+			result["changes"] = Compare(u.before, after, u.getter.Ignore...)
+			return nil
+		}
+	}
+	result["changes_unavailable"] = err.Error()
+	return err
+}
@@ -0,0 +1,82 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package diff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/diff"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+// decode parses a JSON object as the Zabbix client does
+func decode(t *testing.T, s string) map[string]interface{} {
+	t.Helper()
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &obj); err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
+
+func assertChanges(t *testing.T, changes []diff.Change, want string) {
+	t.Helper()
+	data, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zabbixtest.AssertJSON(t, data, want)
+}
+
+func TestCompare(t *testing.T) {
+	before := decode(t, `{
+		"hostid": "10084", "name": "web01", "description": "", "inventory": {"os": "linux", "location": "dc1"},
+		"interfaces": [{"interfaceid": "1", "ip": "10.0.0.1", "available": "1"}, {"interfaceid": "2", "ip": "10.0.0.2", "available": "1"}],
+		"macros": [{"macro": "{$A}", "value": "1"}],
+		"timeperiods": [{"period": "3600"}]
+	}`)
+	after := decode(t, `{
+		"hostid": "10084", "name": "web01", "description": "moved", "inventory": {"os": "linux", "location": "dc2"},
+		"interfaces": [{"interfaceid": "2", "ip": "10.0.0.2", "available": "2"}, {"interfaceid": "1", "ip": "10.0.0.9", "available": "2"}],
+		"macros": [{"macro": "{$A}", "value": "1"}, {"macro": "{$B}", "value": "2"}],
+		"timeperiods": [{"period": "7200"}],
+		"proxyid": "5"
+	}`)
+
+	assertChanges(t, diff.Compare(before, after, "interfaces.available"), `[
+		{"field": "description", "change": "changed", "before": "", "after": "moved"},
+		{"field": "interfaces[1].ip", "change": "changed", "before": "10.0.0.1", "after": "10.0.0.9"},
+		{"field": "inventory.location", "change": "changed", "before": "dc1", "after": "dc2"},
+		{"field": "macros[{$B}]", "change": "added", "after": {"macro": "{$B}", "value": "2"}},
+		{"field": "proxyid", "change": "added", "after": "5"},
+		{"field": "timeperiods[0].period", "change": "changed", "before": "3600", "after": "7200"}
+	]`)
+
+	if changes := diff.Compare(before, before); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestCompareRedactsSecrets(t *testing.T) {
+	before := decode(t, `{"tls_psk": "aaaa", "macros": [{"macro": "{$DB.PASSWORD}", "type": "0", "value": "old"}]}`)
+	after := decode(t, `{"tls_psk": "bbbb", "macros": [{"macro": "{$DB.PASSWORD}", "type": "1", "value": "new"}, {"macro": "{$TOKEN}", "type": "1", "value": "t0k3n"}]}`)
+
+	assertChanges(t, diff.Compare(before, after), `[
+		{"field": "macros[{$DB.PASSWORD}].type", "change": "changed", "before": "0", "after": "1"},
+		{"field": "macros[{$DB.PASSWORD}].value", "change": "changed", "before": "[REDACTED]", "after": "[REDACTED]"},
+		{"field": "macros[{$TOKEN}]", "change": "added", "after": {"macro": "{$TOKEN}", "type": "1", "value": "[REDACTED]"}},
+		{"field": "tls_psk", "change": "changed", "before": "[REDACTED]", "after": "[REDACTED]"}
+	]`)
+}
+
+func TestCompareTagsWithDuplicateNames(t *testing.T) {
+	before := decode(t, `{"tags": [{"tag": "role", "value": "web"}, {"tag": "role", "value": "db"}]}`)
+	after := decode(t, `{"tags": [{"tag": "role", "value": "db"}, {"tag": "role", "value": "cache"}]}`)
+
+	assertChanges(t, diff.Compare(before, after), `[
+		{"field": "tags[role:web]", "change": "removed", "before": {"tag": "role", "value": "web"}},
+		{"field": "tags[role:cache]", "change": "added", "after": {"tag": "role", "value": "cache"}}
+	]`)
+}
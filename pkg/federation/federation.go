@@ -0,0 +1,145 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package federation runs a read-only query on several named Zabbix
+// instances in parallel and merges the results, tagging each record with
+// the instance it came from.
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
+)
+
+// Argument is the tool argument naming the instances to query
+const Argument = "instances"
+
+// All queries every configured instance
+const All = "all"
+
+// Failure is an instance whose query failed
+type Failure struct {
+	Instance string `json:"instance"`
+	Error    string `json:"error"`
+}
+
+// Result is the merged output of a federated query
+type Result[T any] struct {
+	Results  []T       `json:"results"`
+	Failures []Failure `json:"failures,omitempty"`
+
+	// queried is the number of instances the query was sent to
+	queried int
+}
+
+// Targets returns the instances named by the call's instances argument, or
+// nil when the call queries a single Zabbix server
+func Targets(req mcp.CallToolRequest) ([]string, error) {
+	spec, _ := req.GetArguments()[Argument].(string)
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+	if instance, _ := req.GetArguments()["instance"].(string); instance != "" {
+		return nil, fmt.Errorf("use either instance or instances, not both")
+	}
+
+	config := client.Instances()
+	if len(config.Instances) == 0 {
+		return nil, fmt.Errorf("no named Zabbix instances are configured")
+	}
+	if spec == All {
+		return config.Names(), nil
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range utils.SplitAndTrim(spec) {
+		if _, ok := config.Lookup(name); !ok {
+			return nil, fmt.Errorf("unknown Zabbix instance %q (configured: %s)", name, strings.Join(config.Names(), ", "))
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// Query calls method with params on each target and decodes every result
+// into records of type T. tag records the instance on each record.
+// Instances that fail are reported in the result's failures.
+func Query[T any](ctx context.Context, targets []string, method string, params interface{}, tag func(record *T, instance string), logger *log.Logger) Result[T] {
+	merged := Result[T]{Results: []T{}, queried: len(targets)}
+
+	for _, result := range client.CallInstances(ctx, targets, method, params, logger) {
+		err := result.Err
+		var records []T
+		if err == nil {
+			if err = json.Unmarshal(result.Result, &records); err != nil {
+				err = fmt.Errorf("failed to parse %s response: %w", method, err)
+			}
+		}
+		if err != nil {
+			logger.WithError(err).WithField("instance", result.Instance).Warn("Federated query failed on instance")
+			merged.Failures = append(merged.Failures, Failure{Instance: result.Instance, Error: err.Error()})
+			continue
+		}
+
+		for i := range records {
+			tag(&records[i], result.Instance)
+		}
+		merged.Results = append(merged.Results, records...)
+	}
+
+	logger.WithFields(log.Fields{
+		"method":    method,
+		"instances": len(targets),
+		"failures":  len(merged.Failures),
+		"records":   len(merged.Results),
+	}).Debug("Federated query finished")
+
+	return merged
+}
+
+// Truncate keeps the first limit merged records, so limit caps the whole
+// result rather than each instance's share. Zero keeps every record.
+func (r *Result[T]) Truncate(limit int) {
+	if limit > 0 && len(r.Results) > limit {
+		r.Results = r.Results[:limit]
+	}
+}
+
+// ToolResult renders a merged result. It is an error only when every
+// instance failed.
+func ToolResult[T any](result Result[T]) *mcp.CallToolResult {
+	if result.queried > 0 && len(result.Failures) == result.queried {
+		messages := make([]string, 0, len(result.Failures))
+		for _, failure := range result.Failures {
+			messages = append(messages, fmt.Sprintf("%s: %s", failure.Instance, failure.Error))
+		}
+		return mcp.NewToolResultError("All Zabbix instances failed: " + strings.Join(messages, "; "))
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling JSON: %v", err))
+	}
+	return mcp.NewToolResultText(string(jsonData))
+}
+
+// Newer reports whether Unix timestamp a is later than b, for sorting
+// merged records newest first
+func Newer(a, b string) bool {
+	x, _ := strconv.ParseInt(a, 10, 64)
+	y, _ := strconv.ParseInt(b, 10, 64)
+	return x > y
+}
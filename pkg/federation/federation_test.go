@@ -0,0 +1,172 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package federation_test
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/federation"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+type record struct {
+	EventID  string `json:"eventid"`
+	Instance string `json:"instance,omitempty"`
+}
+
+func tagRecord(r *record, instance string) { r.Instance = instance }
+
+// setInstances configures a named instance for each server
+func setInstances(t *testing.T, servers map[string]*zabbixtest.Server) []string {
+	var config client.InstancesConfig
+	var names []string
+	for _, name := range []string{"eu", "us", "apac"} {
+		if s, ok := servers[name]; ok {
+			config.Instances = append(config.Instances, client.Instance{Name: name, URL: s.APIURL(), Token: zabbixtest.Token})
+			names = append(names, name)
+		}
+	}
+	client.SetInstances(config)
+	t.Cleanup(func() { client.SetInstances(client.InstancesConfig{}) })
+	return names
+}
+
+func request(args map[string]interface{}) mcp.CallToolRequest {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+	return req
+}
+
+func TestTargets(t *testing.T) {
+	if names, err := federation.Targets(request(map[string]interface{}{})); err != nil || names != nil {
+		t.Fatalf("expected no targets, got %v, %v", names, err)
+	}
+	if _, err := federation.Targets(request(map[string]interface{}{"instances": "all"})); err == nil || !strings.Contains(err.Error(), "no named Zabbix instances") {
+		t.Fatalf("expected an error without instances, got %v", err)
+	}
+
+	s := zabbixtest.NewServer(t)
+	setInstances(t, map[string]*zabbixtest.Server{"eu": s, "us": s, "apac": s})
+
+	for _, tc := range []struct {
+		args map[string]interface{}
+		want string
+		err  string
+	}{
+		{args: map[string]interface{}{"instances": "all"}, want: "eu,us,apac"},
+		{args: map[string]interface{}{"instances": " us, eu,us "}, want: "us,eu"},
+		{args: map[string]interface{}{"instances": "eu,mars"}, err: `unknown Zabbix instance "mars"`},
+		{args: map[string]interface{}{"instances": "eu", "instance": "us"}, err: "not both"},
+	} {
+		names, err := federation.Targets(request(tc.args))
+		switch {
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Errorf("%v: expected error containing %q, got %v", tc.args, tc.err, err)
+		case tc.err == "" && err != nil:
+			t.Errorf("%v: unexpected error: %v", tc.args, err)
+		case tc.err == "" && strings.Join(names, ",") != tc.want:
+			t.Errorf("%v: expected %s, got %v", tc.args, tc.want, names)
+		}
+	}
+}
+
+func TestQueryReportsFailedInstances(t *testing.T) {
+	eu := zabbixtest.NewServer(t)
+	us := zabbixtest.NewServer(t)
+	apac := zabbixtest.NewServer(t)
+	eu.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": "1"})
+	us.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": "1"})
+	us.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": "2"})
+	apac.Fail("problem.get", zabbixtest.Error{Code: zabbixtest.CodeInternal, Message: "Application error.", Data: "Database unavailable"}, 0)
+	names := setInstances(t, map[string]*zabbixtest.Server{"eu": eu, "us": us, "apac": apac})
+
+	ctx := zabbixtest.NewServer(t).Context(t)
+	merged := federation.Query(ctx, names, "problem.get", map[string]interface{}{"output": "extend"}, tagRecord, zabbixtest.Logger())
+
+	result := federation.ToolResult(merged)
+	if result.IsError {
+		t.Fatalf("expected a partial result, got: %s", zabbixtest.ResultText(result))
+	}
+	zabbixtest.AssertJSON(t, []byte(zabbixtest.ResultText(result)), `{
+		"results": [
+			{"eventid":"1","instance":"eu"},
+			{"eventid":"1","instance":"us"},
+			{"eventid":"2","instance":"us"}
+		],
+		"failures": [
+			{"instance":"apac","error":"Zabbix API error -32500: Application error. - Database unavailable"}
+		]
+	}`)
+}
+
+func TestTruncateCapsMergedResults(t *testing.T) {
+	eu := zabbixtest.NewServer(t)
+	us := zabbixtest.NewServer(t)
+	for _, id := range []string{"1", "2"} {
+		eu.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": id})
+		us.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": id})
+	}
+	names := setInstances(t, map[string]*zabbixtest.Server{"eu": eu, "us": us})
+
+	ctx := zabbixtest.NewServer(t).Context(t)
+	merged := federation.Query(ctx, names, "problem.get", map[string]interface{}{"limit": 2}, tagRecord, zabbixtest.Logger())
+	merged.Truncate(3)
+	if len(merged.Results) != 3 {
+		t.Fatalf("expected the limit to cap the merged records, got %+v", merged.Results)
+	}
+	merged.Truncate(0)
+	if len(merged.Results) != 3 {
+		t.Errorf("expected a zero limit to keep every record, got %+v", merged.Results)
+	}
+}
+
+func TestQueryFailsWhenEveryInstanceFails(t *testing.T) {
+	eu := zabbixtest.NewServer(t)
+	eu.RevokeToken(zabbixtest.Token)
+	names := setInstances(t, map[string]*zabbixtest.Server{"eu": eu})
+
+	ctx := zabbixtest.NewServer(t).Context(t)
+	result := federation.ToolResult(federation.Query(ctx, names, "problem.get", map[string]interface{}{}, tagRecord, zabbixtest.Logger()))
+	if text := zabbixtest.ResultText(result); !result.IsError || !strings.Contains(text, "All Zabbix instances failed: eu:") {
+		t.Fatalf("expected an error naming eu, got: %s", text)
+	}
+}
+
+func TestQueryBoundsConcurrency(t *testing.T) {
+	zabbixtest.UseConnection(t, func(c *client.ConnectionConfig) { c.FederationConcurrency = 2 })
+
+	var running, peak atomic.Int32
+	slow := func(req zabbixtest.Request) (interface{}, *zabbixtest.Error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		return []interface{}{}, nil
+	}
+	servers := map[string]*zabbixtest.Server{}
+	for _, name := range []string{"eu", "us", "apac"} {
+		servers[name] = zabbixtest.NewServer(t)
+		servers[name].Handle("host.get", slow)
+	}
+	names := setInstances(t, servers)
+
+	ctx := zabbixtest.NewServer(t).Context(t)
+	merged := federation.Query(ctx, names, "host.get", map[string]interface{}{}, tagRecord, zabbixtest.Logger())
+	if len(merged.Failures) != 0 {
+		t.Fatalf("unexpected failures: %+v", merged.Failures)
+	}
+	if got := peak.Load(); got != 2 {
+		t.Errorf("expected at most 2 concurrent calls, got %d", got)
+	}
+}
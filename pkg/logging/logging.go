@@ -0,0 +1,155 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package logging correlates log entries with the request and MCP session
+// that produced them and keeps secrets out of the log.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// RequestIDHeader carries the correlation ID of an HTTP request. A valid
+// ID sent by the caller is kept, otherwise one is generated; either way it
+// is echoed in the response.
+const RequestIDHeader = "X-Request-ID"
+
+// Log entry fields added by FromContext
+const (
+	FieldRequestID = "request_id"
+	FieldSessionID = "session_id"
+	FieldTool      = "tool"
+)
+
+// validRequestID accepts the IDs of common proxies and tracing systems
+// without letting callers inject arbitrary text into the log
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type contextKey string
+
+const (
+	requestIDKey contextKey = "request_id"
+	toolKey      contextKey = "tool"
+)
+
+// NewRequestID returns a random correlation ID
+func NewRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithRequestID returns a context carrying the correlation ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestIDFromContext returns the correlation ID of ctx, if any
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// Middleware assigns every HTTP request a correlation ID
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = NewRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+	})
+}
+
+// ToolMiddleware tags the context of every tool call with the tool name and
+// a correlation ID, generating one for stdio calls, and logs the redacted
+// arguments at debug level
+func ToolMiddleware(logger *log.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if RequestIDFromContext(ctx) == "" {
+				ctx = WithRequestID(ctx, NewRequestID())
+			}
+			ctx = context.WithValue(ctx, toolKey, request.Params.Name)
+
+			callLogger := FromContext(ctx, logger)
+			if logger.IsLevelEnabled(log.DebugLevel) {
+				callLogger.WithField("arguments", RedactArguments(request.Params.Name, request.GetArguments())).Debug("Calling tool")
+			}
+
+			start := time.Now()
+			result, err := next(ctx, request)
+
+			entry := callLogger.WithField("duration", time.Since(start))
+			switch {
+			case err != nil:
+				entry.WithError(err).Debug("Tool call failed")
+			case result != nil && result.IsError:
+				entry.Debug("Tool call returned an error result")
+			default:
+				entry.Debug("Tool call completed")
+			}
+			return result, err
+		}
+	}
+}
+
+// FromContext returns a logger whose entries carry the request ID, MCP
+// session and tool of ctx. It writes to the same output, with the same
+// formatter, level and hooks, as logger.
+func FromContext(ctx context.Context, logger *log.Logger) *log.Logger {
+	fields := log.Fields{}
+	if id := RequestIDFromContext(ctx); id != "" {
+		fields[FieldRequestID] = id
+	}
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		fields[FieldSessionID] = session.SessionID()
+	}
+	if tool, ok := ctx.Value(toolKey).(string); ok {
+		fields[FieldTool] = tool
+	}
+	if len(fields) == 0 {
+		return logger
+	}
+
+	hooks := make(log.LevelHooks)
+	for level, levelHooks := range logger.Hooks {
+		hooks[level] = append([]log.Hook(nil), levelHooks...)
+	}
+	hooks.Add(fieldsHook(fields))
+
+	return &log.Logger{
+		Out:          logger.Out,
+		Hooks:        hooks,
+		Formatter:    logger.Formatter,
+		ReportCaller: logger.ReportCaller,
+		Level:        logger.GetLevel(),
+		ExitFunc:     logger.ExitFunc,
+	}
+}
+
+// fieldsHook adds fields to every entry that does not set them itself
+type fieldsHook log.Fields
+
+func (h fieldsHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h fieldsHook) Fire(entry *log.Entry) error {
+	for key, value := range h {
+		if _, ok := entry.Data[key]; !ok {
+			entry.Data[key] = value
+		}
+	}
+	return nil
+}
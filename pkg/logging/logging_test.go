@@ -0,0 +1,236 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+// jsonLogger returns a debug logger that writes JSON lines to buf, with
+// redaction as configured by the server
+func jsonLogger(buf *bytes.Buffer) *log.Logger {
+	logger := log.New()
+	logger.SetOutput(buf)
+	logger.SetFormatter(&log.JSONFormatter{})
+	logger.SetLevel(log.DebugLevel)
+	logger.AddHook(logging.RedactHook{})
+	return logger
+}
+
+// entries decodes the JSON log lines in buf
+func entries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var result []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		entry := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+func TestRedactHostSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	logger := jsonLogger(&buf)
+
+	params := client.HostCreateParams{
+		Host:         "web-01",
+		TlsPsk:       "1f87b595725ac58dd977beef14b97461",
+		IpmiPassword: "ipmi-secret",
+		Interfaces: []client.HostInterface{{
+			Type: "2",
+			Details: client.HostInterfaceDetails{
+				Version:        3,
+				SecurityName:   "monitor",
+				AuthPassphrase: "auth-secret",
+				PrivPassphrase: "priv-secret",
+			},
+		}},
+		Macros: []client.Macro{
+			{Macro: "{$DB.PASSWORD}", Value: "macro-secret", Type: "1"},
+			{Macro: "{$DB.USER}", Value: "zabbix", Type: "0"},
+		},
+	}
+	logger.WithFields(log.Fields{
+		"params": params,
+		"token":  "api-token",
+	}).Debug("Making Zabbix API call")
+
+	out := buf.String()
+	for _, secret := range []string{"1f87b595725ac58dd977beef14b97461", "ipmi-secret", "auth-secret", "priv-secret", "macro-secret", "api-token"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("secret %q was logged: %s", secret, out)
+		}
+	}
+	for _, kept := range []string{"web-01", "monitor", "{$DB.PASSWORD}", "zabbix", logging.Redacted} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("expected %q in the log: %s", kept, out)
+		}
+	}
+
+	// Values passed to the API are not modified
+	if params.TlsPsk != "1f87b595725ac58dd977beef14b97461" || params.Macros[0].Value != "macro-secret" {
+		t.Errorf("redaction modified the logged value: %+v", params)
+	}
+}
+
+func TestRedactToolArguments(t *testing.T) {
+	args := map[string]interface{}{
+		"hostid": "10084",
+		"macro":  "{$SNMP_COMMUNITY}",
+		"value":  "public-secret",
+		"type":   float64(1),
+	}
+	redacted := logging.Redact(args).(map[string]interface{})
+	if redacted["value"] != logging.Redacted || redacted["hostid"] != "10084" {
+		t.Errorf("unexpected redaction: %v", redacted)
+	}
+	if args["value"] != "public-secret" {
+		t.Error("redaction modified the arguments")
+	}
+
+	args["type"] = "0"
+	if redacted := logging.Redact(args).(map[string]interface{}); redacted["value"] != "public-secret" {
+		t.Errorf("text macro values must be kept, got %v", redacted)
+	}
+}
+
+func TestRedactValueOnlyMacroUpdates(t *testing.T) {
+	var buf bytes.Buffer
+	logger := jsonLogger(&buf)
+	s := zabbixtest.NewServer(t)
+	session := zabbixtest.NewSession()
+	s.Handle("usermacro.update", func(zabbixtest.Request) (interface{}, *zabbixtest.Error) {
+		return map[string]interface{}{"hostmacroids": []string{"30"}}, nil
+	})
+	ctx := s.SessionContext(t, session)
+
+	// The update changes a secret macro without naming its type
+	tool := logging.ToolMiddleware(logger)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zabbix, err := client.GetZabbixClientFromContext(ctx, logger)
+		if err != nil {
+			return nil, err
+		}
+		zabbix.Logger = logger
+		if _, err := zabbix.CallContext(ctx, "usermacro.update", request.GetArguments()); err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "update_user_macro"
+	request.Params.Arguments = map[string]interface{}{"hostmacroid": "30", "value": "rotated-secret"}
+	if _, err := tool(ctx, request); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "rotated-secret") {
+		t.Errorf("the macro value was logged: %s", buf.String())
+	}
+
+	for _, tool := range []string{"update_user_macro", "update_global_macro"} {
+		redacted := logging.RedactArguments(tool, map[string]interface{}{"globalmacroid": "2", "value": "rotated-secret"})
+		if redacted["value"] != logging.Redacted || redacted["globalmacroid"] != "2" {
+			t.Errorf("%s: unexpected redaction: %v", tool, redacted)
+		}
+	}
+	if redacted := logging.RedactArguments("update_global_macro", map[string]interface{}{"globalmacroid": "2", "value": "public", "type": float64(0)}); redacted["value"] != "public" {
+		t.Errorf("text macro values must be kept, got %v", redacted)
+	}
+	if redacted := logging.RedactArguments("update_item", map[string]interface{}{"itemid": "1", "value": "kept"}); redacted["value"] != "kept" {
+		t.Errorf("only macro tools redact value, got %v", redacted)
+	}
+}
+
+func TestMiddlewareRequestID(t *testing.T) {
+	var seen string
+	handler := logging.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logging.RequestIDFromContext(r.Context())
+	}))
+
+	for _, test := range []struct {
+		header string
+		keep   bool
+	}{
+		{"", false},
+		{"req-42.abc", true},
+		{"bad id\nwith newline", false},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if test.header != "" {
+			req.Header.Set(logging.RequestIDHeader, test.header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if seen == "" || rec.Header().Get(logging.RequestIDHeader) != seen {
+			t.Errorf("%q: expected the request ID %q to be echoed, got %q", test.header, seen, rec.Header().Get(logging.RequestIDHeader))
+		}
+		if (seen == test.header) != test.keep {
+			t.Errorf("%q: unexpected request ID %q", test.header, seen)
+		}
+	}
+}
+
+func TestToolMiddlewareCorrelatesEntries(t *testing.T) {
+	var buf bytes.Buffer
+	logger := jsonLogger(&buf)
+	s := zabbixtest.NewServer(t)
+	session := zabbixtest.NewSession()
+	s.Handle("host.update", func(zabbixtest.Request) (interface{}, *zabbixtest.Error) {
+		return map[string]interface{}{"hostids": []string{"10084"}}, nil
+	})
+	ctx := logging.WithRequestID(s.SessionContext(t, session), "req-1")
+
+	tool := logging.ToolMiddleware(logger)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		toolLogger := logging.FromContext(ctx, logger)
+		zabbix, err := client.GetZabbixClientFromContext(ctx, toolLogger)
+		if err != nil {
+			return nil, err
+		}
+		// The session's client logs to the server logger
+		zabbix.Logger = logger
+		if _, err := zabbix.CallContext(ctx, "host.update", map[string]interface{}{"hostid": "10084", "tls_psk": "zabbix-psk"}); err != nil {
+			return nil, err
+		}
+		toolLogger.Info("Updated host")
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_hosts"
+	request.Params.Arguments = map[string]interface{}{"search": "web"}
+	if _, err := tool(ctx, request); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buf.String(), "zabbix-psk") {
+		t.Errorf("PSK was logged: %s", buf.String())
+	}
+	messages := map[string]bool{}
+	for _, entry := range entries(t, &buf) {
+		messages[entry["msg"].(string)] = true
+		if entry[logging.FieldRequestID] != "req-1" || entry[logging.FieldSessionID] != session.ID || entry[logging.FieldTool] != "get_hosts" {
+			t.Errorf("entry is not correlated with the call: %v", entry)
+		}
+	}
+	for _, want := range []string{"Calling tool", "Making Zabbix API call", "Updated host", "Tool call completed"} {
+		if !messages[want] {
+			t.Errorf("expected the entry %q, got %v", want, messages)
+		}
+	}
+}
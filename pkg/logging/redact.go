@@ -0,0 +1,193 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Redacted replaces secret values in the log
+const Redacted = "[REDACTED]"
+
+// secretKeys are the field names whose values are never logged: Zabbix
+// credentials, host PSKs, IPMI passwords and SNMP secrets, compared case
+// insensitively
+var secretKeys = map[string]bool{
+	"password":          true,
+	"passwd":            true,
+	"current_passwd":    true,
+	"token":             true,
+	"auth":              true,
+	"sessionid":         true,
+	"authorization":     true,
+	"x-api-key":         true,
+	"x-zabbix-token":    true,
+	"x-zabbix-password": true,
+	"zabbix_token":      true,
+	"zabbix_password":   true,
+	"tls_psk":           true,
+	"ipmi_password":     true,
+	"community":         true,
+	"authpassphrase":    true,
+	"privpassphrase":    true,
+	"secret":            true,
+	"hmac_secret":       true,
+}
+
+// secretMacroType is the user macro type whose value is secret
+const secretMacroType = "1"
+
+// textMacroType is the user macro type whose value is plain text
+const textMacroType = "0"
+
+// macroTools are the tools whose value argument is a user macro value
+var macroTools = map[string]bool{
+	"create_user_macro":   true,
+	"update_user_macro":   true,
+	"create_global_macro": true,
+	"update_global_macro": true,
+}
+
+// IsSecretKey reports whether values of the field name are redacted
+func IsSecretKey(name string) bool {
+	return secretKeys[strings.ToLower(name)]
+}
+
+// Redact returns a copy of v with secret values replaced: fields named in
+// the secret list and the values of secret user macros. Structs are
+// redacted through their JSON form, as sent to Zabbix.
+func Redact(v interface{}) interface{} {
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		return redactMap(value)
+	case []interface{}:
+		redacted := make([]interface{}, len(value))
+		for i, item := range value {
+			redacted[i] = Redact(item)
+		}
+		return redacted
+	case string, bool, float64, int, int64, json.Number:
+		return value
+	case json.RawMessage:
+		return redactJSON(value)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%T", v)
+	}
+	return redactJSON(data)
+}
+
+// redactJSON decodes a JSON document and redacts it
+func redactJSON(data []byte) interface{} {
+	var decoded interface{}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return string(data)
+	}
+	return Redact(decoded)
+}
+
+func redactMap(m map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		if IsSecretKey(key) {
+			redacted[key] = Redacted
+			continue
+		}
+		redacted[key] = Redact(value)
+	}
+	if _, isMacro := m["macro"]; isMacro && fmt.Sprint(m["type"]) == secretMacroType {
+		if _, ok := m["value"]; ok {
+			redacted["value"] = Redacted
+		}
+	}
+	return redacted
+}
+
+// RedactArguments returns a copy of the arguments of a tool call with
+// secrets replaced. Macro tools keep their value only when the call marks
+// the macro as text, as an update may change a secret macro without naming
+// its type.
+func RedactArguments(tool string, args map[string]interface{}) map[string]interface{} {
+	redacted := redactMap(args)
+	if macroTools[tool] {
+		redactMacroValues(redacted)
+	}
+	return redacted
+}
+
+// RedactParams returns a copy of the parameters of a Zabbix API call with
+// secrets replaced. The values of usermacro methods are kept only for
+// macros marked as text.
+func RedactParams(method string, params interface{}) interface{} {
+	redacted := Redact(params)
+	if strings.HasPrefix(method, "usermacro.") {
+		redactMacroValues(redacted)
+	}
+	return redacted
+}
+
+// redactMacroValues replaces, in place, the value of every macro object in
+// v whose type is not text
+func redactMacroValues(v interface{}) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		if _, ok := value["value"]; ok && fmt.Sprint(value["type"]) != textMacroType {
+			value["value"] = Redacted
+		}
+	case []interface{}:
+		for _, item := range value {
+			redactMacroValues(item)
+		}
+	}
+}
+
+// RedactHook redacts secret fields and secrets nested in map, slice and
+// struct fields of every log entry, e.g. Zabbix request parameters logged
+// at debug level
+type RedactHook struct{}
+
+func (RedactHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (RedactHook) Fire(entry *log.Entry) error {
+	for key, value := range entry.Data {
+		if IsSecretKey(key) {
+			entry.Data[key] = Redacted
+			continue
+		}
+		if needsRedaction(value) {
+			entry.Data[key] = Redact(value)
+		}
+	}
+	return nil
+}
+
+// needsRedaction reports whether value may nest secrets. Errors, times and
+// other values with their own string form are logged as they are.
+func needsRedaction(value interface{}) bool {
+	switch value.(type) {
+	case nil, error, fmt.Stringer, time.Time:
+		return false
+	case json.RawMessage:
+		return true
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Pointer:
+		return true
+	}
+	return false
+}
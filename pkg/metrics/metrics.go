@@ -0,0 +1,134 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package metrics exposes the server's Prometheus metrics: MCP tool calls,
+// Zabbix API calls, sessions, rate limiting and build information.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/vfcastr/Zabbix-MCP/version"
+)
+
+// Path is where the metrics are served in HTTP mode
+const Path = "/metrics"
+
+const namespace = "zabbix_mcp"
+
+// Call outcomes used as the status label
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// DefaultInstance labels Zabbix calls made without a named instance
+const DefaultInstance = "default"
+
+// Registry holds every metric of the server, plus the Go runtime and process
+// collectors
+var Registry = prometheus.NewRegistry()
+
+var (
+	toolCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tool_calls_total",
+		Help:      "MCP tool calls by tool and status.",
+	}, []string{"tool", "status"})
+
+	toolDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "tool_call_duration_seconds",
+		Help:      "MCP tool call latency by tool.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"tool"})
+
+	zabbixDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "zabbix_request_duration_seconds",
+		Help:      "Zabbix API call latency, including retries, by method, instance and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "instance", "status"})
+
+	rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "HTTP requests rejected by the rate limiter, by exhausted bucket.",
+	}, []string{"scope"})
+
+	buildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "build_info",
+		Help:      "Build information of the running server; the value is always 1.",
+	}, []string{"version", "git_commit", "build_date", "go_version"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		toolCalls, toolDuration, zabbixDuration, rateLimited, buildInfo,
+	)
+
+	info := version.GetVersionInfo()
+	buildInfo.WithLabelValues(info["version"], info["git_commit"], info["build_date"], info["go_version"]).Set(1)
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// RegisterGauge exposes a value computed at scrape time, such as the number
+// of active sessions
+func RegisterGauge(name, help string, value func() float64) {
+	Registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      name,
+		Help:      help,
+	}, value))
+}
+
+// ObserveZabbixCall records a Zabbix API call. An empty instance is the
+// session's default connection.
+func ObserveZabbixCall(method, instance string, err error, duration time.Duration) {
+	if instance == "" {
+		instance = DefaultInstance
+	}
+	zabbixDuration.WithLabelValues(method, instance, status(err == nil)).Observe(duration.Seconds())
+}
+
+// RateLimited records a request rejected because the scope's bucket, global,
+// session or ip, was empty
+func RateLimited(scope string) {
+	rateLimited.WithLabelValues(scope).Inc()
+}
+
+// ToolMiddleware records the count, status and latency of every tool call.
+// Calls that return an error result count as errors.
+func ToolMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, request)
+
+		tool := request.Params.Name
+		toolDuration.WithLabelValues(tool).Observe(time.Since(start).Seconds())
+		toolCalls.WithLabelValues(tool, status(err == nil && (result == nil || !result.IsError))).Inc()
+
+		return result, err
+	}
+}
+
+func status(ok bool) string {
+	if ok {
+		return StatusOK
+	}
+	return StatusError
+}
@@ -0,0 +1,108 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vfcastr/Zabbix-MCP/pkg/metrics"
+)
+
+// sample returns the counter value, or the histogram sample count, of the
+// metric with exactly these labels, and zero when it was never recorded
+func sample(t *testing.T, name string, labels map[string]string) float64 {
+	t.Helper()
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	next:
+		for _, m := range family.GetMetric() {
+			if len(m.GetLabel()) != len(labels) {
+				continue
+			}
+			for _, label := range m.GetLabel() {
+				if labels[label.GetName()] != label.GetValue() {
+					continue next
+				}
+			}
+			if m.GetHistogram() != nil {
+				return float64(m.GetHistogram().GetSampleCount())
+			}
+			return m.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestToolMiddleware(t *testing.T) {
+	cases := []struct {
+		name       string
+		result     *mcp.CallToolResult
+		err        error
+		wantStatus string
+	}{
+		{name: "success", result: mcp.NewToolResultText("ok"), wantStatus: metrics.StatusOK},
+		{name: "error result", result: mcp.NewToolResultError("host not found"), wantStatus: metrics.StatusError},
+		{name: "go error", err: errors.New("connection refused"), wantStatus: metrics.StatusError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tool := "test_" + tc.name
+			handler := metrics.ToolMiddleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return tc.result, tc.err
+			})
+
+			var request mcp.CallToolRequest
+			request.Params.Name = tool
+			result, err := handler(context.Background(), request)
+			if result != tc.result || err != tc.err {
+				t.Fatalf("expected the handler's result to pass through, got %v, %v", result, err)
+			}
+
+			if got := sample(t, "zabbix_mcp_tool_calls_total", map[string]string{"tool": tool, "status": tc.wantStatus}); got != 1 {
+				t.Errorf("expected one %s call, got %v", tc.wantStatus, got)
+			}
+			if got := sample(t, "zabbix_mcp_tool_call_duration_seconds", map[string]string{"tool": tool}); got != 1 {
+				t.Errorf("expected one latency sample, got %v", got)
+			}
+		})
+	}
+}
+
+func TestObserveZabbixCall(t *testing.T) {
+	metrics.ObserveZabbixCall("test.get", "", nil, time.Millisecond)
+	metrics.ObserveZabbixCall("test.get", "eu", errors.New("timeout"), time.Millisecond)
+
+	if got := sample(t, "zabbix_mcp_zabbix_request_duration_seconds", map[string]string{"method": "test.get", "instance": metrics.DefaultInstance, "status": metrics.StatusOK}); got != 1 {
+		t.Errorf("expected the call without an instance labelled %q, got %v samples", metrics.DefaultInstance, got)
+	}
+	if got := sample(t, "zabbix_mcp_zabbix_request_duration_seconds", map[string]string{"method": "test.get", "instance": "eu", "status": metrics.StatusError}); got != 1 {
+		t.Errorf("expected one failed call on eu, got %v samples", got)
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	before := sample(t, "zabbix_mcp_rate_limited_requests_total", map[string]string{"scope": "session"})
+	global := sample(t, "zabbix_mcp_rate_limited_requests_total", map[string]string{"scope": "global"})
+
+	metrics.RateLimited("session")
+	metrics.RateLimited("session")
+
+	if got := sample(t, "zabbix_mcp_rate_limited_requests_total", map[string]string{"scope": "session"}); got != before+2 {
+		t.Errorf("expected the session counter at %v, got %v", before+2, got)
+	}
+	if got := sample(t, "zabbix_mcp_rate_limited_requests_total", map[string]string{"scope": "global"}); got != global {
+		t.Errorf("expected the global counter unchanged at %v, got %v", global, got)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package alerts_test
+
+import (
+	"testing"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/alerts"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func TestGetAlerts(t *testing.T) {
+	zabbixtest.RunToolCases(t, alerts.GetAlerts(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "alert.get",
+			Params: `{"output":"extend","sortfield":["clock","alertid"],"sortorder":["DESC","DESC"],"limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"eventids": "501", "mediatypeids": "1,4", "userids": "5", "time_till": float64(1700003600), "limit": float64(20)},
+			Method: "alert.get",
+			Params: `{"output":"extend","eventids":["501"],"mediatypeids":["1","4"],"userids":["5"],"time_till":1700003600,"sortfield":["clock","alertid"],"sortorder":["DESC","DESC"],"limit":20}`,
+		},
+		{
+			Name: "returns model alerts",
+			Setup: func(t *testing.T, s *zabbixtest.Server) {
+				s.Model.Add("alert", zabbixtest.Object{"alertid": "900", "eventid": "501", "subject": "Problem: High CPU", "status": "1"})
+			},
+			Args:     map[string]interface{}{"eventids": "501"},
+			WantText: "Problem: High CPU",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("alert.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Failed to get alerts",
+		},
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -54,7 +55,15 @@ type AlertGetParams struct {
 func GetAlerts(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_alerts",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Alerts",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Retrieve alerts that have been generated by actions."),
 			mcp.WithString("alertids", mcp.Description("Comma-separated list of alert IDs to filter by")),
 			mcp.WithString("actionids", mcp.Description("Comma-separated list of action IDs to filter by")),
@@ -68,7 +77,7 @@ func GetAlerts(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("limit", mcp.Description("Max alerts to return (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getAlertsHandler(ctx, req, logger)
+			return getAlertsHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -119,7 +128,7 @@ func getAlertsHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.
 		}
 	}
 
-	result, err := zabbix.Call("alert.get", params)
+	result, err := zabbix.CallContext(ctx, "alert.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get alerts: %v", err)), nil
 	}
@@ -0,0 +1,48 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package auditlog_test
+
+import (
+	"testing"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/auditlog"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func TestGetAuditLog(t *testing.T) {
+	zabbixtest.RunToolCases(t, auditlog.GetAuditLog(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "auditlog.get",
+			Params: `{"output":"extend","sortfield":"clock","sortorder":"DESC","limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"userids": "1", "actions": "0,2", "resourcetypes": "4", "time_from": float64(1700000000), "limit": float64(5000)},
+			Method: "auditlog.get",
+			Params: `{"output":"extend","userids":["1"],"filter":{"action":[0,2],"resourcetype":[4]},"time_from":1700000000,"sortfield":"clock","sortorder":"DESC","limit":1000}`,
+		},
+		{
+			Name:   "resource type only",
+			Args:   map[string]interface{}{"resourcetypes": "4"},
+			Method: "auditlog.get",
+			Params: `{"output":"extend","filter":{"resourcetype":[4]},"sortfield":"clock","sortorder":"DESC","limit":100}`,
+		},
+		{
+			Name: "returns model entries",
+			Setup: func(t *testing.T, s *zabbixtest.Server) {
+				s.Model.Add("auditlog", zabbixtest.Object{"auditid": "a1", "userid": "1", "username": "Admin", "action": "2", "resourcename": "web01"})
+			},
+			Args:     map[string]interface{}{"actions": "2"},
+			WantText: "web01",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("auditlog.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Failed to get audit log",
+		},
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -46,7 +47,15 @@ type AuditLogGetParams struct {
 func GetAuditLog(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_audit_log",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Audit Log",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Retrieve audit log entries from Zabbix. Useful for tracking user actions, configuration changes, and system events."),
 			mcp.WithString("auditids", mcp.Description("Comma-separated list of audit log entry IDs")),
 			mcp.WithString("userids", mcp.Description("Comma-separated list of user IDs to filter by")),
@@ -57,7 +66,7 @@ func GetAuditLog(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("limit", mcp.Description("Max entries to return (default: 100, max: 1000)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getAuditLogHandler(ctx, req, logger)
+			return getAuditLogHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -123,7 +132,7 @@ func getAuditLogHandler(ctx context.Context, req mcp.CallToolRequest, logger *lo
 		}
 	}
 
-	result, err := zabbix.Call("auditlog.get", params)
+	result, err := zabbix.CallContext(ctx, "auditlog.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get audit log: %v", err)), nil
 	}
@@ -0,0 +1,67 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package docs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The handler reads the documentation bundled into the container image, so
+// these tests exercise the parsing helpers on an inline document instead.
+var testDoc = strings.Split(`# Zabbix API
+## Host
+### host.create
+Creates new hosts.
+#### Parameters
+groups (array) required
+### host.get
+Returns hosts.
+## Template
+### template.create
+Creates new templates.`, "\n")
+
+func TestExtractSection(t *testing.T) {
+	got := extractSection(testDoc, "host")
+	want := "## Host\n### host.create\nCreates new hosts.\n#### Parameters\ngroups (array) required\n### host.get\nReturns hosts.\n"
+	if got != want {
+		t.Errorf("extractSection(host) = %q, want %q", got, want)
+	}
+
+	if got := extractSection(testDoc, "template.create"); got != "### template.create\nCreates new templates.\n" {
+		t.Errorf("extractSection(template.create) = %q", got)
+	}
+
+	if got := extractSection(testDoc, "proxy"); got != "" {
+		t.Errorf("extractSection(proxy) = %q, want empty", got)
+	}
+}
+
+func TestSearchInDoc(t *testing.T) {
+	results := searchInDoc(testDoc, "CREATES", 1)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 matches, got %d: %v", len(results), results)
+	}
+	want := "**Match at line 4:**\n```\n### host.create\n>>> Creates new hosts. <<<\n#### Parameters\n```\n"
+	if results[0] != want {
+		t.Errorf("first match = %q, want %q", results[0], want)
+	}
+	if !strings.HasSuffix(results[1], ">>> Creates new templates. <<<\n```\n") {
+		t.Errorf("context must stop at the end of the document: %q", results[1])
+	}
+
+	many := strings.Split(strings.Repeat("match\n", 20), "\n")
+	if got := len(searchInDoc(many, "match", 0)); got != 10 {
+		t.Errorf("expected results to be capped at 10, got %d", got)
+	}
+}
+
+func TestExtractTableOfContents(t *testing.T) {
+	got := extractTableOfContents(testDoc)
+	want := []string{"# Zabbix API", "## Host", "### host.create", "### host.get", "## Template", "### template.create"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractTableOfContents() = %v, want %v", got, want)
+	}
+}
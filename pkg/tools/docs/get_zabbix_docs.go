@@ -13,6 +13,7 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -22,14 +23,22 @@ const maxResultLength = 50000
 func GetZabbixDocs(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_zabbix_docs",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Zabbix Docs",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Search and retrieve Zabbix API documentation. Use this tool to look up API methods, parameters, and examples from the official Zabbix documentation."),
 			mcp.WithString("search", mcp.Description("Search term to find in the documentation (e.g., 'host.create', 'trigger', 'template'). If not provided, returns the table of contents.")),
 			mcp.WithString("section", mcp.Description("Specific section to retrieve (e.g., 'Host', 'Template', 'Trigger', 'Item'). Use this to get the full documentation for a specific API.")),
 			mcp.WithNumber("context_lines", mcp.Description("Number of lines before and after each match to include (default: 20)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getZabbixDocsHandler(ctx, req, logger)
+			return getZabbixDocsHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
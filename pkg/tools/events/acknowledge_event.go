@@ -13,6 +13,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type EventAcknowledgeParams struct {
@@ -27,6 +29,15 @@ type EventAcknowledgeParams struct {
 func AcknowledgeEvent(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("acknowledge_event",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Acknowledge Event",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Acknowledge events or update them (add message, change severity, close, suppress, etc.)."),
 			mcp.WithString("eventids", mcp.Description("Comma-separated list of event IDs to acknowledge"), mcp.Required()),
 			mcp.WithNumber("action", mcp.Description("Action bitmask: 1=close, 2=acknowledge, 4=add message, 8=change severity, 16=unacknowledge, 32=suppress, 64=unsuppress, 128=change rank, 256=change symptoms to cause")),
@@ -36,7 +47,7 @@ func AcknowledgeEvent(logger *log.Logger) server.ServerTool {
 			mcp.WithString("cause_eventid", mcp.Description("Cause event ID when changing symptom to cause")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return acknowledgeEventHandler(ctx, req, logger)
+			return acknowledgeEventHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -79,7 +90,7 @@ func acknowledgeEventHandler(ctx context.Context, req mcp.CallToolRequest, logge
 		params.CauseEventID = v
 	}
 
-	result, err := zabbix.Call("event.acknowledge", params)
+	result, err := zabbix.CallContext(ctx, "event.acknowledge", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to acknowledge events: %v", err)), nil
 	}
@@ -0,0 +1,84 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package events_test
+
+import (
+	"testing"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/events"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addEvent(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add(zabbixtest.Events, zabbixtest.Object{
+		"eventid":  "501",
+		"objectid": "16001",
+		"name":     "High CPU on web01",
+		"severity": "4",
+		"hosts":    []interface{}{map[string]interface{}{"hostid": "10084"}},
+	})
+}
+
+func TestGetEvents(t *testing.T) {
+	zabbixtest.RunToolCases(t, events.GetEvents(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "event.get",
+			Params: `{"output":"extend","selectTags":"extend","sortfield":["clock","eventid"],"sortorder":["DESC","DESC"],"limit":100}`,
+		},
+		{
+			Name: "filters",
+			Args: map[string]interface{}{
+				"hostids": "10084", "objectids": "16001", "source": float64(0), "object": float64(0),
+				"acknowledged": false, "severities": "4, 5, 9", "time_from": float64(1700000000), "limit": float64(10),
+			},
+			Method: "event.get",
+			Params: `{"output":"extend","hostids":["10084"],"objectids":["16001"],"source":0,"object":0,"acknowledged":false,"severities":[4,5],"time_from":1700000000,"selectTags":"extend","sortfield":["clock","eventid"],"sortorder":["DESC","DESC"],"limit":10}`,
+		},
+		{
+			Name:     "returns model events",
+			Setup:    addEvent,
+			Args:     map[string]interface{}{"hostids": "10084"},
+			WantText: "High CPU on web01",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("event.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Failed to get events",
+		},
+	})
+}
+
+func TestAcknowledgeEvent(t *testing.T) {
+	zabbixtest.RunToolCases(t, events.AcknowledgeEvent(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing eventids",
+			Args:      map[string]interface{}{},
+			WantError: "eventids is required",
+		},
+		{
+			Name:     "acknowledge by default",
+			Setup:    addEvent,
+			Args:     map[string]interface{}{"eventids": "501"},
+			Method:   "event.acknowledge",
+			Params:   `{"eventids":["501"],"action":2}`,
+			WantText: "Events acknowledged",
+		},
+		{
+			Name:   "message and severity",
+			Setup:  addEvent,
+			Args:   map[string]interface{}{"eventids": "501, 502", "action": float64(12), "message": "on it", "severity": float64(0), "suppress_until": float64(1700003600)},
+			Method: "event.acknowledge",
+			Params: `{"eventids":["501","502"],"action":12,"message":"on it","severity":0,"suppress_until":1700003600}`,
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("event.acknowledge"),
+			Args:      map[string]interface{}{"eventids": "501"},
+			WantError: "Failed to acknowledge events",
+		},
+	})
+}
@@ -7,12 +7,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/federation"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -29,6 +32,9 @@ type Event struct {
 	Suppressed   string       `json:"suppressed"`
 	OpData       string       `json:"opdata"`
 	Tags         []client.Tag `json:"tags,omitempty"`
+	// Instance is the named instance the record came from in a federated
+	// query
+	Instance string `json:"instance,omitempty"`
 }
 
 type EventGetParams struct {
@@ -55,7 +61,15 @@ type EventGetParams struct {
 func GetEvents(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_events",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Events",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Retrieve events generated by triggers, network discovery and other Zabbix systems."),
 			mcp.WithString("eventids", mcp.Description("Comma-separated list of event IDs to filter by")),
 			mcp.WithString("groupids", mcp.Description("Comma-separated list of host group IDs to filter by")),
@@ -70,15 +84,15 @@ func GetEvents(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("limit", mcp.Description("Max events to return (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getEventsHandler(ctx, req, logger)
+			return getEventsHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
 
 func getEventsHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
-	zabbix, err := client.GetZabbixClientFromContext(ctx, logger)
+	targets, err := federation.Targets(req)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get Zabbix client: %v", err)), nil
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
 	params := EventGetParams{
@@ -127,7 +141,21 @@ func getEventsHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.
 		}
 	}
 
-	result, err := zabbix.Call("event.get", params)
+	if targets != nil {
+		merged := federation.Query(ctx, targets, "event.get", params, func(e *Event, instance string) { e.Instance = instance }, logger)
+		sort.SliceStable(merged.Results, func(i, j int) bool {
+			return federation.Newer(merged.Results[i].Clock, merged.Results[j].Clock)
+		})
+		merged.Truncate(params.Limit)
+		return federation.ToolResult(merged), nil
+	}
+
+	zabbix, err := client.GetZabbixClientFromContext(ctx, logger)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get Zabbix client: %v", err)), nil
+	}
+
+	result, err := zabbix.CallContext(ctx, "event.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get events: %v", err)), nil
 	}
@@ -10,12 +10,23 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // CreateHostGroup returns the tool definition and handler for creating a new Zabbix host group
 func CreateHostGroup(logger *logrus.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("zabbix_create_host_group",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create Host Group",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new host group in Zabbix."),
 			mcp.WithString("name",
 				mcp.Description("Name of the host group"),
@@ -23,6 +34,7 @@ func CreateHostGroup(logger *logrus.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			logger := logging.FromContext(ctx, logger)
 			zabbixClient, err := client.GetZabbixClientFromContext(ctx, logger)
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
@@ -44,7 +56,7 @@ func CreateHostGroup(logger *logrus.Logger) server.ServerTool {
 			}
 
 			// Make API call
-			result, err := zabbixClient.Call("hostgroup.create", params)
+			result, err := zabbixClient.CallContext(ctx, "hostgroup.create", params)
 			if err != nil {
 				logger.WithError(err).Error("Failed to create host group")
 				return mcp.NewToolResultError(err.Error()), nil
@@ -11,12 +11,23 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // DeleteHostGroup returns the tool definition and handler for deleting Zabbix host groups
 func DeleteHostGroup(logger *logrus.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("zabbix_delete_host_group",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete Host Group",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete host groups from Zabbix server."),
 			mcp.WithString("groupids",
 				mcp.Description("Comma-separated list of host group IDs to delete"),
@@ -24,6 +35,7 @@ func DeleteHostGroup(logger *logrus.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			logger := logging.FromContext(ctx, logger)
 			zabbixClient, err := client.GetZabbixClientFromContext(ctx, logger)
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
@@ -43,7 +55,7 @@ func DeleteHostGroup(logger *logrus.Logger) server.ServerTool {
 			groupIDs := strings.Split(groupIDsStr, ",")
 
 			// Make API call
-			result, err := zabbixClient.Call("hostgroup.delete", groupIDs)
+			result, err := zabbixClient.CallContext(ctx, "hostgroup.delete", groupIDs)
 			if err != nil {
 				logger.WithError(err).Error("Failed to delete host groups")
 				return mcp.NewToolResultError(err.Error()), nil
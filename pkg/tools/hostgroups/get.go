@@ -11,12 +11,23 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // GetHostGroups returns the tool definition and handler for retrieving Zabbix host groups
 func GetHostGroups(logger *logrus.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("zabbix_get_host_groups",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Host Groups",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("List host groups from Zabbix server. Can filter by group IDs, host IDs, or search term."),
 			mcp.WithString("groupids",
 				mcp.Description("Comma-separated list of host group IDs to filter by"),
@@ -32,6 +43,7 @@ func GetHostGroups(logger *logrus.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			logger := logging.FromContext(ctx, logger)
 			zabbixClient, err := client.GetZabbixClientFromContext(ctx, logger)
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
@@ -66,7 +78,7 @@ func GetHostGroups(logger *logrus.Logger) server.ServerTool {
 			}
 
 			// Make API call
-			result, err := zabbixClient.Call("hostgroup.get", params)
+			result, err := zabbixClient.CallContext(ctx, "hostgroup.get", params)
 			if err != nil {
 				logger.WithError(err).Error("Failed to get host groups")
 				return mcp.NewToolResultError(err.Error()), nil
@@ -0,0 +1,94 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package hostgroups_test
+
+import (
+	"testing"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/hostgroups"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addHostGroup(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add(zabbixtest.HostGroups, zabbixtest.Object{"groupid": "2", "name": "Linux servers"})
+}
+
+func TestGetHostGroups(t *testing.T) {
+	zabbixtest.RunToolCases(t, hostgroups.GetHostGroups(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Method: "hostgroup.get",
+			Params: `{"output":"extend","selectHosts":["hostid","name"],"limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"groupids": "2,4", "hostids": "10084", "search": "Linux", "limit": float64(10)},
+			Method: "hostgroup.get",
+			Params: `{"output":"extend","groupids":["2","4"],"hostids":["10084"],"search":{"name":"Linux"},"selectHosts":["hostid","name"],"limit":10}`,
+		},
+		{
+			Name:     "returns model groups",
+			Setup:    addHostGroup,
+			Args:     map[string]interface{}{},
+			WantText: "Linux servers",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("hostgroup.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Injected failure.",
+		},
+	})
+}
+
+func TestCreateHostGroup(t *testing.T) {
+	zabbixtest.RunToolCases(t, hostgroups.CreateHostGroup(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing name",
+			Args:      map[string]interface{}{},
+			WantError: "name is required",
+		},
+		{
+			Name:     "create",
+			Args:     map[string]interface{}{"name": "Databases"},
+			Method:   "hostgroup.create",
+			Params:   `{"name":"Databases"}`,
+			WantText: `"groupids"`,
+		},
+	})
+}
+
+func TestUpdateHostGroup(t *testing.T) {
+	zabbixtest.RunToolCases(t, hostgroups.UpdateHostGroup(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing name",
+			Args:      map[string]interface{}{"groupid": "2"},
+			WantError: "name is required",
+		},
+		{
+			Name:   "rename",
+			Setup:  addHostGroup,
+			Args:   map[string]interface{}{"groupid": "2", "name": "Linux"},
+			Method: "hostgroup.update",
+			Params: `{"groupid":"2","name":"Linux"}`,
+		},
+	})
+}
+
+func TestDeleteHostGroup(t *testing.T) {
+	zabbixtest.RunToolCases(t, hostgroups.DeleteHostGroup(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing groupids",
+			Args:      map[string]interface{}{},
+			WantError: "groupids is required",
+		},
+		{
+			Name:   "delete",
+			Setup:  addHostGroup,
+			Args:   map[string]interface{}{"groupids": "2"},
+			Method: "hostgroup.delete",
+			Params: `["2"]`,
+		},
+	})
+}
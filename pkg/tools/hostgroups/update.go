@@ -10,12 +10,23 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // UpdateHostGroup returns the tool definition and handler for updating a Zabbix host group
 func UpdateHostGroup(logger *logrus.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("zabbix_update_host_group",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update Host Group",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Update an existing host group in Zabbix."),
 			mcp.WithString("groupid",
 				mcp.Description("ID of the host group to update"),
@@ -27,6 +38,7 @@ func UpdateHostGroup(logger *logrus.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			logger := logging.FromContext(ctx, logger)
 			zabbixClient, err := client.GetZabbixClientFromContext(ctx, logger)
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
@@ -54,7 +66,7 @@ func UpdateHostGroup(logger *logrus.Logger) server.ServerTool {
 			}
 
 			// Make API call
-			result, err := zabbixClient.Call("hostgroup.update", params)
+			result, err := zabbixClient.CallContext(ctx, "hostgroup.update", params)
 			if err != nil {
 				logger.WithError(err).Error("Failed to update host group")
 				return mcp.NewToolResultError(err.Error()), nil
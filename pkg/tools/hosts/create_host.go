@@ -13,12 +13,23 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // CreateHost creates a tool for creating a new host in Zabbix
 func CreateHost(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("create_host",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create Host",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new host in the Zabbix server."),
 			mcp.WithString("host",
 				mcp.Required(),
@@ -102,7 +113,7 @@ func CreateHost(logger *log.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return createHostHandler(ctx, req, logger)
+			return createHostHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -270,7 +281,7 @@ func createHostHandler(ctx context.Context, req mcp.CallToolRequest, logger *log
 		params.Templates = templates
 	}
 
-	result, err := zabbix.Call("host.create", params)
+	result, err := zabbix.CallContext(ctx, "host.create", params)
 	if err != nil {
 		logger.WithError(err).Error("Failed to create host")
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create host: %v", err)), nil
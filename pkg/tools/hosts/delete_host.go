@@ -12,20 +12,33 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/confirm"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // DeleteHost creates a tool for deleting hosts from Zabbix
 func DeleteHost(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("delete_host",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete Host",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete one or more hosts from the Zabbix server."),
 			mcp.WithString("hostids",
 				mcp.Required(),
 				mcp.Description("Comma-separated list of host IDs to delete"),
 			),
+			confirm.WithConfirm(),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return deleteHostHandler(ctx, req, logger)
+			return deleteHostHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -54,7 +67,45 @@ func deleteHostHandler(ctx context.Context, req mcp.CallToolRequest, logger *log
 		return mcp.NewToolResultError("At least one hostid is required"), nil
 	}
 
-	result, err := zabbix.Call("host.delete", hostids)
+	result, err := zabbix.CallContext(ctx, "host.get", map[string]interface{}{
+		"output":         []string{"hostid", "host"},
+		"hostids":        hostids,
+		"selectItems":    "count",
+		"selectTriggers": "count",
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to resolve hosts")
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to resolve hosts: %v", err)), nil
+	}
+
+	var hosts []struct {
+		HostID   string        `json:"hostid"`
+		Host     string        `json:"host"`
+		Items    confirm.Count `json:"items"`
+		Triggers confirm.Count `json:"triggers"`
+	}
+	if err := json.Unmarshal(result, &hosts); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse hosts: %v", err)), nil
+	}
+
+	found := make([]confirm.Object, 0, len(hosts))
+	for _, h := range hosts {
+		found = append(found, confirm.Object{
+			ID:      h.HostID,
+			Name:    h.Host,
+			Details: confirm.Plural(h.Items, "item", "items") + ", " + confirm.Plural(h.Triggers, "trigger", "triggers"),
+		})
+	}
+
+	if res := confirm.Request(ctx, req, confirm.Operation{
+		Action:  "Delete " + confirm.Plural(len(hostids), "host", "hosts"),
+		Objects: confirm.Resolve(hostids, found),
+		Warning: "All items, triggers, graphs and history of these hosts will be deleted.",
+	}, logger); res != nil {
+		return res, nil
+	}
+
+	result, err = zabbix.CallContext(ctx, "host.delete", hostids)
 	if err != nil {
 		logger.WithError(err).Error("Failed to delete hosts")
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete hosts: %v", err)), nil
@@ -12,6 +12,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/federation"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -26,6 +28,9 @@ type Host struct {
 	Interfaces  []client.HostInterface `json:"interfaces"`
 	Macros      []client.Macro         `json:"macros,omitempty"`
 	Inventory   interface{}            `json:"inventory,omitempty"`
+	// Instance is the named instance the host came from in a federated
+	// query
+	Instance string `json:"instance,omitempty"`
 }
 
 // GetHosts creates a tool for listing Zabbix hosts
@@ -34,7 +39,11 @@ func GetHosts(logger *log.Logger) server.ServerTool {
 		Tool: mcp.NewTool("get_hosts",
 			mcp.WithToolAnnotation(
 				mcp.ToolAnnotation{
-					IdempotentHint: utils.ToBoolPtr(true),
+					Title:           "Get Hosts",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
 				},
 			),
 			mcp.WithDescription("List hosts from the Zabbix server. Can filter by host IDs, group IDs, or search term."),
@@ -52,7 +61,7 @@ func GetHosts(logger *log.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getHostsHandler(ctx, req, logger)
+			return getHostsHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -60,11 +69,9 @@ func GetHosts(logger *log.Logger) server.ServerTool {
 func getHostsHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	logger.Debug("Handling get_hosts request")
 
-	// Get Zabbix client from context
-	zabbix, err := client.GetZabbixClientFromContext(ctx, logger)
+	targets, err := federation.Targets(req)
 	if err != nil {
-		logger.WithError(err).Error("Failed to get Zabbix client")
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get Zabbix client: %v", err)), nil
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
 	// Build parameters
@@ -102,8 +109,22 @@ func getHostsHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.L
 		params.Limit = 100
 	}
 
+	// Query every requested instance and merge the hosts in instance order
+	if targets != nil {
+		merged := federation.Query(ctx, targets, "host.get", params, func(h *Host, instance string) { h.Instance = instance }, logger)
+		merged.Truncate(params.Limit)
+		return federation.ToolResult(merged), nil
+	}
+
+	// Get Zabbix client from context
+	zabbix, err := client.GetZabbixClientFromContext(ctx, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get Zabbix client")
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get Zabbix client: %v", err)), nil
+	}
+
 	// Make API call
-	result, err := zabbix.Call("host.get", params)
+	result, err := zabbix.CallContext(ctx, "host.get", params)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get hosts")
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get hosts: %v", err)), nil
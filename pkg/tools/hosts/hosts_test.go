@@ -0,0 +1,248 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package hosts_test
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/hosts"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addHost(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add(zabbixtest.Hosts, zabbixtest.Object{"hostid": "10084", "host": "web01", "name": "Web 01", "status": "0"})
+}
+
+func TestGetHosts(t *testing.T) {
+	zabbixtest.RunToolCases(t, hosts.GetHosts(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "host.get",
+			Params: `{"output":"extend","selectHostGroups":"extend","selectParentTemplates":"extend","selectTags":"extend","selectInterfaces":"extend","selectMacros":"extend","selectInventory":"extend","limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"hostids": "1, 2", "groupids": "4", "search": "web", "limit": float64(5)},
+			Method: "host.get",
+			Params: `{"output":"extend","hostids":["1","2"],"groupids":["4"],"search":{"name":"web"},"selectHostGroups":"extend","selectParentTemplates":"extend","selectTags":"extend","selectInterfaces":"extend","selectMacros":"extend","selectInventory":"extend","limit":5}`,
+		},
+		{
+			Name:     "returns model hosts",
+			Setup:    addHost,
+			Args:     map[string]interface{}{"hostids": "10084"},
+			Method:   "host.get",
+			WantText: `"host": "web01"`,
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("host.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Failed to get hosts",
+		},
+	})
+}
+
+func TestCreateHost(t *testing.T) {
+	zabbixtest.RunToolCases(t, hosts.CreateHost(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing host",
+			Args:      map[string]interface{}{"groupids": "2"},
+			WantError: "host parameter is required",
+		},
+		{
+			Name:      "missing groupids",
+			Args:      map[string]interface{}{"host": "web01"},
+			WantError: "groupids parameter is required",
+		},
+		{
+			Name:   "minimal",
+			Args:   map[string]interface{}{"host": "web01", "groupids": "2,3"},
+			Method: "host.create",
+			Params: `{"host":"web01","groups":[{"groupid":"2"},{"groupid":"3"}]}`,
+		},
+		{
+			Name: "agent interface, templates and tags",
+			Args: map[string]interface{}{
+				"host": "web01", "groupids": "2", "name": "Web 01", "ip": "10.0.0.1", "port": "10051",
+				"templateids": "10001", "tags": "env:prod, role", "description": "front end",
+			},
+			Method: "host.create",
+			Params: `{
+				"host":"web01","name":"Web 01","groups":[{"groupid":"2"}],
+				"interfaces":[{"type":"1","main":"1","useip":"1","ip":"10.0.0.1","dns":"","port":"10051"}],
+				"templates":[{"templateid":"10001"}],"description":"front end",
+				"tags":[{"tag":"env","value":"prod"},{"tag":"role","value":""}]
+			}`,
+			WantText: `"hostids"`,
+		},
+		{
+			Name:   "dns interface",
+			Args:   map[string]interface{}{"host": "web01", "groupids": "2", "dns": "web01.example.com"},
+			Method: "host.create",
+			Params: `{"host":"web01","groups":[{"groupid":"2"}],"interfaces":[{"type":"1","main":"1","useip":"0","ip":"","dns":"web01.example.com","port":"10050"}]}`,
+		},
+		{
+			Name: "json interfaces, macros, inventory, encryption, proxy and ipmi",
+			Args: map[string]interface{}{
+				"host": "sw01", "groupids": "2",
+				"interfaces":       `[{"type":"2","main":"1","useip":"1","ip":"10.0.0.2","dns":"","port":"161","details":{"version":2,"community":"public"}}]`,
+				"macros":           `[{"macro":"{$SNMP}","value":"x"}]`,
+				"inventory_mode":   float64(1),
+				"inventory":        `{"location":"rack 4"}`,
+				"tls_connect":      float64(2),
+				"tls_accept":       float64(2),
+				"tls_psk_identity": "psk01",
+				"tls_psk":          "0123456789abcdef0123456789abcdef",
+				"monitored_by":     float64(1),
+				"proxyid":          "5",
+				"ipmi_authtype":    float64(2),
+				"ipmi_privilege":   float64(3),
+				"ipmi_username":    "admin",
+				"ipmi_password":    "secret",
+			},
+			Method: "host.create",
+			Params: `{
+				"host":"sw01","groups":[{"groupid":"2"}],
+				"interfaces":[{"type":"2","main":"1","useip":"1","ip":"10.0.0.2","dns":"","port":"161","details":{"version":2,"community":"public"}}],
+				"macros":[{"macro":"{$SNMP}","value":"x"}],
+				"inventory_mode":1,"inventory":{"location":"rack 4"},
+				"tls_connect":2,"tls_accept":2,"tls_psk_identity":"psk01","tls_psk":"0123456789abcdef0123456789abcdef",
+				"monitored_by":1,"proxyid":"5",
+				"ipmi_authtype":2,"ipmi_privilege":3,"ipmi_username":"admin","ipmi_password":"secret"
+			}`,
+		},
+		{
+			Name:      "invalid interfaces json",
+			Args:      map[string]interface{}{"host": "web01", "groupids": "2", "interfaces": "not json"},
+			WantError: "Failed to parse interfaces JSON",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("host.create"),
+			Args:      map[string]interface{}{"host": "web01", "groupids": "2"},
+			WantError: "Injected failure.",
+		},
+	})
+}
+
+func TestUpdateHost(t *testing.T) {
+	zabbixtest.RunToolCases(t, hosts.UpdateHost(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing hostid",
+			Args:      map[string]interface{}{"name": "x"},
+			WantError: "hostid parameter is required",
+		},
+		{
+			Name:   "name and status",
+			Setup:  addHost,
+			Args:   map[string]interface{}{"hostid": "10084", "name": "Web 01 (old)", "status": float64(1)},
+			Method: "host.update",
+			Params: `{"hostid":"10084","name":"Web 01 (old)","status":1}`,
+		},
+		{
+			Name:  "zero values are sent",
+			Setup: addHost,
+			Args: map[string]interface{}{
+				"hostid": "10084", "status": float64(0), "inventory_mode": float64(-1), "tls_connect": float64(1),
+				"tls_accept": float64(1), "monitored_by": float64(0), "ipmi_authtype": float64(0), "ipmi_privilege": float64(2),
+			},
+			Method: "host.update",
+			Params: `{"hostid":"10084","status":0,"inventory_mode":-1,"tls_connect":1,"tls_accept":1,"monitored_by":0,"ipmi_authtype":0,"ipmi_privilege":2}`,
+		},
+		{
+			Name:  "tags, macros and inventory",
+			Setup: addHost,
+			Args: map[string]interface{}{
+				"hostid": "10084", "host": "web01a", "tags": "env:prod", "macros": `[{"macro":"{$A}","value":"1","type":"1"}]`,
+				"inventory": `{"os":"linux"}`, "proxy_groupid": "7", "description": "moved",
+			},
+			Method: "host.update",
+			Params: `{"hostid":"10084","host":"web01a","description":"moved","tags":[{"tag":"env","value":"prod"}],"macros":[{"macro":"{$A}","value":"1","type":"1"}],"inventory":{"os":"linux"},"proxy_groupid":"7"}`,
+		},
+		{
+			Name: "reports changes",
+			Setup: func(t *testing.T, s *zabbixtest.Server) {
+				s.Model.Add(zabbixtest.Hosts, zabbixtest.Object{
+					"hostid": "10084", "host": "web01", "status": "0",
+					"tags":   []zabbixtest.Object{{"tag": "env", "value": "test"}},
+					"macros": []zabbixtest.Object{{"macro": "{$A}", "value": "1"}},
+				})
+			},
+			Args: map[string]interface{}{"hostid": "10084", "status": float64(1), "tags": "env:prod,team:ops", "macros": `[{"macro":"{$A}","value":"2"}]`},
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				zabbixtest.AssertChanges(t, result, `[
+					{"field":"macros[{$A}].value","change":"changed","before":"1","after":"2"},
+					{"field":"status","change":"changed","before":"0","after":1},
+					{"field":"tags[env:test]","change":"removed","before":{"tag":"env","value":"test"}},
+					{"field":"tags[env:prod]","change":"added","after":{"tag":"env","value":"prod"}},
+					{"field":"tags[team:ops]","change":"added","after":{"tag":"team","value":"ops"}}
+				]`)
+			},
+		},
+		{
+			Name: "zabbix 6.0 reports changes",
+			Setup: func(t *testing.T, s *zabbixtest.Server) {
+				s.Version = "6.0.30"
+				s.Model.Add(zabbixtest.Hosts, zabbixtest.Object{
+					"hostid": "10084", "host": "web01", "status": "0",
+					"groups": []zabbixtest.Object{{"groupid": "2", "name": "Linux servers"}},
+				})
+			},
+			Args: map[string]interface{}{"hostid": "10084", "status": float64(1)},
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				zabbixtest.AssertChanges(t, result, `[{"field":"status","change":"changed","before":"0","after":1}]`)
+			},
+		},
+		{
+			Name:     "diff unavailable",
+			Setup:    func(t *testing.T, s *zabbixtest.Server) { addHost(t, s); zabbixtest.Failing("host.get")(t, s) },
+			Args:     map[string]interface{}{"hostid": "10084", "status": float64(1)},
+			Method:   "host.update",
+			WantText: `"changes_unavailable": "host.get before the update failed`,
+		},
+		{
+			Name:      "unknown host",
+			Args:      map[string]interface{}{"hostid": "1", "name": "x"},
+			WantError: "No permissions to referred object",
+		},
+	})
+}
+
+func TestDeleteHost(t *testing.T) {
+	setup := func(t *testing.T, s *zabbixtest.Server) {
+		addHost(t, s)
+		s.Model.Add(zabbixtest.Items, zabbixtest.Object{"hostid": "10084", "name": "CPU"})
+		s.Model.Add(zabbixtest.Items, zabbixtest.Object{"hostid": "10084", "name": "Memory"})
+		s.Model.Add(zabbixtest.Triggers, zabbixtest.Object{"hostid": "10084", "description": "High CPU"})
+	}
+
+	zabbixtest.RunToolCases(t, hosts.DeleteHost(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing hostids",
+			Args:      map[string]interface{}{},
+			WantError: "hostids parameter is required",
+		},
+		{
+			Name:      "requires confirmation",
+			Setup:     setup,
+			Args:      map[string]interface{}{"hostids": "10084"},
+			Method:    "host.get",
+			Params:    `{"output":["hostid","host"],"hostids":["10084"],"selectItems":"count","selectTriggers":"count"}`,
+			WantError: `"details": "2 items, 1 trigger"`,
+			Check: func(t *testing.T, s *zabbixtest.Server, _ *mcp.CallToolResult) {
+				s.AssertNotCalled(t, "host.delete")
+			},
+		},
+		{
+			Name:     "confirmed",
+			Setup:    setup,
+			Args:     map[string]interface{}{"hostids": "10084", "confirm": true},
+			Method:   "host.delete",
+			Params:   `["10084"]`,
+			WantText: "Hosts deleted successfully",
+		},
+	})
+}
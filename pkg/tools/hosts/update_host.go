@@ -13,13 +13,25 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/diff"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // UpdateHost creates a tool for updating an existing host in Zabbix
 func UpdateHost(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("update_host",
-			mcp.WithDescription("Update an existing host in the Zabbix server."),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update Host",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
+			mcp.WithDescription("Update an existing host in the Zabbix server. Returns the fields that changed, with their old and new values."),
 			mcp.WithString("hostid",
 				mcp.Required(),
 				mcp.Description("ID of the host to update"),
@@ -89,11 +101,27 @@ func UpdateHost(logger *log.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return updateHostHandler(ctx, req, logger)
+			return updateHostHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
 
+// hostDiff fetches a host with the fields update_host can change
+var hostDiff = diff.Getter{
+	Method:   "host.get",
+	IDsParam: "hostids",
+	Params: map[string]interface{}{
+		"selectTags":            "extend",
+		"selectMacros":          "extend",
+		"selectInterfaces":      "extend",
+		"selectHostGroups":      "extend",
+		"selectParentTemplates": []string{"templateid", "host"},
+		"selectInventory":       "extend",
+	},
+	Ignore: []string{"maintenance_status", "maintenance_type", "maintenance_from", "maintenanceid", "active_available",
+		"interfaces.available", "interfaces.error", "interfaces.errors_from", "interfaces.disable_until"},
+}
+
 func updateHostHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	logger.Debug("Handling update_host request")
 
@@ -218,7 +246,9 @@ func updateHostHandler(ctx context.Context, req mcp.CallToolRequest, logger *log
 		params.IpmiPassword = v
 	}
 
-	result, err := zabbix.Call("host.update", params)
+	update := hostDiff.Track(ctx, zabbix, hostid)
+
+	result, err := zabbix.CallContext(ctx, "host.update", params)
 	if err != nil {
 		logger.WithError(err).Error("Failed to update host")
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update host: %v", err)), nil
@@ -232,10 +262,15 @@ func updateHostHandler(ctx context.Context, req mcp.CallToolRequest, logger *log
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse response: %v", err)), nil
 	}
 
-	jsonData, _ := json.MarshalIndent(map[string]interface{}{
+	responseData := map[string]interface{}{
 		"message": "Host updated successfully",
 		"hostids": response.HostIDs,
-	}, "", "  ")
+	}
+	if err := update.Report(ctx, responseData); err != nil {
+		logger.WithError(err).Warn("Failed to compare host after update")
+	}
+
+	jsonData, _ := json.MarshalIndent(responseData, "", "  ")
 
 	logger.WithField("hostid", hostid).Info("Successfully updated host")
 	return mcp.NewToolResultText(string(jsonData)), nil
@@ -0,0 +1,50 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package instances_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/instances"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func setInstances(t *testing.T, s *zabbixtest.Server) {
+	client.SetInstances(client.InstancesConfig{
+		Default: "eu",
+		Instances: []client.Instance{
+			{Name: "eu", Description: "Europe", URL: "https://zabbix-eu.example.com/api_jsonrpc.php", Token: "eu-token"},
+			{Name: "us", URL: "https://zabbix-us.example.com/api_jsonrpc.php", User: "automation", Password: "us-password"},
+		},
+	})
+	t.Cleanup(func() { client.SetInstances(client.InstancesConfig{}) })
+}
+
+func TestListZabbixInstances(t *testing.T) {
+	zabbixtest.RunToolCases(t, instances.ListZabbixInstances(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:     "none configured",
+			Args:     map[string]interface{}{},
+			WantText: "No named Zabbix instances are configured",
+		},
+		{
+			Name:  "lists instances without credentials",
+			Setup: setInstances,
+			Args:  map[string]interface{}{},
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				text := zabbixtest.ResultText(result)
+				if strings.Contains(text, "eu-token") || strings.Contains(text, "us-password") {
+					t.Fatalf("credentials leaked: %s", text)
+				}
+				zabbixtest.AssertJSON(t, []byte(text), `[
+					{"name":"eu","description":"Europe","url":"https://zabbix-eu.example.com/api_jsonrpc.php","auth":"token","default":true},
+					{"name":"us","url":"https://zabbix-us.example.com/api_jsonrpc.php","auth":"user","default":false}
+				]`)
+			},
+		},
+	})
+}
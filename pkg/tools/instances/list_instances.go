@@ -0,0 +1,80 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package instances
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
+)
+
+// instanceInfo describes a configured instance without its credentials
+type instanceInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+	Auth        string `json:"auth"`
+	Default     bool   `json:"default"`
+}
+
+// ListZabbixInstances creates a tool for listing the named Zabbix instances
+func ListZabbixInstances(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_zabbix_instances",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "List Zabbix Instances",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
+			mcp.WithDescription("List the named Zabbix instances that other tools can target with their instance argument, e.g. to compare data across regions."),
+		),
+		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listZabbixInstancesHandler(ctx, req, logging.FromContext(ctx, logger))
+		},
+	}
+}
+
+func listZabbixInstancesHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	logger.Debug("Handling list_zabbix_instances request")
+
+	config := client.Instances()
+	if len(config.Instances) == 0 {
+		return mcp.NewToolResultText("No named Zabbix instances are configured; tools use the session's Zabbix connection."), nil
+	}
+
+	infos := make([]instanceInfo, 0, len(config.Instances))
+	for _, instance := range config.Instances {
+		auth := "token"
+		if instance.Token == "" {
+			auth = "user"
+		}
+		infos = append(infos, instanceInfo{
+			Name:        instance.Name,
+			Description: instance.Description,
+			URL:         instance.URL,
+			Auth:        auth,
+			Default:     instance.Name == config.Default,
+		})
+	}
+
+	jsonData, err := json.MarshalIndent(infos, "", "  ")
+	if err != nil {
+		logger.WithError(err).Error("Failed to marshal instances to JSON")
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling JSON: %v", err)), nil
+	}
+
+	logger.WithField("instance_count", len(infos)).Debug("Successfully listed Zabbix instances")
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
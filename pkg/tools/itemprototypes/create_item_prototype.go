@@ -12,6 +12,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type ItemPrototypeCreateParams struct {
@@ -29,6 +31,15 @@ type ItemPrototypeCreateParams struct {
 func CreateItemPrototype(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("create_item_prototype",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create Item Prototype",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new item prototype in Zabbix."),
 			mcp.WithString("ruleid", mcp.Description("ID of the LLD rule this prototype belongs to"), mcp.Required()),
 			mcp.WithString("hostid", mcp.Description("Host ID to create the item prototype for"), mcp.Required()),
@@ -41,7 +52,7 @@ func CreateItemPrototype(logger *log.Logger) server.ServerTool {
 			mcp.WithString("description", mcp.Description("Description")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return createItemPrototypeHandler(ctx, req, logger)
+			return createItemPrototypeHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -87,7 +98,7 @@ func createItemPrototypeHandler(ctx context.Context, req mcp.CallToolRequest, lo
 		params.Description = v
 	}
 
-	result, err := zabbix.Call("itemprototype.create", params)
+	result, err := zabbix.CallContext(ctx, "itemprototype.create", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create item prototype: %v", err)), nil
 	}
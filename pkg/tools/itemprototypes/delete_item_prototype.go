@@ -13,16 +13,27 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func DeleteItemPrototype(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("delete_item_prototype",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete Item Prototype",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete item prototypes from Zabbix."),
 			mcp.WithString("itemids", mcp.Description("Comma-separated list of item prototype IDs to delete"), mcp.Required()),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return deleteItemPrototypeHandler(ctx, req, logger)
+			return deleteItemPrototypeHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -50,7 +61,7 @@ func deleteItemPrototypeHandler(ctx context.Context, req mcp.CallToolRequest, lo
 		}
 	}
 
-	result, err := zabbix.Call("itemprototype.delete", itemids)
+	result, err := zabbix.CallContext(ctx, "itemprototype.delete", itemids)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete item prototypes: %v", err)), nil
 	}
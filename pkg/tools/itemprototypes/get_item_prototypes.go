@@ -13,6 +13,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -46,7 +47,15 @@ type ItemPrototypeGetParams struct {
 func GetItemPrototypes(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_item_prototypes",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Item Prototypes",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Retrieve item prototypes from Zabbix."),
 			mcp.WithString("itemids", mcp.Description("Comma-separated list of item prototype IDs to filter by")),
 			mcp.WithString("hostids", mcp.Description("Comma-separated list of host IDs to filter by")),
@@ -56,7 +65,7 @@ func GetItemPrototypes(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("limit", mcp.Description("Max item prototypes to return (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getItemPrototypesHandler(ctx, req, logger)
+			return getItemPrototypesHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -94,7 +103,7 @@ func getItemPrototypesHandler(ctx context.Context, req mcp.CallToolRequest, logg
 		}
 	}
 
-	result, err := zabbix.Call("itemprototype.get", params)
+	result, err := zabbix.CallContext(ctx, "itemprototype.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get item prototypes: %v", err)), nil
 	}
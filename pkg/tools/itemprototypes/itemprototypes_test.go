@@ -0,0 +1,100 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package itemprototypes_test
+
+import (
+	"testing"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/itemprototypes"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addPrototype(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add("itemprototype", zabbixtest.Object{"itemid": "40010", "hostid": "10084", "name": "Free space on {#FSNAME}", "key_": "vfs.fs.size[{#FSNAME},free]"})
+}
+
+func TestGetItemPrototypes(t *testing.T) {
+	zabbixtest.RunToolCases(t, itemprototypes.GetItemPrototypes(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "itemprototype.get",
+			Params: `{"output":"extend","selectDiscoveryRule":"extend","limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"discoveryids": "40001", "search": "vfs", "limit": float64(5)},
+			Method: "itemprototype.get",
+			Params: `{"output":"extend","discoveryids":["40001"],"search":{"name":"vfs","key_":"vfs"},"selectDiscoveryRule":"extend","limit":5}`,
+		},
+		{
+			Name:     "returns model prototypes",
+			Setup:    addPrototype,
+			Args:     map[string]interface{}{"itemids": "40010"},
+			WantText: "vfs.fs.size[{#FSNAME},free]",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("itemprototype.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Failed to get item prototypes",
+		},
+	})
+}
+
+func TestCreateItemPrototype(t *testing.T) {
+	zabbixtest.RunToolCases(t, itemprototypes.CreateItemPrototype(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing ruleid",
+			Args:      map[string]interface{}{"hostid": "10084", "name": "Free", "key_": "vfs.fs.size"},
+			WantError: "ruleid, hostid, name, and key_ are required",
+		},
+		{
+			Name: "create",
+			Args: map[string]interface{}{
+				"ruleid": "40001", "hostid": "10084", "name": "Free space on {#FSNAME}", "key_": "vfs.fs.size[{#FSNAME},free]",
+				"type": float64(0), "value_type": float64(3), "delay": "5m", "units": "B",
+			},
+			Method:   "itemprototype.create",
+			Params:   `{"ruleid":"40001","hostid":"10084","name":"Free space on {#FSNAME}","key_":"vfs.fs.size[{#FSNAME},free]","type":0,"value_type":3,"delay":"5m","units":"B"}`,
+			WantText: "Item prototype created",
+		},
+	})
+}
+
+func TestUpdateItemPrototype(t *testing.T) {
+	zabbixtest.RunToolCases(t, itemprototypes.UpdateItemPrototype(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing itemid",
+			Args:      map[string]interface{}{"name": "x"},
+			WantError: "itemid is required",
+		},
+		{
+			Name:     "update",
+			Setup:    addPrototype,
+			Args:     map[string]interface{}{"itemid": "40010", "units": "%", "status": float64(0)},
+			Method:   "itemprototype.update",
+			Params:   `{"itemid":"40010","units":"%","status":0}`,
+			WantText: "Item prototype updated",
+		},
+	})
+}
+
+func TestDeleteItemPrototype(t *testing.T) {
+	zabbixtest.RunToolCases(t, itemprototypes.DeleteItemPrototype(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing itemids",
+			Args:      map[string]interface{}{},
+			WantError: "itemids is required",
+		},
+		{
+			Name:     "delete",
+			Setup:    addPrototype,
+			Args:     map[string]interface{}{"itemids": "40010"},
+			Method:   "itemprototype.delete",
+			Params:   `["40010"]`,
+			WantText: "Item prototypes deleted",
+		},
+	})
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type ItemPrototypeUpdateParams struct {
@@ -27,6 +29,15 @@ type ItemPrototypeUpdateParams struct {
 func UpdateItemPrototype(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("update_item_prototype",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update Item Prototype",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Update an existing item prototype in Zabbix."),
 			mcp.WithString("itemid", mcp.Description("Item prototype ID to update"), mcp.Required()),
 			mcp.WithString("name", mcp.Description("New name")),
@@ -37,7 +48,7 @@ func UpdateItemPrototype(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("status", mcp.Description("Status: 0=enabled, 1=disabled")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return updateItemPrototypeHandler(ctx, req, logger)
+			return updateItemPrototypeHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -80,7 +91,7 @@ func updateItemPrototypeHandler(ctx context.Context, req mcp.CallToolRequest, lo
 		params.Status = &s
 	}
 
-	result, err := zabbix.Call("itemprototype.update", params)
+	result, err := zabbix.CallContext(ctx, "itemprototype.update", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update item prototype: %v", err)), nil
 	}
@@ -13,11 +13,22 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func CreateItem(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("create_item",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create Item",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new monitoring item in Zabbix."),
 			mcp.WithString("hostid", mcp.Required(), mcp.Description("Host ID")),
 			mcp.WithString("interfaceid", mcp.Description("Interface ID (required for Zabbix agent items)")),
@@ -29,7 +40,7 @@ func CreateItem(logger *log.Logger) server.ServerTool {
 			mcp.WithString("tags", mcp.Description("Tags in format key:value,key2:value2")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return createItemHandler(ctx, req, logger)
+			return createItemHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -81,7 +92,7 @@ func createItemHandler(ctx context.Context, req mcp.CallToolRequest, logger *log
 		params.Tags = tags
 	}
 
-	result, err := zabbix.Call("item.create", params)
+	result, err := zabbix.CallContext(ctx, "item.create", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create item: %v", err)), nil
 	}
@@ -12,16 +12,27 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func DeleteItem(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("delete_item",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete Item",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete items from Zabbix."),
 			mcp.WithString("itemids", mcp.Required(), mcp.Description("Comma-separated item IDs")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return deleteItemHandler(ctx, req, logger)
+			return deleteItemHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -42,7 +53,7 @@ func deleteItemHandler(ctx context.Context, req mcp.CallToolRequest, logger *log
 		return mcp.NewToolResultError("itemids is required"), nil
 	}
 
-	result, err := zabbix.Call("item.delete", splitAndTrim(itemidsStr))
+	result, err := zabbix.CallContext(ctx, "item.delete", splitAndTrim(itemidsStr))
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete items: %v", err)), nil
 	}
@@ -13,6 +13,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -41,7 +42,15 @@ type HistoryGetParams struct {
 func GetHistory(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_history",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get History",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Get historical values for monitoring items. Returns the most recent values for CPU, memory, or any monitored metric."),
 			mcp.WithString("itemids", mcp.Required(), mcp.Description("Comma-separated list of item IDs to get history for")),
 			mcp.WithNumber("history_type", mcp.Description("Value type: 0=float (default), 1=char, 2=log, 3=unsigned int, 4=text")),
@@ -50,7 +59,7 @@ func GetHistory(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("limit", mcp.Description("Max records to return (default: 10, max: 1000)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getHistoryHandler(ctx, req, logger)
+			return getHistoryHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -112,7 +121,7 @@ func getHistoryHandler(ctx context.Context, req mcp.CallToolRequest, logger *log
 		"limit":     params.Limit,
 	}).Debug("Getting history")
 
-	result, err := zabbix.Call("history.get", params)
+	result, err := zabbix.CallContext(ctx, "history.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get history: %v", err)), nil
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -28,7 +29,15 @@ type Item struct {
 func GetItems(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_items",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Items",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("List items from the Zabbix server."),
 			mcp.WithString("itemids", mcp.Description("Comma-separated list of item IDs")),
 			mcp.WithString("hostids", mcp.Description("Comma-separated list of host IDs")),
@@ -36,7 +45,7 @@ func GetItems(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("limit", mcp.Description("Max items to return (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getItemsHandler(ctx, req, logger)
+			return getItemsHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -64,7 +73,7 @@ func getItemsHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.L
 		}
 	}
 
-	result, err := zabbix.Call("item.get", params)
+	result, err := zabbix.CallContext(ctx, "item.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get items: %v", err)), nil
 	}
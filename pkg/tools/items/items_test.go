@@ -0,0 +1,199 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package items_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/items"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addItem(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add(zabbixtest.Items, zabbixtest.Object{"itemid": "23456", "hostid": "10084", "name": "CPU utilization", "key_": "system.cpu.util"})
+}
+
+func TestGetItems(t *testing.T) {
+	zabbixtest.RunToolCases(t, items.GetItems(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "item.get",
+			Params: `{"output":"extend","selectHosts":"extend","selectTags":"extend","limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"itemids": "1,2", "hostids": "10084", "search": "cpu", "limit": float64(3)},
+			Method: "item.get",
+			Params: `{"output":"extend","itemids":["1","2"],"hostids":["10084"],"search":{"name":"cpu"},"selectHosts":"extend","selectTags":"extend","limit":3}`,
+		},
+		{
+			Name:     "returns model items",
+			Setup:    addItem,
+			Args:     map[string]interface{}{"hostids": "10084"},
+			WantText: "system.cpu.util",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("item.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Failed to get items",
+		},
+	})
+}
+
+func TestGetHistory(t *testing.T) {
+	zabbixtest.RunToolCases(t, items.GetHistory(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing itemids",
+			Args:      map[string]interface{}{},
+			WantError: "itemids is required",
+		},
+		{
+			Name:   "explicit range",
+			Args:   map[string]interface{}{"itemids": "23456", "history_type": float64(3), "time_from": float64(1700000000), "time_till": float64(1700003600), "limit": float64(50)},
+			Method: "history.get",
+			Params: `{"output":"extend","itemids":["23456"],"history":3,"time_from":1700000000,"time_till":1700003600,"sortfield":["clock"],"sortorder":["DESC"],"limit":50}`,
+		},
+		{
+			Name:   "default range is the last hour",
+			Args:   map[string]interface{}{"itemids": "23456"},
+			Method: "history.get",
+			Check: func(t *testing.T, s *zabbixtest.Server, _ *mcp.CallToolResult) {
+				req, _ := s.LastCall("history.get")
+				var params struct {
+					History  int   `json:"history"`
+					TimeFrom int64 `json:"time_from"`
+					TimeTill int64 `json:"time_till"`
+					Limit    int   `json:"limit"`
+				}
+				json.Unmarshal(req.Params, &params)
+				if params.TimeTill-params.TimeFrom != 3600 || params.History != 0 || params.Limit != 10 {
+					t.Errorf("unexpected defaults: %+v", params)
+				}
+			},
+		},
+		{
+			Name:   "limit is capped",
+			Args:   map[string]interface{}{"itemids": "23456", "time_from": float64(1), "time_till": float64(2), "limit": float64(5000)},
+			Method: "history.get",
+			Params: `{"output":"extend","itemids":["23456"],"history":0,"time_from":1,"time_till":2,"sortfield":["clock"],"sortorder":["DESC"],"limit":1000}`,
+		},
+		{
+			Name: "formats timestamps",
+			Setup: func(t *testing.T, s *zabbixtest.Server) {
+				s.Model.Add(zabbixtest.History, zabbixtest.Object{"itemid": "23456", "clock": "1700000000", "value": "12.5"})
+			},
+			Args: map[string]interface{}{"itemids": "23456"},
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				var entries []struct {
+					ItemID    string `json:"itemid"`
+					Timestamp string `json:"timestamp"`
+					Value     string `json:"value"`
+				}
+				zabbixtest.DecodeResult(t, result, &entries)
+				if len(entries) != 1 || entries[0].Value != "12.5" || entries[0].Timestamp == "" {
+					t.Errorf("unexpected history: %+v", entries)
+				}
+			},
+		},
+	})
+}
+
+func TestCreateItem(t *testing.T) {
+	zabbixtest.RunToolCases(t, items.CreateItem(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing key",
+			Args:      map[string]interface{}{"hostid": "10084", "name": "CPU"},
+			WantError: "hostid, name, and key_ are required",
+		},
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{"hostid": "10084", "name": "CPU", "key_": "system.cpu.util"},
+			Method: "item.create",
+			Params: `{"name":"CPU","key_":"system.cpu.util","hostid":"10084","type":0,"value_type":3,"delay":"1m"}`,
+		},
+		{
+			Name:     "all fields",
+			Args:     map[string]interface{}{"hostid": "10084", "name": "Trap", "key_": "trap", "interfaceid": "1", "type": float64(2), "value_type": float64(4), "delay": "0", "tags": "component:app"},
+			Method:   "item.create",
+			Params:   `{"name":"Trap","key_":"trap","hostid":"10084","interfaceid":"1","type":2,"value_type":4,"delay":"0","tags":[{"tag":"component","value":"app"}]}`,
+			WantText: "Item created",
+		},
+	})
+}
+
+func TestUpdateItem(t *testing.T) {
+	zabbixtest.RunToolCases(t, items.UpdateItem(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing itemid",
+			Args:      map[string]interface{}{"name": "x"},
+			WantError: "itemid is required",
+		},
+		{
+			Name:   "disable",
+			Setup:  addItem,
+			Args:   map[string]interface{}{"itemid": "23456", "status": float64(1)},
+			Method: "item.update",
+			Params: `{"itemid":"23456","status":1}`,
+		},
+		{
+			Name:     "rename, delay and tags",
+			Setup:    addItem,
+			Args:     map[string]interface{}{"itemid": "23456", "name": "CPU", "delay": "30s", "tags": "a:b"},
+			Method:   "item.update",
+			Params:   `{"itemid":"23456","name":"CPU","delay":"30s","tags":[{"tag":"a","value":"b"}]}`,
+			WantText: "Item updated",
+		},
+		{
+			Name: "reports changes",
+			Setup: func(t *testing.T, s *zabbixtest.Server) {
+				s.Model.Add(zabbixtest.Items, zabbixtest.Object{
+					"itemid": "23456", "hostid": "10084", "name": "CPU utilization", "status": "0", "lastvalue": "12",
+					"tags": []zabbixtest.Object{{"tag": "a", "value": "b"}},
+				})
+			},
+			Args: map[string]interface{}{"itemid": "23456", "status": float64(1), "tags": "a:c"},
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				zabbixtest.AssertChanges(t, result, `[
+					{"field":"status","change":"changed","before":"0","after":1},
+					{"field":"tags[a:b]","change":"removed","before":{"tag":"a","value":"b"}},
+					{"field":"tags[a:c]","change":"added","after":{"tag":"a","value":"c"}}
+				]`)
+			},
+		},
+		{
+			Name:     "diff unavailable",
+			Setup:    func(t *testing.T, s *zabbixtest.Server) { addItem(t, s); zabbixtest.Failing("item.get")(t, s) },
+			Args:     map[string]interface{}{"itemid": "23456", "status": float64(1)},
+			Method:   "item.update",
+			WantText: `"changes_unavailable": "item.get before the update failed`,
+		},
+	})
+}
+
+func TestDeleteItem(t *testing.T) {
+	zabbixtest.RunToolCases(t, items.DeleteItem(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing itemids",
+			Args:      map[string]interface{}{},
+			WantError: "itemids is required",
+		},
+		{
+			Name:     "delete",
+			Setup:    addItem,
+			Args:     map[string]interface{}{"itemids": "23456"},
+			Method:   "item.delete",
+			Params:   `["23456"]`,
+			WantText: "Items deleted",
+		},
+		{
+			Name:      "unknown item",
+			Args:      map[string]interface{}{"itemids": "1"},
+			WantError: "Failed to delete items",
+		},
+	})
+}
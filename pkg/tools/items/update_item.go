@@ -13,12 +13,24 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/diff"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func UpdateItem(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("update_item",
-			mcp.WithDescription("Update an existing item in Zabbix."),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update Item",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
+			mcp.WithDescription("Update an existing item in Zabbix. Returns the fields that changed, with their old and new values."),
 			mcp.WithString("itemid", mcp.Required(), mcp.Description("Item ID")),
 			mcp.WithString("name", mcp.Description("New item name")),
 			mcp.WithNumber("status", mcp.Description("Status: 0=enabled, 1=disabled")),
@@ -26,11 +38,22 @@ func UpdateItem(logger *log.Logger) server.ServerTool {
 			mcp.WithString("tags", mcp.Description("Tags in format key:value,key2:value2")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return updateItemHandler(ctx, req, logger)
+			return updateItemHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
 
+// itemDiff fetches an item with its tags and preprocessing steps
+var itemDiff = diff.Getter{
+	Method:   "item.get",
+	IDsParam: "itemids",
+	Params: map[string]interface{}{
+		"selectTags":          "extend",
+		"selectPreprocessing": "extend",
+	},
+	Ignore: []string{"lastclock", "lastns", "lastvalue", "prevvalue", "state", "error"},
+}
+
 func updateItemHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	zabbix, err := client.GetZabbixClientFromContext(ctx, logger)
 	if err != nil {
@@ -72,7 +95,9 @@ func updateItemHandler(ctx context.Context, req mcp.CallToolRequest, logger *log
 		params.Tags = tags
 	}
 
-	result, err := zabbix.Call("item.update", params)
+	update := itemDiff.Track(ctx, zabbix, itemid)
+
+	result, err := zabbix.CallContext(ctx, "item.update", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update item: %v", err)), nil
 	}
@@ -81,6 +106,11 @@ func updateItemHandler(ctx context.Context, req mcp.CallToolRequest, logger *log
 		ItemIDs []string `json:"itemids"`
 	}
 	json.Unmarshal(result, &response)
-	jsonData, _ := json.MarshalIndent(map[string]interface{}{"message": "Item updated", "itemids": response.ItemIDs}, "", "  ")
+	responseData := map[string]interface{}{"message": "Item updated", "itemids": response.ItemIDs}
+	if err := update.Report(ctx, responseData); err != nil {
+		logger.WithError(err).Warn("Failed to compare item after update")
+	}
+
+	jsonData, _ := json.MarshalIndent(responseData, "", "  ")
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
@@ -13,6 +13,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type LLDRuleCopyParams struct {
@@ -23,12 +25,21 @@ type LLDRuleCopyParams struct {
 func CopyLLDRule(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("copy_lld_rule",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Copy LLD Rule",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Copy low-level discovery rules to the specified hosts. This copies all item prototypes, trigger prototypes, graph prototypes, and host prototypes from the original discovery rules."),
 			mcp.WithString("discoveryids", mcp.Description("Comma-separated list of LLD rule IDs to copy"), mcp.Required()),
 			mcp.WithString("hostids", mcp.Description("Comma-separated list of destination host IDs to copy the LLD rules to"), mcp.Required()),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return copyLLDRuleHandler(ctx, req, logger)
+			return copyLLDRuleHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -74,7 +85,7 @@ func copyLLDRuleHandler(ctx context.Context, req mcp.CallToolRequest, logger *lo
 		"hostids":      hostids,
 	}
 
-	result, err := zabbix.Call("discoveryrule.copy", params)
+	result, err := zabbix.CallContext(ctx, "discoveryrule.copy", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to copy LLD rules: %v", err)), nil
 	}
@@ -12,6 +12,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type LLDRuleCreateParams struct {
@@ -27,6 +29,15 @@ type LLDRuleCreateParams struct {
 func CreateLLDRule(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("create_lld_rule",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create LLD Rule",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new low-level discovery rule in Zabbix."),
 			mcp.WithString("hostid", mcp.Description("Host ID to create the LLD rule for"), mcp.Required()),
 			mcp.WithString("name", mcp.Description("Name of the LLD rule"), mcp.Required()),
@@ -37,7 +48,7 @@ func CreateLLDRule(logger *log.Logger) server.ServerTool {
 			mcp.WithString("description", mcp.Description("Description of the LLD rule")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return createLLDRuleHandler(ctx, req, logger)
+			return createLLDRuleHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -79,7 +90,7 @@ func createLLDRuleHandler(ctx context.Context, req mcp.CallToolRequest, logger *
 		params.Description = v
 	}
 
-	result, err := zabbix.Call("discoveryrule.create", params)
+	result, err := zabbix.CallContext(ctx, "discoveryrule.create", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create LLD rule: %v", err)), nil
 	}
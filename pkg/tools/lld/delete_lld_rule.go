@@ -13,16 +13,27 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func DeleteLLDRule(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("delete_lld_rule",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete LLD Rule",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete low-level discovery rules from Zabbix."),
 			mcp.WithString("itemids", mcp.Description("Comma-separated list of LLD rule IDs to delete"), mcp.Required()),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return deleteLLDRuleHandler(ctx, req, logger)
+			return deleteLLDRuleHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -50,7 +61,7 @@ func deleteLLDRuleHandler(ctx context.Context, req mcp.CallToolRequest, logger *
 		}
 	}
 
-	result, err := zabbix.Call("discoveryrule.delete", itemids)
+	result, err := zabbix.CallContext(ctx, "discoveryrule.delete", itemids)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete LLD rules: %v", err)), nil
 	}
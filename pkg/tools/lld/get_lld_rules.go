@@ -13,6 +13,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -44,7 +45,15 @@ type LLDRuleGetParams struct {
 func GetLLDRules(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_lld_rules",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get LLD Rules",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Retrieve low-level discovery rules from Zabbix."),
 			mcp.WithString("itemids", mcp.Description("Comma-separated list of LLD rule IDs to filter by")),
 			mcp.WithString("hostids", mcp.Description("Comma-separated list of host IDs to filter by")),
@@ -53,7 +62,7 @@ func GetLLDRules(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("limit", mcp.Description("Max LLD rules to return (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getLLDRulesHandler(ctx, req, logger)
+			return getLLDRulesHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -88,7 +97,7 @@ func getLLDRulesHandler(ctx context.Context, req mcp.CallToolRequest, logger *lo
 		}
 	}
 
-	result, err := zabbix.Call("discoveryrule.get", params)
+	result, err := zabbix.CallContext(ctx, "discoveryrule.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get LLD rules: %v", err)), nil
 	}
@@ -0,0 +1,115 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package lld_test
+
+import (
+	"testing"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/lld"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addRule(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add("discoveryrule", zabbixtest.Object{"itemid": "40001", "hostid": "10084", "name": "Mounted filesystem discovery", "key_": "vfs.fs.discovery"})
+}
+
+func TestGetLLDRules(t *testing.T) {
+	zabbixtest.RunToolCases(t, lld.GetLLDRules(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "discoveryrule.get",
+			Params: `{"output":"extend","selectFilter":"extend","limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"hostids": "10084", "templateids": "10001", "search": "fs", "limit": float64(5)},
+			Method: "discoveryrule.get",
+			Params: `{"output":"extend","hostids":["10084"],"templateids":["10001"],"search":{"name":"fs","key_":"fs"},"selectFilter":"extend","limit":5}`,
+		},
+		{
+			Name:     "returns model rules",
+			Setup:    addRule,
+			Args:     map[string]interface{}{"hostids": "10084"},
+			WantText: "vfs.fs.discovery",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("discoveryrule.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Failed to get LLD rules",
+		},
+	})
+}
+
+func TestCreateLLDRule(t *testing.T) {
+	zabbixtest.RunToolCases(t, lld.CreateLLDRule(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing key",
+			Args:      map[string]interface{}{"hostid": "10084", "name": "FS"},
+			WantError: "hostid, name, and key_ are required",
+		},
+		{
+			Name:     "create",
+			Args:     map[string]interface{}{"hostid": "10084", "name": "FS", "key_": "vfs.fs.discovery", "type": float64(0), "delay": "1h", "lifetime": "7d"},
+			Method:   "discoveryrule.create",
+			Params:   `{"hostid":"10084","name":"FS","key_":"vfs.fs.discovery","type":0,"delay":"1h","lifetime":"7d"}`,
+			WantText: "LLD rule created",
+		},
+	})
+}
+
+func TestUpdateLLDRule(t *testing.T) {
+	zabbixtest.RunToolCases(t, lld.UpdateLLDRule(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing itemid",
+			Args:      map[string]interface{}{"name": "x"},
+			WantError: "itemid is required",
+		},
+		{
+			Name:     "disable",
+			Setup:    addRule,
+			Args:     map[string]interface{}{"itemid": "40001", "status": float64(1), "delay": "2h"},
+			Method:   "discoveryrule.update",
+			Params:   `{"itemid":"40001","delay":"2h","status":1}`,
+			WantText: "LLD rule updated",
+		},
+	})
+}
+
+func TestDeleteLLDRule(t *testing.T) {
+	zabbixtest.RunToolCases(t, lld.DeleteLLDRule(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing itemids",
+			Args:      map[string]interface{}{},
+			WantError: "itemids is required",
+		},
+		{
+			Name:     "delete",
+			Setup:    addRule,
+			Args:     map[string]interface{}{"itemids": "40001"},
+			Method:   "discoveryrule.delete",
+			Params:   `["40001"]`,
+			WantText: "LLD rules deleted",
+		},
+	})
+}
+
+func TestCopyLLDRule(t *testing.T) {
+	zabbixtest.RunToolCases(t, lld.CopyLLDRule(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing hostids",
+			Args:      map[string]interface{}{"discoveryids": "40001"},
+			WantError: "hostids is required",
+		},
+		{
+			Name:     "copy",
+			Setup:    addRule,
+			Args:     map[string]interface{}{"discoveryids": "40001", "hostids": "10085, 10086"},
+			Method:   "discoveryrule.copy",
+			Params:   `{"discoveryids":["40001"],"hostids":[{"hostid":"10085"},{"hostid":"10086"}]}`,
+			WantText: "LLD rules copied",
+		},
+	})
+}
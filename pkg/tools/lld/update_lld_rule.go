@@ -12,6 +12,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type LLDRuleUpdateParams struct {
@@ -27,6 +29,15 @@ type LLDRuleUpdateParams struct {
 func UpdateLLDRule(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("update_lld_rule",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update LLD Rule",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Update an existing low-level discovery rule in Zabbix."),
 			mcp.WithString("itemid", mcp.Description("LLD rule ID to update"), mcp.Required()),
 			mcp.WithString("name", mcp.Description("New name")),
@@ -37,7 +48,7 @@ func UpdateLLDRule(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("status", mcp.Description("Status: 0=enabled, 1=disabled")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return updateLLDRuleHandler(ctx, req, logger)
+			return updateLLDRuleHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -80,7 +91,7 @@ func updateLLDRuleHandler(ctx context.Context, req mcp.CallToolRequest, logger *
 		params.Status = &s
 	}
 
-	result, err := zabbix.Call("discoveryrule.update", params)
+	result, err := zabbix.CallContext(ctx, "discoveryrule.update", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update LLD rule: %v", err)), nil
 	}
@@ -12,6 +12,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type GlobalMacroCreateParams struct {
@@ -24,6 +26,15 @@ type GlobalMacroCreateParams struct {
 func CreateGlobalMacro(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("create_global_macro",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create Global Macro",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new global macro in Zabbix."),
 			mcp.WithString("macro", mcp.Description("Macro name (e.g., {$MYMACRO})"), mcp.Required()),
 			mcp.WithString("value", mcp.Description("Macro value"), mcp.Required()),
@@ -31,7 +42,7 @@ func CreateGlobalMacro(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("type", mcp.Description("Macro type: 0=text (default), 1=secret, 2=vault secret")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return createGlobalMacroHandler(ctx, req, logger)
+			return createGlobalMacroHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -66,7 +77,7 @@ func createGlobalMacroHandler(ctx context.Context, req mcp.CallToolRequest, logg
 		params.Type = int(v)
 	}
 
-	result, err := zabbix.Call("usermacro.createglobal", params)
+	result, err := zabbix.CallContext(ctx, "usermacro.createglobal", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create global macro: %v", err)), nil
 	}
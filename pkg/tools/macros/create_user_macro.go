@@ -12,6 +12,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type UserMacroCreateParams struct {
@@ -25,6 +27,15 @@ type UserMacroCreateParams struct {
 func CreateUserMacro(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("create_user_macro",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create User Macro",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new host-level user macro in Zabbix."),
 			mcp.WithString("hostid", mcp.Description("Host ID to create the macro for"), mcp.Required()),
 			mcp.WithString("macro", mcp.Description("Macro name (e.g., {$MYMACRO})"), mcp.Required()),
@@ -33,7 +44,7 @@ func CreateUserMacro(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("type", mcp.Description("Macro type: 0=text (default), 1=secret, 2=vault secret")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return createUserMacroHandler(ctx, req, logger)
+			return createUserMacroHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -70,7 +81,7 @@ func createUserMacroHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		params.Type = int(v)
 	}
 
-	result, err := zabbix.Call("usermacro.create", params)
+	result, err := zabbix.CallContext(ctx, "usermacro.create", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create user macro: %v", err)), nil
 	}
@@ -13,16 +13,27 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func DeleteGlobalMacro(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("delete_global_macro",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete Global Macro",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete global macros from Zabbix."),
 			mcp.WithString("globalmacroids", mcp.Description("Comma-separated list of global macro IDs to delete"), mcp.Required()),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return deleteGlobalMacroHandler(ctx, req, logger)
+			return deleteGlobalMacroHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -50,7 +61,7 @@ func deleteGlobalMacroHandler(ctx context.Context, req mcp.CallToolRequest, logg
 		}
 	}
 
-	result, err := zabbix.Call("usermacro.deleteglobal", globalmacroids)
+	result, err := zabbix.CallContext(ctx, "usermacro.deleteglobal", globalmacroids)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete global macros: %v", err)), nil
 	}
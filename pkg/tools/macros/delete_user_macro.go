@@ -13,16 +13,27 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func DeleteUserMacro(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("delete_user_macro",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete User Macro",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete host-level user macros from Zabbix."),
 			mcp.WithString("hostmacroids", mcp.Description("Comma-separated list of host macro IDs to delete"), mcp.Required()),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return deleteUserMacroHandler(ctx, req, logger)
+			return deleteUserMacroHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -50,7 +61,7 @@ func deleteUserMacroHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		}
 	}
 
-	result, err := zabbix.Call("usermacro.delete", hostmacroids)
+	result, err := zabbix.CallContext(ctx, "usermacro.delete", hostmacroids)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete user macros: %v", err)), nil
 	}
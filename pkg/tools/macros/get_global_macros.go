@@ -13,6 +13,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -28,14 +29,22 @@ type GlobalMacroGetParams struct {
 func GetGlobalMacros(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_global_macros",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Global Macros",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Retrieve global macros from Zabbix."),
 			mcp.WithString("globalmacroids", mcp.Description("Comma-separated list of global macro IDs to filter by")),
 			mcp.WithString("search", mcp.Description("Search macros by name")),
 			mcp.WithNumber("limit", mcp.Description("Max macros to return (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getGlobalMacrosHandler(ctx, req, logger)
+			return getGlobalMacrosHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -64,7 +73,7 @@ func getGlobalMacrosHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		}
 	}
 
-	result, err := zabbix.Call("usermacro.get", params)
+	result, err := zabbix.CallContext(ctx, "usermacro.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get global macros: %v", err)), nil
 	}
@@ -13,6 +13,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -43,7 +44,15 @@ type UserMacroGetParams struct {
 func GetUserMacros(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_user_macros",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get User Macros",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Retrieve host-level user macros from Zabbix."),
 			mcp.WithString("hostids", mcp.Description("Comma-separated list of host IDs to filter by")),
 			mcp.WithString("hostmacroids", mcp.Description("Comma-separated list of host macro IDs to filter by")),
@@ -53,7 +62,7 @@ func GetUserMacros(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("limit", mcp.Description("Max macros to return (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getUserMacrosHandler(ctx, req, logger)
+			return getUserMacrosHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -91,7 +100,7 @@ func getUserMacrosHandler(ctx context.Context, req mcp.CallToolRequest, logger *
 		}
 	}
 
-	result, err := zabbix.Call("usermacro.get", params)
+	result, err := zabbix.CallContext(ctx, "usermacro.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get user macros: %v", err)), nil
 	}
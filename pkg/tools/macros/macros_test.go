@@ -0,0 +1,207 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package macros_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/macros"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addMacros(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add(zabbixtest.UserMacros, zabbixtest.Object{"hostmacroid": "30", "hostid": "10084", "macro": "{$CPU.UTIL.CRIT}", "value": "90"})
+	s.Model.Add(zabbixtest.GlobalMacros, zabbixtest.Object{"globalmacroid": "2", "macro": "{$SNMP_COMMUNITY}", "value": "public"})
+}
+
+func TestGetUserMacros(t *testing.T) {
+	zabbixtest.RunToolCases(t, macros.GetUserMacros(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "usermacro.get",
+			Params: `{"output":"extend","selectHosts":"extend","limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"hostids": "10084", "templateids": "10001", "search": "CPU", "limit": float64(10)},
+			Method: "usermacro.get",
+			Params: `{"output":"extend","hostids":["10084"],"templateids":["10001"],"search":{"macro":"CPU"},"selectHosts":"extend","limit":10}`,
+		},
+		{
+			Name:  "returns host macros only",
+			Setup: addMacros,
+			Args:  map[string]interface{}{},
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				text := zabbixtest.ResultText(result)
+				if !strings.Contains(text, "{$CPU.UTIL.CRIT}") || strings.Contains(text, "{$SNMP_COMMUNITY}") {
+					t.Errorf("unexpected macros: %s", text)
+				}
+			},
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("usermacro.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Failed to get user macros",
+		},
+	})
+}
+
+func TestGetGlobalMacros(t *testing.T) {
+	zabbixtest.RunToolCases(t, macros.GetGlobalMacros(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "usermacro.get",
+			Params: `{"output":"extend","globalmacro":true,"limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"globalmacroids": "2, 3", "search": "SNMP", "limit": float64(1)},
+			Method: "usermacro.get",
+			Params: `{"output":"extend","globalmacro":true,"globalmacroids":["2","3"],"search":{"macro":"SNMP"},"limit":1}`,
+		},
+		{
+			Name:  "returns global macros only",
+			Setup: addMacros,
+			Args:  map[string]interface{}{},
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				text := zabbixtest.ResultText(result)
+				if !strings.Contains(text, "{$SNMP_COMMUNITY}") || strings.Contains(text, "{$CPU.UTIL.CRIT}") {
+					t.Errorf("unexpected macros: %s", text)
+				}
+			},
+		},
+	})
+}
+
+func TestCreateUserMacro(t *testing.T) {
+	zabbixtest.RunToolCases(t, macros.CreateUserMacro(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing value",
+			Args:      map[string]interface{}{"hostid": "10084", "macro": "{$A}"},
+			WantError: "hostid, macro, and value are required",
+		},
+		{
+			Name:     "secret macro",
+			Args:     map[string]interface{}{"hostid": "10084", "macro": "{$PASSWORD}", "value": "s3cret", "type": float64(1), "description": "db"},
+			Method:   "usermacro.create",
+			Params:   `{"hostid":"10084","macro":"{$PASSWORD}","value":"s3cret","description":"db","type":1}`,
+			WantText: "User macro created",
+		},
+	})
+}
+
+func TestCreateGlobalMacro(t *testing.T) {
+	zabbixtest.RunToolCases(t, macros.CreateGlobalMacro(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing value",
+			Args:      map[string]interface{}{"macro": "{$A}"},
+			WantError: "macro and value are required",
+		},
+		{
+			Name:     "create",
+			Args:     map[string]interface{}{"macro": "{$SNMP_COMMUNITY}", "value": "public"},
+			Method:   "usermacro.createglobal",
+			Params:   `{"macro":"{$SNMP_COMMUNITY}","value":"public"}`,
+			WantText: "Global macro created",
+		},
+	})
+}
+
+func TestUpdateUserMacro(t *testing.T) {
+	zabbixtest.RunToolCases(t, macros.UpdateUserMacro(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing hostmacroid",
+			Args:      map[string]interface{}{"value": "1"},
+			WantError: "hostmacroid is required",
+		},
+		{
+			Name:     "update",
+			Setup:    addMacros,
+			Args:     map[string]interface{}{"hostmacroid": "30", "value": "95", "type": float64(0)},
+			Method:   "usermacro.update",
+			Params:   `{"hostmacroid":"30","value":"95","type":0}`,
+			WantText: "User macro updated",
+		},
+		{
+			Name:     "reports changes",
+			Setup:    addMacros,
+			Args:     map[string]interface{}{"hostmacroid": "30", "value": "95"},
+			WantText: `"field": "value"`,
+		},
+		{
+			Name:  "redacts secret values",
+			Setup: addMacros,
+			Args:  map[string]interface{}{"hostmacroid": "30", "value": "s3cret", "type": float64(1)},
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				if text := zabbixtest.ResultText(result); strings.Contains(text, "s3cret") || !strings.Contains(text, `"after": "[REDACTED]"`) {
+					t.Errorf("expected the secret value to be redacted, got: %s", text)
+				}
+			},
+		},
+	})
+}
+
+func TestUpdateGlobalMacro(t *testing.T) {
+	zabbixtest.RunToolCases(t, macros.UpdateGlobalMacro(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing globalmacroid",
+			Args:      map[string]interface{}{"value": "1"},
+			WantError: "globalmacroid is required",
+		},
+		{
+			Name:     "update",
+			Setup:    addMacros,
+			Args:     map[string]interface{}{"globalmacroid": "2", "value": "private"},
+			Method:   "usermacro.updateglobal",
+			Params:   `{"globalmacroid":"2","value":"private"}`,
+			WantText: "Global macro updated",
+		},
+		{
+			Name:      "unknown macro",
+			Args:      map[string]interface{}{"globalmacroid": "99", "value": "x"},
+			WantError: "Failed to update global macro",
+		},
+	})
+}
+
+func TestDeleteUserMacro(t *testing.T) {
+	zabbixtest.RunToolCases(t, macros.DeleteUserMacro(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing hostmacroids",
+			Args:      map[string]interface{}{},
+			WantError: "hostmacroids is required",
+		},
+		{
+			Name:     "delete",
+			Setup:    addMacros,
+			Args:     map[string]interface{}{"hostmacroids": "30"},
+			Method:   "usermacro.delete",
+			Params:   `["30"]`,
+			WantText: "User macros deleted",
+		},
+	})
+}
+
+func TestDeleteGlobalMacro(t *testing.T) {
+	zabbixtest.RunToolCases(t, macros.DeleteGlobalMacro(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing globalmacroids",
+			Args:      map[string]interface{}{},
+			WantError: "globalmacroids is required",
+		},
+		{
+			Name:     "delete",
+			Setup:    addMacros,
+			Args:     map[string]interface{}{"globalmacroids": "2"},
+			Method:   "usermacro.deleteglobal",
+			Params:   `["2"]`,
+			WantText: "Global macros deleted",
+		},
+	})
+}
@@ -12,6 +12,9 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/diff"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type GlobalMacroUpdateParams struct {
@@ -25,7 +28,16 @@ type GlobalMacroUpdateParams struct {
 func UpdateGlobalMacro(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("update_global_macro",
-			mcp.WithDescription("Update an existing global macro in Zabbix."),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update Global Macro",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
+			mcp.WithDescription("Update an existing global macro in Zabbix. Returns the fields that changed, with their old and new values."),
 			mcp.WithString("globalmacroid", mcp.Description("Global macro ID to update"), mcp.Required()),
 			mcp.WithString("macro", mcp.Description("New macro name")),
 			mcp.WithString("value", mcp.Description("New macro value")),
@@ -33,11 +45,18 @@ func UpdateGlobalMacro(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("type", mcp.Description("Macro type: 0=text, 1=secret, 2=vault secret")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return updateGlobalMacroHandler(ctx, req, logger)
+			return updateGlobalMacroHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
 
+// globalMacroDiff fetches a global macro
+var globalMacroDiff = diff.Getter{
+	Method:   "usermacro.get",
+	IDsParam: "globalmacroids",
+	Params:   map[string]interface{}{"globalmacro": true},
+}
+
 func updateGlobalMacroHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	zabbix, err := client.GetZabbixClientFromContext(ctx, logger)
 	if err != nil {
@@ -70,17 +89,24 @@ func updateGlobalMacroHandler(ctx context.Context, req mcp.CallToolRequest, logg
 		params.Type = &t
 	}
 
-	result, err := zabbix.Call("usermacro.updateglobal", params)
+	update := globalMacroDiff.Track(ctx, zabbix, globalmacroid)
+
+	result, err := zabbix.CallContext(ctx, "usermacro.updateglobal", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update global macro: %v", err)), nil
 	}
 
 	var response map[string]interface{}
 	json.Unmarshal(result, &response)
-	jsonData, _ := json.MarshalIndent(map[string]interface{}{
+	responseData := map[string]interface{}{
 		"message":       "Global macro updated successfully",
 		"globalmacroid": globalmacroid,
 		"response":      response,
-	}, "", "  ")
+	}
+	if err := update.Report(ctx, responseData); err != nil {
+		logger.WithError(err).Warn("Failed to compare global macro after update")
+	}
+
+	jsonData, _ := json.MarshalIndent(responseData, "", "  ")
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
@@ -12,6 +12,9 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/diff"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type UserMacroUpdateParams struct {
@@ -25,7 +28,16 @@ type UserMacroUpdateParams struct {
 func UpdateUserMacro(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("update_user_macro",
-			mcp.WithDescription("Update an existing host-level user macro in Zabbix."),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update User Macro",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
+			mcp.WithDescription("Update an existing host-level user macro in Zabbix. Returns the fields that changed, with their old and new values."),
 			mcp.WithString("hostmacroid", mcp.Description("Host macro ID to update"), mcp.Required()),
 			mcp.WithString("macro", mcp.Description("New macro name")),
 			mcp.WithString("value", mcp.Description("New macro value")),
@@ -33,11 +45,17 @@ func UpdateUserMacro(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("type", mcp.Description("Macro type: 0=text, 1=secret, 2=vault secret")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return updateUserMacroHandler(ctx, req, logger)
+			return updateUserMacroHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
 
+// userMacroDiff fetches a host or template macro
+var userMacroDiff = diff.Getter{
+	Method:   "usermacro.get",
+	IDsParam: "hostmacroids",
+}
+
 func updateUserMacroHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	zabbix, err := client.GetZabbixClientFromContext(ctx, logger)
 	if err != nil {
@@ -70,17 +88,24 @@ func updateUserMacroHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		params.Type = &t
 	}
 
-	result, err := zabbix.Call("usermacro.update", params)
+	update := userMacroDiff.Track(ctx, zabbix, hostmacroid)
+
+	result, err := zabbix.CallContext(ctx, "usermacro.update", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update user macro: %v", err)), nil
 	}
 
 	var response map[string]interface{}
 	json.Unmarshal(result, &response)
-	jsonData, _ := json.MarshalIndent(map[string]interface{}{
+	responseData := map[string]interface{}{
 		"message":     "User macro updated successfully",
 		"hostmacroid": hostmacroid,
 		"response":    response,
-	}, "", "  ")
+	}
+	if err := update.Report(ctx, responseData); err != nil {
+		logger.WithError(err).Warn("Failed to compare user macro after update")
+	}
+
+	jsonData, _ := json.MarshalIndent(responseData, "", "  ")
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
@@ -14,11 +14,22 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func CreateMaintenance(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("create_maintenance",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create Maintenance",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a maintenance period."),
 			mcp.WithString("name", mcp.Required(), mcp.Description("Maintenance name")),
 			mcp.WithString("active_since", mcp.Required(), mcp.Description("Start time (Unix timestamp or RFC3339)")),
@@ -30,7 +41,7 @@ func CreateMaintenance(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("maintenance_type", mcp.Description("Type: 0=with data, 1=without")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return createMaintenanceHandler(ctx, req, logger)
+			return createMaintenanceHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -84,7 +95,7 @@ func createMaintenanceHandler(ctx context.Context, req mcp.CallToolRequest, logg
 		params.MaintenanceType = int(v)
 	}
 
-	result, err := zabbix.Call("maintenance.create", params)
+	result, err := zabbix.CallContext(ctx, "maintenance.create", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed: %v", err)), nil
 	}
@@ -12,16 +12,29 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/confirm"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func DeleteMaintenance(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("delete_maintenance",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete Maintenance",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete maintenance periods."),
 			mcp.WithString("maintenanceids", mcp.Required(), mcp.Description("Comma-separated maintenance IDs")),
+			confirm.WithConfirm(),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return deleteMaintenanceHandler(ctx, req, logger)
+			return deleteMaintenanceHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -42,7 +55,47 @@ func deleteMaintenanceHandler(ctx context.Context, req mcp.CallToolRequest, logg
 		return mcp.NewToolResultError("maintenanceids required"), nil
 	}
 
-	result, err := zabbix.Call("maintenance.delete", splitAndTrim(maintenanceidsStr))
+	maintenanceids := splitAndTrim(maintenanceidsStr)
+
+	// The client sends selectHostGroups as selectGroups to Zabbix before 6.2
+	result, err := zabbix.CallContext(ctx, "maintenance.get", map[string]interface{}{
+		"output":           []string{"maintenanceid", "name"},
+		"maintenanceids":   maintenanceids,
+		"selectHosts":      []string{"hostid"},
+		"selectHostGroups": []string{"groupid"},
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to resolve maintenance: %v", err)), nil
+	}
+
+	var maintenances []struct {
+		MaintenanceID string            `json:"maintenanceid"`
+		Name          string            `json:"name"`
+		Hosts         []json.RawMessage `json:"hosts"`
+		HostGroups    []json.RawMessage `json:"hostgroups"`
+	}
+	if err := json.Unmarshal(result, &maintenances); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse maintenance: %v", err)), nil
+	}
+
+	found := make([]confirm.Object, 0, len(maintenances))
+	for _, m := range maintenances {
+		found = append(found, confirm.Object{
+			ID:      m.MaintenanceID,
+			Name:    m.Name,
+			Details: confirm.Plural(len(m.Hosts), "host", "hosts") + ", " + confirm.Plural(len(m.HostGroups), "host group", "host groups"),
+		})
+	}
+
+	if res := confirm.Request(ctx, req, confirm.Operation{
+		Action:  "Delete " + confirm.Plural(len(maintenanceids), "maintenance period", "maintenance periods"),
+		Objects: confirm.Resolve(maintenanceids, found),
+		Warning: "Hosts covered by these periods will leave maintenance immediately.",
+	}, logger); res != nil {
+		return res, nil
+	}
+
+	result, err = zabbix.CallContext(ctx, "maintenance.delete", maintenanceids)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed: %v", err)), nil
 	}
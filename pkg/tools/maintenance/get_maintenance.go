@@ -12,6 +12,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -27,14 +28,22 @@ type Maintenance struct {
 func GetMaintenance(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_maintenance",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Maintenance",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("List maintenance periods from Zabbix."),
 			mcp.WithString("maintenanceids", mcp.Description("Comma-separated maintenance IDs")),
 			mcp.WithString("hostids", mcp.Description("Comma-separated host IDs")),
 			mcp.WithNumber("limit", mcp.Description("Max records (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getMaintenanceHandler(ctx, req, logger)
+			return getMaintenanceHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -59,7 +68,7 @@ func getMaintenanceHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		}
 	}
 
-	result, err := zabbix.Call("maintenance.get", params)
+	result, err := zabbix.CallContext(ctx, "maintenance.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed: %v", err)), nil
 	}
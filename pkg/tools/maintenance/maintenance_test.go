@@ -0,0 +1,177 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package maintenance_test
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/maintenance"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addMaintenance(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add("maintenance", zabbixtest.Object{
+		"maintenanceid": "3",
+		"name":          "Patch window",
+		"hosts":         []interface{}{map[string]interface{}{"hostid": "10084"}, map[string]interface{}{"hostid": "10085"}},
+		"hostgroups":    []interface{}{map[string]interface{}{"groupid": "2"}},
+	})
+}
+
+func TestGetMaintenance(t *testing.T) {
+	zabbixtest.RunToolCases(t, maintenance.GetMaintenance(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "maintenance.get",
+			Params: `{"output":"extend","selectHosts":"extend","selectTimeperiods":"extend","limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"maintenanceids": "3", "hostids": "10084", "limit": float64(1)},
+			Method: "maintenance.get",
+			Params: `{"output":"extend","maintenanceids":["3"],"hostids":["10084"],"selectHosts":"extend","selectTimeperiods":"extend","limit":1}`,
+		},
+		{
+			Name:     "returns model maintenance",
+			Setup:    addMaintenance,
+			Args:     map[string]interface{}{},
+			WantText: "Patch window",
+		},
+	})
+}
+
+func TestCreateMaintenance(t *testing.T) {
+	zabbixtest.RunToolCases(t, maintenance.CreateMaintenance(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing active_till",
+			Args:      map[string]interface{}{"name": "Patch", "active_since": "1700000000"},
+			WantError: "name, active_since, and active_till are required",
+		},
+		{
+			Name:      "invalid time",
+			Args:      map[string]interface{}{"name": "Patch", "active_since": "tomorrow", "active_till": "1700003600"},
+			WantError: "Invalid time format",
+		},
+		{
+			Name:   "unix timestamps",
+			Args:   map[string]interface{}{"name": "Patch", "active_since": "1700000000", "active_till": "1700003600", "hostids": "10084"},
+			Method: "maintenance.create",
+			Params: `{"name":"Patch","active_since":1700000000,"active_till":1700003600,"hostids":["10084"],"timeperiods":[{"timeperiod_type":0,"start_date":1700000000,"period":3600}]}`,
+		},
+		{
+			Name: "rfc3339 and options",
+			Args: map[string]interface{}{
+				"name": "Patch", "active_since": "2023-11-14T22:13:20Z", "active_till": "2023-11-15T00:00:00Z",
+				"groupids": "2,3", "period": float64(7200), "description": "kernel", "maintenance_type": float64(1),
+			},
+			Method:   "maintenance.create",
+			Params:   `{"name":"Patch","active_since":1700000000,"active_till":1700006400,"groupids":["2","3"],"timeperiods":[{"timeperiod_type":0,"start_date":1700000000,"period":7200}],"description":"kernel","maintenance_type":1}`,
+			WantText: "Maintenance created",
+		},
+	})
+}
+
+func TestUpdateMaintenance(t *testing.T) {
+	zabbixtest.RunToolCases(t, maintenance.UpdateMaintenance(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing maintenanceid",
+			Args:      map[string]interface{}{"name": "x"},
+			WantError: "maintenanceid required",
+		},
+		{
+			Name:     "extend",
+			Setup:    addMaintenance,
+			Args:     map[string]interface{}{"maintenanceid": "3", "name": "Patch window 2", "active_till": "1700007200", "description": "extended"},
+			Method:   "maintenance.update",
+			Params:   `{"maintenanceid":"3","name":"Patch window 2","active_till":1700007200,"description":"extended"}`,
+			WantText: "Maintenance updated",
+		},
+		{
+			Name:   "invalid active_till is ignored",
+			Setup:  addMaintenance,
+			Args:   map[string]interface{}{"maintenanceid": "3", "active_till": "soon"},
+			Method: "maintenance.update",
+			Params: `{"maintenanceid":"3"}`,
+		},
+		{
+			Name:  "reports changes",
+			Setup: addMaintenance,
+			Args:  map[string]interface{}{"maintenanceid": "3", "name": "Patch window 2"},
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				zabbixtest.AssertChanges(t, result, `[{"field":"name","change":"changed","before":"Patch window","after":"Patch window 2"}]`)
+			},
+		},
+		{
+			Name: "zabbix 6.0 reports changes",
+			Setup: func(t *testing.T, s *zabbixtest.Server) {
+				s.Version = "6.0.30"
+				s.Model.Add("maintenance", zabbixtest.Object{
+					"maintenanceid": "3",
+					"name":          "Patch window",
+					"groups":        []interface{}{map[string]interface{}{"groupid": "2"}},
+				})
+			},
+			Args: map[string]interface{}{"maintenanceid": "3", "name": "Patch window 2"},
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				zabbixtest.AssertChanges(t, result, `[{"field":"name","change":"changed","before":"Patch window","after":"Patch window 2"}]`)
+			},
+		},
+		{
+			Name: "diff unavailable",
+			Setup: func(t *testing.T, s *zabbixtest.Server) {
+				addMaintenance(t, s)
+				zabbixtest.Failing("maintenance.get")(t, s)
+			},
+			Args:     map[string]interface{}{"maintenanceid": "3", "name": "Patch window 2"},
+			Method:   "maintenance.update",
+			WantText: `"changes_unavailable": "maintenance.get before the update failed`,
+		},
+	})
+}
+
+func TestDeleteMaintenance(t *testing.T) {
+	zabbixtest.RunToolCases(t, maintenance.DeleteMaintenance(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing maintenanceids",
+			Args:      map[string]interface{}{},
+			WantError: "maintenanceids required",
+		},
+		{
+			Name:      "requires confirmation",
+			Setup:     addMaintenance,
+			Args:      map[string]interface{}{"maintenanceids": "3"},
+			Method:    "maintenance.get",
+			Params:    `{"output":["maintenanceid","name"],"maintenanceids":["3"],"selectHosts":["hostid"],"selectHostGroups":["groupid"]}`,
+			WantError: `"details": "2 hosts, 1 host group"`,
+			Check: func(t *testing.T, s *zabbixtest.Server, _ *mcp.CallToolResult) {
+				s.AssertNotCalled(t, "maintenance.delete")
+			},
+		},
+		{
+			Name: "zabbix 6.0 selects groups",
+			Setup: func(t *testing.T, s *zabbixtest.Server) {
+				s.Version = "6.0.30"
+				s.Model.Add("maintenance", zabbixtest.Object{
+					"maintenanceid": "3",
+					"name":          "Patch window",
+					"groups":        []interface{}{map[string]interface{}{"groupid": "2"}, map[string]interface{}{"groupid": "4"}},
+				})
+			},
+			Args:      map[string]interface{}{"maintenanceids": "3"},
+			Method:    "maintenance.get",
+			Params:    `{"output":["maintenanceid","name"],"maintenanceids":["3"],"selectHosts":["hostid"],"selectGroups":["groupid"]}`,
+			WantError: `"details": "0 hosts, 2 host groups"`,
+		},
+		{
+			Name:     "confirmed",
+			Setup:    addMaintenance,
+			Args:     map[string]interface{}{"maintenanceids": "3", "confirm": true},
+			Method:   "maintenance.delete",
+			Params:   `["3"]`,
+			WantText: "Maintenance deleted",
+		},
+	})
+}
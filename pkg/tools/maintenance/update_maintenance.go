@@ -12,23 +12,48 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/diff"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func UpdateMaintenance(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("update_maintenance",
-			mcp.WithDescription("Update a maintenance period."),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update Maintenance",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
+			mcp.WithDescription("Update a maintenance period. Returns the fields that changed, with their old and new values."),
 			mcp.WithString("maintenanceid", mcp.Required(), mcp.Description("Maintenance ID")),
 			mcp.WithString("name", mcp.Description("New name")),
 			mcp.WithString("active_till", mcp.Description("New end time")),
 			mcp.WithString("description", mcp.Description("New description")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return updateMaintenanceHandler(ctx, req, logger)
+			return updateMaintenanceHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
 
+// maintenanceDiff fetches a maintenance with its hosts, groups, time
+// periods and problem tags
+var maintenanceDiff = diff.Getter{
+	Method:   "maintenance.get",
+	IDsParam: "maintenanceids",
+	Params: map[string]interface{}{
+		"selectHosts":       []string{"hostid", "host"},
+		"selectHostGroups":  []string{"groupid", "name"},
+		"selectTimeperiods": "extend",
+		"selectTags":        "extend",
+	},
+}
+
 func updateMaintenanceHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	zabbix, err := client.GetZabbixClientFromContext(ctx, logger)
 	if err != nil {
@@ -58,7 +83,9 @@ func updateMaintenanceHandler(ctx context.Context, req mcp.CallToolRequest, logg
 		params.Description = v
 	}
 
-	result, err := zabbix.Call("maintenance.update", params)
+	update := maintenanceDiff.Track(ctx, zabbix, maintenanceid)
+
+	result, err := zabbix.CallContext(ctx, "maintenance.update", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed: %v", err)), nil
 	}
@@ -67,6 +94,11 @@ func updateMaintenanceHandler(ctx context.Context, req mcp.CallToolRequest, logg
 		MaintenanceIDs []string `json:"maintenanceids"`
 	}
 	json.Unmarshal(result, &response)
-	jsonData, _ := json.MarshalIndent(map[string]interface{}{"message": "Maintenance updated", "maintenanceids": response.MaintenanceIDs}, "", "  ")
+	responseData := map[string]interface{}{"message": "Maintenance updated", "maintenanceids": response.MaintenanceIDs}
+	if err := update.Report(ctx, responseData); err != nil {
+		logger.WithError(err).Warn("Failed to compare maintenance after update")
+	}
+
+	jsonData, _ := json.MarshalIndent(responseData, "", "  ")
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
@@ -0,0 +1,106 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpaudit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/vfcastr/Zabbix-MCP/pkg/audit"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// GetMCPAuditLog creates a tool for querying the MCP server's audit trail
+// of mutating tool calls
+func GetMCPAuditLog(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("get_mcp_audit_log",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get MCP Audit Log",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
+			mcp.WithDescription("Retrieve the MCP server's audit trail of mutating tool calls, newest first: the MCP session and authenticated principal that made each change, the tool and its arguments (secrets redacted), the Zabbix methods called with the IDs they returned, and the outcome. Unlike get_audit_log, it shows which session made a change rather than only the Zabbix API user."),
+			mcp.WithString("session_id", mcp.Description("Only calls made by this MCP session")),
+			mcp.WithString("principal", mcp.Description("Only calls made by this authenticated principal. With HTTP authentication, defaults to the caller, and only admin keys may read other principals.")),
+			mcp.WithString("tool", mcp.Description("Only calls of this tool, e.g. update_host")),
+			mcp.WithString("status", mcp.Description("Only calls with this outcome"), mcp.Enum(audit.StatusSuccess, audit.StatusFailure)),
+			mcp.WithNumber("time_from", mcp.Description("Unix timestamp - return only calls made at or after this time")),
+			mcp.WithNumber("time_till", mcp.Description("Unix timestamp - return only calls made at or before this time")),
+			mcp.WithNumber("limit", mcp.Description("Max records to return (default: 100, max: 1000)")),
+		),
+		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return getMCPAuditLogHandler(ctx, req, logging.FromContext(ctx, logger))
+		},
+	}
+}
+
+func getMCPAuditLogHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	logger.Debug("Handling get_mcp_audit_log request")
+
+	auditLog := audit.CurrentLog()
+	if auditLog == nil {
+		return mcp.NewToolResultText("The MCP audit log is disabled; set MCP_AUDIT_FILE to enable it."), nil
+	}
+
+	query := audit.Query{Limit: defaultLimit}
+	args := req.GetArguments()
+	query.SessionID, _ = args["session_id"].(string)
+	query.Principal, _ = args["principal"].(string)
+	query.Tool, _ = args["tool"].(string)
+	query.Status, _ = args["status"].(string)
+	// Authenticated callers only read their own records unless they are admins
+	if principal := client.PrincipalFromContext(ctx); principal != "" && !client.IsAdmin(ctx) {
+		if query.Principal != "" && query.Principal != principal {
+			return mcp.NewToolResultError(fmt.Sprintf("Only admin keys may read the audit records of other principals; %q can read its own", principal)), nil
+		}
+		query.Principal = principal
+	}
+	if query.Status != "" && query.Status != audit.StatusSuccess && query.Status != audit.StatusFailure {
+		return mcp.NewToolResultError(fmt.Sprintf("status must be %s or %s", audit.StatusSuccess, audit.StatusFailure)), nil
+	}
+	if v, ok := args["time_from"].(float64); ok && v > 0 {
+		query.From = time.Unix(int64(v), 0)
+	}
+	if v, ok := args["time_till"].(float64); ok && v > 0 {
+		query.Till = time.Unix(int64(v), 0)
+	}
+	if v, ok := args["limit"].(float64); ok && v > 0 {
+		query.Limit = min(int(v), maxLimit)
+	}
+
+	records, err := auditLog.Query(query)
+	if err != nil {
+		logger.WithError(err).Error("Failed to query the MCP audit log")
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query the MCP audit log: %v", err)), nil
+	}
+	if records == nil {
+		records = []audit.Record{}
+	}
+
+	jsonData, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		logger.WithError(err).Error("Failed to marshal audit records to JSON")
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling JSON: %v", err)), nil
+	}
+
+	logger.WithField("record_count", len(records)).Debug("Successfully queried the MCP audit log")
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
@@ -0,0 +1,126 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpaudit_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vfcastr/Zabbix-MCP/pkg/audit"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/mcpaudit"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+// withRecords enables auditing with a few recorded calls
+func withRecords(t *testing.T, s *zabbixtest.Server) {
+	auditLog, err := audit.Open(audit.Config{File: filepath.Join(t.TempDir(), "audit.jsonl")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit.SetLog(auditLog)
+	t.Cleanup(func() {
+		audit.SetLog(nil)
+		auditLog.Close()
+	})
+
+	start := time.Unix(1700000000, 0).UTC()
+	for i, record := range []audit.Record{
+		{SessionID: "s1", Principal: "alice", Tool: "update_host", Status: audit.StatusSuccess,
+			Calls: []audit.Call{{Method: "host.update", IDs: map[string][]string{"hostids": {"10084"}}}}},
+		{SessionID: "s2", Principal: "bob", Tool: "delete_host", Status: audit.StatusFailure, Error: "No permissions"},
+		{SessionID: "s1", Principal: "alice", Tool: "create_user_macro", Status: audit.StatusSuccess},
+	} {
+		record.Time = start.Add(time.Duration(i) * time.Hour)
+		if err := auditLog.Write(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGetMCPAuditLog(t *testing.T) {
+	zabbixtest.RunToolCases(t, mcpaudit.GetMCPAuditLog(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:     "disabled",
+			Args:     map[string]interface{}{},
+			WantText: "MCP audit log is disabled",
+		},
+		{
+			Name:  "newest first",
+			Setup: withRecords,
+			Args:  map[string]interface{}{"limit": float64(2)},
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				zabbixtest.AssertJSON(t, []byte(zabbixtest.ResultText(result)), `[
+					{"time":"2023-11-15T00:13:20Z","session_id":"s1","principal":"alice","tool":"create_user_macro","status":"success","duration_ms":0},
+					{"time":"2023-11-14T23:13:20Z","session_id":"s2","principal":"bob","tool":"delete_host","status":"failure","error":"No permissions","duration_ms":0}
+				]`)
+			},
+		},
+		{
+			Name:  "filters",
+			Setup: withRecords,
+			Args:  map[string]interface{}{"principal": "alice", "status": "success", "time_till": float64(1700000000 + 1800)},
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				zabbixtest.AssertJSON(t, []byte(zabbixtest.ResultText(result)), `[
+					{"time":"2023-11-14T22:13:20Z","session_id":"s1","principal":"alice","tool":"update_host","status":"success","duration_ms":0,
+					 "zabbix_calls":[{"method":"host.update","ids":{"hostids":["10084"]}}]}
+				]`)
+			},
+		},
+		{
+			Name:      "invalid status",
+			Setup:     withRecords,
+			Args:      map[string]interface{}{"status": "ok"},
+			WantError: "status must be success or failure",
+		},
+	})
+}
+
+func TestGetMCPAuditLogScopesPrincipals(t *testing.T) {
+	s := zabbixtest.NewServer(t)
+	withRecords(t, s)
+	tool := mcpaudit.GetMCPAuditLog(zabbixtest.Logger())
+	alice := client.WithPrincipal(s.Context(t), "alice")
+
+	principals := func(ctx context.Context, args map[string]interface{}) string {
+		t.Helper()
+		result := s.CallTool(t, ctx, tool, args)
+		if result.IsError {
+			t.Fatalf("unexpected error: %s", zabbixtest.ResultText(result))
+		}
+		var seen []string
+		for _, principal := range []string{"alice", "bob"} {
+			if strings.Contains(zabbixtest.ResultText(result), `"principal": "`+principal+`"`) {
+				seen = append(seen, principal)
+			}
+		}
+		return strings.Join(seen, ",")
+	}
+
+	// Authenticated callers default to their own records
+	if got := principals(alice, map[string]interface{}{}); got != "alice" {
+		t.Errorf("expected only alice's records, got %q", got)
+	}
+	if got := principals(alice, map[string]interface{}{"principal": "alice"}); got != "alice" {
+		t.Errorf("expected alice's records, got %q", got)
+	}
+
+	result := s.CallTool(t, alice, tool, map[string]interface{}{"principal": "bob"})
+	if !result.IsError || !strings.Contains(zabbixtest.ResultText(result), "Only admin keys") {
+		t.Errorf("expected bob's records to be refused, got %s", zabbixtest.ResultText(result))
+	}
+
+	// Admins read every principal
+	admin := client.WithAdmin(client.WithPrincipal(s.Context(t), "auditor"))
+	if got := principals(admin, map[string]interface{}{}); got != "alice,bob" {
+		t.Errorf("expected every record for an admin, got %q", got)
+	}
+	if got := principals(admin, map[string]interface{}{"principal": "bob"}); got != "bob" {
+		t.Errorf("expected bob's records for an admin, got %q", got)
+	}
+}
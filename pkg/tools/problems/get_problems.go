@@ -7,12 +7,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/federation"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -30,6 +34,9 @@ type Problem struct {
 	Suppressed   string       `json:"suppressed"`
 	OpData       string       `json:"opdata"`
 	Tags         []client.Tag `json:"tags,omitempty"`
+	// Instance is the named instance the record came from in a federated
+	// query
+	Instance string `json:"instance,omitempty"`
 }
 
 type ProblemGetParams struct {
@@ -54,7 +61,15 @@ type ProblemGetParams struct {
 func GetProblems(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_problems",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Problems",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Retrieve problems according to the given parameters. Problems are sorted by severity and time in descending order by default."),
 			mcp.WithString("eventids", mcp.Description("Comma-separated list of event IDs to filter by")),
 			mcp.WithString("groupids", mcp.Description("Comma-separated list of host group IDs to filter by")),
@@ -69,15 +84,15 @@ func GetProblems(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("limit", mcp.Description("Max problems to return (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getProblemsHandler(ctx, req, logger)
+			return getProblemsHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
 
 func getProblemsHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
-	zabbix, err := client.GetZabbixClientFromContext(ctx, logger)
+	targets, err := federation.Targets(req)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get Zabbix client: %v", err)), nil
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
 	params := ProblemGetParams{
@@ -124,7 +139,27 @@ func getProblemsHandler(ctx context.Context, req mcp.CallToolRequest, logger *lo
 		}
 	}
 
-	result, err := zabbix.Call("problem.get", params)
+	if targets != nil {
+		merged := federation.Query(ctx, targets, "problem.get", params, func(p *Problem, instance string) { p.Instance = instance }, logger)
+		// Most severe first, then newest first, so the limit keeps the most
+		// severe problems of every instance
+		sort.SliceStable(merged.Results, func(i, j int) bool {
+			a, b := merged.Results[i], merged.Results[j]
+			if x, y := severityOf(a), severityOf(b); x != y {
+				return x > y
+			}
+			return federation.Newer(a.Clock, b.Clock)
+		})
+		merged.Truncate(params.Limit)
+		return federation.ToolResult(merged), nil
+	}
+
+	zabbix, err := client.GetZabbixClientFromContext(ctx, logger)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get Zabbix client: %v", err)), nil
+	}
+
+	result, err := zabbix.CallContext(ctx, "problem.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get problems: %v", err)), nil
 	}
@@ -135,6 +170,12 @@ func getProblemsHandler(ctx context.Context, req mcp.CallToolRequest, logger *lo
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
 
+// severityOf returns the numeric severity of a problem
+func severityOf(p Problem) int {
+	severity, _ := strconv.Atoi(p.Severity)
+	return severity
+}
+
 func splitAndTrim(s string) []string {
 	var result []string
 	for _, part := range strings.Split(s, ",") {
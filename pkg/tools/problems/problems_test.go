@@ -0,0 +1,141 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package problems_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/problems"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func TestGetProblems(t *testing.T) {
+	zabbixtest.RunToolCases(t, problems.GetProblems(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "problem.get",
+			Params: `{"output":"extend","selectTags":"extend","sortfield":["eventid"],"sortorder":["DESC"],"limit":100}`,
+		},
+		{
+			Name: "filters",
+			Args: map[string]interface{}{
+				"groupids": "2", "acknowledged": false, "suppressed": false, "recent": true,
+				"severities": "3,4", "time_from": float64(1700000000), "time_till": float64(1700003600), "limit": float64(5),
+			},
+			Method: "problem.get",
+			Params: `{"output":"extend","groupids":["2"],"acknowledged":false,"suppressed":false,"recent":true,"severities":[3,4],"time_from":1700000000,"time_till":1700003600,"selectTags":"extend","sortfield":["eventid"],"sortorder":["DESC"],"limit":5}`,
+		},
+		{
+			Name: "returns model problems",
+			Setup: func(t *testing.T, s *zabbixtest.Server) {
+				s.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": "501", "objectid": "16001", "name": "High CPU on web01", "severity": "4"})
+			},
+			Args:     map[string]interface{}{"objectids": "16001"},
+			WantText: "High CPU on web01",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("problem.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Failed to get problems",
+		},
+	})
+}
+
+func TestGetProblemsAcrossInstances(t *testing.T) {
+	eu := zabbixtest.NewServer(t)
+	us := zabbixtest.NewServer(t)
+	eu.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": "501", "clock": "1700000100", "name": "Disk full on eu-db01"})
+	us.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": "77", "clock": "1700000200", "name": "High CPU on us-web01"})
+	client.SetInstances(client.InstancesConfig{Instances: []client.Instance{
+		{Name: "eu", URL: eu.APIURL(), Token: zabbixtest.Token},
+		{Name: "us", URL: us.APIURL(), Token: zabbixtest.Token},
+	}})
+	t.Cleanup(func() { client.SetInstances(client.InstancesConfig{}) })
+
+	home := zabbixtest.NewServer(t)
+	result := home.CallTool(t, home.Context(t), problems.GetProblems(zabbixtest.Logger()), map[string]interface{}{"instances": "all", "severities": "4"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", zabbixtest.ResultText(result))
+	}
+
+	// Merged problems are sorted newest first, whatever their instance
+	var merged struct {
+		Results []problems.Problem `json:"results"`
+	}
+	zabbixtest.DecodeResult(t, result, &merged)
+	if len(merged.Results) != 2 || merged.Results[0].Instance != "us" || merged.Results[1].Instance != "eu" {
+		t.Fatalf("unexpected merged problems: %+v", merged.Results)
+	}
+	for _, s := range []*zabbixtest.Server{eu, us} {
+		req, _ := s.LastCall("problem.get")
+		zabbixtest.AssertJSON(t, req.Params, `{"output":"extend","severities":[4],"selectTags":"extend","sortfield":["eventid"],"sortorder":["DESC"],"limit":100}`)
+	}
+	home.AssertNotCalled(t, "problem.get")
+}
+
+func TestGetProblemsAcrossInstancesLimitsMergedResults(t *testing.T) {
+	eu := zabbixtest.NewServer(t)
+	us := zabbixtest.NewServer(t)
+	eu.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": "502", "clock": "1700000400", "name": "Disk full on eu-db01"})
+	eu.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": "501", "clock": "1700000100", "name": "Disk full on eu-db02"})
+	us.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": "78", "clock": "1700000300", "name": "High CPU on us-web01"})
+	us.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": "77", "clock": "1700000200", "name": "High CPU on us-web02"})
+	client.SetInstances(client.InstancesConfig{Instances: []client.Instance{
+		{Name: "eu", URL: eu.APIURL(), Token: zabbixtest.Token},
+		{Name: "us", URL: us.APIURL(), Token: zabbixtest.Token},
+	}})
+	t.Cleanup(func() { client.SetInstances(client.InstancesConfig{}) })
+
+	home := zabbixtest.NewServer(t)
+	result := home.CallTool(t, home.Context(t), problems.GetProblems(zabbixtest.Logger()), map[string]interface{}{"instances": "all", "limit": float64(2)})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", zabbixtest.ResultText(result))
+	}
+
+	// The limit caps the merged problems, keeping the newest of any instance
+	var merged struct {
+		Results []problems.Problem `json:"results"`
+	}
+	zabbixtest.DecodeResult(t, result, &merged)
+	if len(merged.Results) != 2 || merged.Results[0].EventID != "502" || merged.Results[1].EventID != "78" {
+		t.Fatalf("expected the two newest problems, got %+v", merged.Results)
+	}
+}
+
+func TestGetProblemsAcrossInstancesSortsBySeverity(t *testing.T) {
+	eu := zabbixtest.NewServer(t)
+	us := zabbixtest.NewServer(t)
+	eu.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": "502", "clock": "1700000400", "severity": "2", "name": "Agent unreachable on eu-db01"})
+	eu.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": "501", "clock": "1700000100", "severity": "5", "name": "Disk full on eu-db02"})
+	us.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": "78", "clock": "1700000300", "severity": "4", "name": "High CPU on us-web01"})
+	us.Model.Add(zabbixtest.Problems, zabbixtest.Object{"eventid": "77", "clock": "1700000200", "severity": "5", "name": "Service down on us-web02"})
+	client.SetInstances(client.InstancesConfig{Instances: []client.Instance{
+		{Name: "eu", URL: eu.APIURL(), Token: zabbixtest.Token},
+		{Name: "us", URL: us.APIURL(), Token: zabbixtest.Token},
+	}})
+	t.Cleanup(func() { client.SetInstances(client.InstancesConfig{}) })
+
+	home := zabbixtest.NewServer(t)
+	result := home.CallTool(t, home.Context(t), problems.GetProblems(zabbixtest.Logger()), map[string]interface{}{"instances": "all", "limit": float64(3)})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", zabbixtest.ResultText(result))
+	}
+
+	// The most severe problems are kept, newest first within a severity
+	var merged struct {
+		Results []problems.Problem `json:"results"`
+	}
+	zabbixtest.DecodeResult(t, result, &merged)
+	var got []string
+	for _, p := range merged.Results {
+		got = append(got, p.Instance+"/"+p.EventID)
+	}
+	if want := "us/77 eu/501 us/78"; strings.Join(got, " ") != want {
+		t.Errorf("expected %s, got %v", want, got)
+	}
+}
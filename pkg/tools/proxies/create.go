@@ -12,6 +12,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -19,6 +20,15 @@ import (
 func CreateProxy(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("zabbix_create_proxy",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create Proxy",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new proxy in Zabbix."),
 			mcp.WithString("name",
 				mcp.Description("Name of the proxy"),
@@ -72,7 +82,7 @@ func CreateProxy(logger *log.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return createProxyHandler(ctx, req, logger)
+			return createProxyHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -156,7 +166,12 @@ func createProxyHandler(ctx context.Context, req mcp.CallToolRequest, logger *lo
 		}
 	}
 
-	result, err := zabbix.Call("proxy.create", params)
+	callParams, err := proxyParams(zabbix, params)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result, err := zabbix.CallContext(ctx, "proxy.create", callParams)
 	if err != nil {
 		logger.WithError(err).Error("Failed to create proxy")
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create proxy: %v", err)), nil
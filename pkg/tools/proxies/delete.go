@@ -12,6 +12,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -19,6 +20,15 @@ import (
 func DeleteProxies(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("zabbix_delete_proxies",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete Proxies",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete one or more proxies from the Zabbix server."),
 			mcp.WithString("proxyids",
 				mcp.Description("Comma-separated list of proxy IDs to delete"),
@@ -26,7 +36,7 @@ func DeleteProxies(logger *log.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return deleteProxiesHandler(ctx, req, logger)
+			return deleteProxiesHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -53,7 +63,7 @@ func deleteProxiesHandler(ctx context.Context, req mcp.CallToolRequest, logger *
 
 	proxyIDs := utils.SplitAndTrim(proxyIDsStr)
 
-	result, err := zabbix.Call("proxy.delete", proxyIDs)
+	result, err := zabbix.CallContext(ctx, "proxy.delete", proxyIDs)
 	if err != nil {
 		logger.WithError(err).Error("Failed to delete proxies")
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete proxies: %v", err)), nil
@@ -12,6 +12,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -21,7 +22,11 @@ func GetProxies(logger *log.Logger) server.ServerTool {
 		Tool: mcp.NewTool("zabbix_get_proxies",
 			mcp.WithToolAnnotation(
 				mcp.ToolAnnotation{
-					IdempotentHint: utils.ToBoolPtr(true),
+					Title:           "Get Proxies",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
 				},
 			),
 			mcp.WithDescription("Retrieve all configured proxies. Can filter by proxy IDs, proxy group IDs, or search term."),
@@ -39,7 +44,7 @@ func GetProxies(logger *log.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getProxiesHandler(ctx, req, logger)
+			return getProxiesHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -84,8 +89,19 @@ func getProxiesHandler(ctx context.Context, req mcp.CallToolRequest, logger *log
 		params.Limit = 100
 	}
 
+	// Zabbix 6.x names the proxy fields differently
+	var callParams interface{} = params
+	if legacyProxies(zabbix) {
+		legacy, err := legacyProxyParams(params)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		legacy["selectInterface"] = "extend"
+		callParams = legacy
+	}
+
 	// Make API call
-	result, err := zabbix.Call("proxy.get", params)
+	result, err := zabbix.CallContext(ctx, "proxy.get", callParams)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get proxies")
 		// Zabbix might return an empty array if no proxies found but API call successful
@@ -93,7 +109,12 @@ func getProxiesHandler(ctx context.Context, req mcp.CallToolRequest, logger *log
 	}
 
 	var proxies []client.Proxy
-	if err := json.Unmarshal(result, &proxies); err != nil {
+	if legacyProxies(zabbix) {
+		proxies, err = proxiesFromLegacy(result)
+	} else {
+		err = json.Unmarshal(result, &proxies)
+	}
+	if err != nil {
 		logger.WithError(err).Error("Failed to parse proxies response")
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse proxies: %v", err)), nil
 	}
@@ -0,0 +1,142 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package proxies
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+)
+
+// Zabbix 6.x proxy status values; 7.0 replaced them with operating_mode
+// 0=active and 1=passive
+const (
+	legacyStatusActive  = 5
+	legacyStatusPassive = 6
+)
+
+// legacyProxies reports whether zabbix predates the 7.0 proxy API
+func legacyProxies(zabbix *client.ZabbixClient) bool {
+	return !zabbix.Version.AtLeast(client.ProxyGroupsVersion)
+}
+
+// proxyParams returns params for proxy.create and proxy.update in the form
+// the connected Zabbix version expects
+func proxyParams(zabbix *client.ZabbixClient, params interface{}) (interface{}, error) {
+	if !legacyProxies(zabbix) {
+		return params, nil
+	}
+	return legacyProxyParams(params)
+}
+
+// legacyProxyParams renames 7.0 proxy fields to their 6.x names. Fields that
+// have no 6.x equivalent are rejected.
+func legacyProxyParams(params interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal proxy params: %w", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to convert proxy params: %w", err)
+	}
+
+	legacy := make(map[string]interface{}, len(fields))
+	var address, port string
+	for key, value := range fields {
+		switch key {
+		case "name":
+			legacy["host"] = value
+		case "operating_mode":
+			mode, _ := value.(float64)
+			legacy["status"] = legacyStatusActive + int(mode)
+		case "allowed_addresses":
+			legacy["proxy_address"] = value
+		case "address":
+			address, _ = value.(string)
+		case "port":
+			port, _ = value.(string)
+		case "search":
+			if search, ok := value.(map[string]interface{}); ok {
+				if name, ok := search["name"]; ok {
+					delete(search, "name")
+					search["host"] = name
+				}
+			}
+			legacy[key] = value
+		case "proxy_groupid", "proxy_groupids", "local_address", "local_port", "custom_timeouts":
+			return nil, fmt.Errorf("%s is not supported before Zabbix 7.0", key)
+		default:
+			if strings.HasPrefix(key, "timeout_") {
+				return nil, fmt.Errorf("%s is not supported before Zabbix 7.0", key)
+			}
+			legacy[key] = value
+		}
+	}
+
+	// Passive proxies are reached through an interface object in 6.x
+	if address != "" || port != "" {
+		iface := map[string]string{"useip": "0", "ip": "", "dns": address, "port": port}
+		if net.ParseIP(address) != nil {
+			iface["useip"] = "1"
+			iface["ip"] = address
+			iface["dns"] = ""
+		}
+		legacy["interface"] = iface
+	}
+
+	return legacy, nil
+}
+
+// legacyProxy is a proxy as returned by proxy.get before Zabbix 7.0
+type legacyProxy struct {
+	client.Proxy
+	Host         string          `json:"host"`
+	Status       string          `json:"status"`
+	ProxyAddress string          `json:"proxy_address"`
+	LastAccess   string          `json:"lastaccess"`
+	Interface    json.RawMessage `json:"interface"`
+}
+
+// proxiesFromLegacy converts a 6.x proxy.get result to 7.0 proxies
+func proxiesFromLegacy(result json.RawMessage) ([]client.Proxy, error) {
+	var legacy []legacyProxy
+	if err := json.Unmarshal(result, &legacy); err != nil {
+		return nil, err
+	}
+
+	proxies := make([]client.Proxy, 0, len(legacy))
+	for _, lp := range legacy {
+		proxy := lp.Proxy
+		proxy.Name = lp.Host
+		proxy.AllowedAddress = lp.ProxyAddress
+		proxy.LastAccess = lp.LastAccess
+		if status, err := strconv.Atoi(lp.Status); err == nil {
+			proxy.OperatingMode = strconv.Itoa(status - legacyStatusActive)
+		}
+
+		// Active proxies have an empty interface array
+		var iface struct {
+			UseIP string `json:"useip"`
+			IP    string `json:"ip"`
+			DNS   string `json:"dns"`
+			Port  string `json:"port"`
+		}
+		if json.Unmarshal(lp.Interface, &iface) == nil {
+			proxy.Address = iface.DNS
+			if iface.UseIP == "1" {
+				proxy.Address = iface.IP
+			}
+			proxy.Port = iface.Port
+		}
+
+		proxies = append(proxies, proxy)
+	}
+
+	return proxies, nil
+}
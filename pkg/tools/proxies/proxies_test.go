@@ -0,0 +1,183 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package proxies_test
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/proxies"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addProxy(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add("proxy", zabbixtest.Object{"proxyid": "20001", "name": "proxy-dc1", "operating_mode": "0", "proxy_groupid": "1"})
+}
+
+// addLegacyProxy stores a passive proxy the way Zabbix 6.0 returns it
+func addLegacyProxy(t *testing.T, s *zabbixtest.Server) {
+	s.Version = "6.0.30"
+	s.Model.Add("proxy", zabbixtest.Object{
+		"proxyid": "20001", "host": "proxy-dc1", "status": "6", "proxy_address": "", "lastaccess": "1700000000",
+		"interface": map[string]interface{}{"useip": "1", "ip": "10.0.0.5", "dns": "", "port": "10051"},
+	})
+}
+
+func TestGetProxies(t *testing.T) {
+	zabbixtest.RunToolCases(t, proxies.GetProxies(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "proxy.get",
+			Params: `{"output":"extend","selectHosts":"extend","limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"proxyids": "20001, 20002", "proxy_groupids": "1", "search": "dc1", "limit": float64(5)},
+			Method: "proxy.get",
+			Params: `{"output":"extend","proxyids":["20001","20002"],"proxy_groupids":["1"],"search":{"name":"dc1"},"selectHosts":"extend","limit":5}`,
+		},
+		{
+			Name:     "returns model proxies",
+			Setup:    addProxy,
+			Args:     map[string]interface{}{"proxy_groupids": "1"},
+			WantText: "proxy-dc1",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("proxy.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Failed to get proxies",
+		},
+		{
+			Name:   "zabbix 6.0 field names",
+			Setup:  zabbixtest.AtVersion("6.0.30"),
+			Args:   map[string]interface{}{"proxyids": "20001", "search": "dc1"},
+			Method: "proxy.get",
+			Params: `{"output":"extend","proxyids":["20001"],"search":{"host":"dc1"},"selectHosts":"extend","selectInterface":"extend","limit":100}`,
+		},
+		{
+			Name:  "zabbix 6.0 result is translated",
+			Setup: addLegacyProxy,
+			Args:  map[string]interface{}{},
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				var got []client.Proxy
+				zabbixtest.DecodeResult(t, result, &got)
+				if len(got) != 1 || got[0].Name != "proxy-dc1" || got[0].OperatingMode != "1" ||
+					got[0].Address != "10.0.0.5" || got[0].Port != "10051" || got[0].LastAccess != "1700000000" {
+					t.Errorf("unexpected proxies: %+v", got)
+				}
+			},
+		},
+		{
+			Name:      "zabbix 6.0 has no proxy groups",
+			Setup:     zabbixtest.AtVersion("6.0.30"),
+			Args:      map[string]interface{}{"proxy_groupids": "1"},
+			WantError: "proxy_groupids is not supported before Zabbix 7.0",
+			Check: func(t *testing.T, s *zabbixtest.Server, _ *mcp.CallToolResult) {
+				s.AssertNotCalled(t, "proxy.get")
+			},
+		},
+	})
+}
+
+func TestCreateProxy(t *testing.T) {
+	zabbixtest.RunToolCases(t, proxies.CreateProxy(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing name",
+			Args:      map[string]interface{}{"operating_mode": float64(0)},
+			WantError: "name is required",
+		},
+		{
+			Name:      "missing operating_mode",
+			Args:      map[string]interface{}{"name": "proxy-dc1"},
+			WantError: "operating_mode is required",
+		},
+		{
+			Name:   "active proxy",
+			Args:   map[string]interface{}{"name": "proxy-dc1", "operating_mode": float64(0), "allowed_addresses": "10.0.0.1", "hostids": "10084, 10085"},
+			Method: "proxy.create",
+			Params: `{"name":"proxy-dc1","operating_mode":0,"allowed_addresses":"10.0.0.1","hosts":[{"hostid":"10084"},{"hostid":"10085"}]}`,
+		},
+		{
+			Name: "passive proxy with psk",
+			Args: map[string]interface{}{
+				"name": "proxy-dc2", "operating_mode": float64(1), "address": "10.0.0.2", "port": "10051",
+				"tls_connect": float64(2), "tls_psk_identity": "dc2", "tls_psk": "0123456789abcdef",
+			},
+			Method:   "proxy.create",
+			Params:   `{"name":"proxy-dc2","operating_mode":1,"address":"10.0.0.2","port":"10051","tls_connect":2,"tls_psk_identity":"dc2","tls_psk":"0123456789abcdef"}`,
+			WantText: "Proxy created",
+		},
+		{
+			Name:   "zabbix 6.0 passive proxy",
+			Setup:  zabbixtest.AtVersion("6.0.30"),
+			Args:   map[string]interface{}{"name": "proxy-dc2", "operating_mode": float64(1), "address": "proxy.example.com", "port": "10051"},
+			Method: "proxy.create",
+			Params: `{"host":"proxy-dc2","status":6,"interface":{"useip":"0","ip":"","dns":"proxy.example.com","port":"10051"}}`,
+		},
+		{
+			Name:   "zabbix 6.0 active proxy",
+			Setup:  zabbixtest.AtVersion("6.0.30"),
+			Args:   map[string]interface{}{"name": "proxy-dc1", "operating_mode": float64(0), "allowed_addresses": "10.0.0.1"},
+			Method: "proxy.create",
+			Params: `{"host":"proxy-dc1","status":5,"proxy_address":"10.0.0.1"}`,
+		},
+	})
+}
+
+func TestUpdateProxy(t *testing.T) {
+	zabbixtest.RunToolCases(t, proxies.UpdateProxy(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing proxyid",
+			Args:      map[string]interface{}{"name": "x"},
+			WantError: "proxyid is required",
+		},
+		{
+			Name:   "zero values are sent",
+			Setup:  addProxy,
+			Args:   map[string]interface{}{"proxyid": "20001", "operating_mode": float64(0), "tls_accept": float64(1)},
+			Method: "proxy.update",
+			Params: `{"proxyid":"20001","operating_mode":0,"tls_accept":1}`,
+		},
+		{
+			Name:     "move to group",
+			Setup:    addProxy,
+			Args:     map[string]interface{}{"proxyid": "20001", "proxy_groupid": "2", "description": "moved"},
+			Method:   "proxy.update",
+			Params:   `{"proxyid":"20001","proxy_groupid":"2","description":"moved"}`,
+			WantText: "Proxy updated",
+		},
+		{
+			Name:      "unknown proxy",
+			Args:      map[string]interface{}{"proxyid": "1", "name": "x"},
+			WantError: "Failed to update proxy",
+		},
+		{
+			Name:      "zabbix 6.0 rejects proxy groups",
+			Setup:     addLegacyProxy,
+			Args:      map[string]interface{}{"proxyid": "20001", "proxy_groupid": "2"},
+			WantError: "proxy_groupid is not supported before Zabbix 7.0",
+		},
+	})
+}
+
+func TestDeleteProxies(t *testing.T) {
+	zabbixtest.RunToolCases(t, proxies.DeleteProxies(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing proxyids",
+			Args:      map[string]interface{}{},
+			WantError: "proxyids is required",
+		},
+		{
+			Name:     "delete",
+			Setup:    addProxy,
+			Args:     map[string]interface{}{"proxyids": "20001"},
+			Method:   "proxy.delete",
+			Params:   `["20001"]`,
+			WantText: "Proxies deleted",
+		},
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -19,6 +20,15 @@ import (
 func UpdateProxy(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("zabbix_update_proxy",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update Proxy",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Update an existing proxy in Zabbix."),
 			mcp.WithString("proxyid",
 				mcp.Description("ID of the proxy to update"),
@@ -74,7 +84,7 @@ func UpdateProxy(logger *log.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return updateProxyHandler(ctx, req, logger)
+			return updateProxyHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -161,7 +171,12 @@ func updateProxyHandler(ctx context.Context, req mcp.CallToolRequest, logger *lo
 		}
 	}
 
-	result, err := zabbix.Call("proxy.update", params)
+	callParams, err := proxyParams(zabbix, params)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result, err := zabbix.CallContext(ctx, "proxy.update", callParams)
 	if err != nil {
 		logger.WithError(err).Error("Failed to update proxy")
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update proxy: %v", err)), nil
@@ -12,12 +12,23 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // CreateProxyGroup creates a tool for creating a new Zabbix proxy group
 func CreateProxyGroup(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("zabbix_create_proxy_group",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create Proxy Group",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new proxy group in Zabbix."),
 			mcp.WithString("name",
 				mcp.Description("Name of the proxy group"),
@@ -34,7 +45,7 @@ func CreateProxyGroup(logger *log.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return createProxyGroupHandler(ctx, req, logger)
+			return createProxyGroupHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -49,6 +60,10 @@ func createProxyGroupHandler(ctx context.Context, req mcp.CallToolRequest, logge
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get Zabbix client: %v", err)), nil
 	}
 
+	if err := zabbix.Require(client.ProxyGroupsVersion, "The proxy group API"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	args, ok := req.Params.Arguments.(map[string]interface{})
 	if !ok || args == nil {
 		return mcp.NewToolResultError("Invalid arguments"), nil
@@ -73,7 +88,7 @@ func createProxyGroupHandler(ctx context.Context, req mcp.CallToolRequest, logge
 		params.Description = v
 	}
 
-	result, err := zabbix.Call("proxygroup.create", params)
+	result, err := zabbix.CallContext(ctx, "proxygroup.create", params)
 	if err != nil {
 		logger.WithError(err).Error("Failed to create proxy group")
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create proxy group: %v", err)), nil
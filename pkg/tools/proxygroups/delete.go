@@ -12,6 +12,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -19,6 +20,15 @@ import (
 func DeleteProxyGroups(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("zabbix_delete_proxy_groups",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete Proxy Groups",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete one or more proxy groups from the Zabbix server."),
 			mcp.WithString("proxy_groupids",
 				mcp.Description("Comma-separated list of proxy group IDs to delete"),
@@ -26,7 +36,7 @@ func DeleteProxyGroups(logger *log.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return deleteProxyGroupsHandler(ctx, req, logger)
+			return deleteProxyGroupsHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -41,6 +51,10 @@ func deleteProxyGroupsHandler(ctx context.Context, req mcp.CallToolRequest, logg
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get Zabbix client: %v", err)), nil
 	}
 
+	if err := zabbix.Require(client.ProxyGroupsVersion, "The proxy group API"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	args, ok := req.Params.Arguments.(map[string]interface{})
 	if !ok || args == nil {
 		return mcp.NewToolResultError("Invalid arguments"), nil
@@ -53,7 +67,7 @@ func deleteProxyGroupsHandler(ctx context.Context, req mcp.CallToolRequest, logg
 
 	groupIDs := utils.SplitAndTrim(paramsStr)
 
-	result, err := zabbix.Call("proxygroup.delete", groupIDs)
+	result, err := zabbix.CallContext(ctx, "proxygroup.delete", groupIDs)
 	if err != nil {
 		logger.WithError(err).Error("Failed to delete proxy groups")
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete proxy groups: %v", err)), nil
@@ -12,6 +12,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -21,7 +22,11 @@ func GetProxyGroups(logger *log.Logger) server.ServerTool {
 		Tool: mcp.NewTool("zabbix_get_proxy_groups",
 			mcp.WithToolAnnotation(
 				mcp.ToolAnnotation{
-					IdempotentHint: utils.ToBoolPtr(true),
+					Title:           "Get Proxy Groups",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
 				},
 			),
 			mcp.WithDescription("Retrieve all configured proxy groups. Can filter by proxy group IDs or search term."),
@@ -36,7 +41,7 @@ func GetProxyGroups(logger *log.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getProxyGroupsHandler(ctx, req, logger)
+			return getProxyGroupsHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -51,6 +56,10 @@ func getProxyGroupsHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get Zabbix client: %v", err)), nil
 	}
 
+	if err := zabbix.Require(client.ProxyGroupsVersion, "The proxy group API"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	// Build parameters
 	params := client.ProxyGroupGetParams{
 		Output:        "extend",
@@ -78,7 +87,7 @@ func getProxyGroupsHandler(ctx context.Context, req mcp.CallToolRequest, logger
 	}
 
 	// Make API call
-	result, err := zabbix.Call("proxygroup.get", params)
+	result, err := zabbix.CallContext(ctx, "proxygroup.get", params)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get proxy groups")
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get proxy groups: %v", err)), nil
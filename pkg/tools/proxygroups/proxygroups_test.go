@@ -0,0 +1,108 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package proxygroups_test
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/proxygroups"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addProxyGroup(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add("proxygroup", zabbixtest.Object{"proxy_groupid": "1", "name": "DC1", "failover_delay": "1m", "min_online": "1"})
+	s.Model.Add("proxy", zabbixtest.Object{"proxyid": "20001", "name": "proxy-dc1", "proxy_groupid": "1"})
+}
+
+func TestGetProxyGroups(t *testing.T) {
+	zabbixtest.RunToolCases(t, proxygroups.GetProxyGroups(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "proxygroup.get",
+			Params: `{"output":"extend","selectProxies":"extend","limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"proxy_groupids": "1,2", "search": "DC", "limit": float64(2)},
+			Method: "proxygroup.get",
+			Params: `{"output":"extend","proxy_groupids":["1","2"],"search":{"name":"DC"},"selectProxies":"extend","limit":2}`,
+		},
+		{
+			Name:     "returns model groups",
+			Setup:    addProxyGroup,
+			Args:     map[string]interface{}{},
+			WantText: "DC1",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("proxygroup.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Failed to get proxy groups",
+		},
+		{
+			Name:      "zabbix 6.0 is refused",
+			Setup:     zabbixtest.AtVersion("6.0.30"),
+			Args:      map[string]interface{}{},
+			WantError: "The proxy group API requires Zabbix 7.0 or later",
+			Check: func(t *testing.T, s *zabbixtest.Server, _ *mcp.CallToolResult) {
+				s.AssertNotCalled(t, "proxygroup.get")
+			},
+		},
+	})
+}
+
+func TestCreateProxyGroup(t *testing.T) {
+	zabbixtest.RunToolCases(t, proxygroups.CreateProxyGroup(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing name",
+			Args:      map[string]interface{}{},
+			WantError: "name is required",
+		},
+		{
+			Name:     "create",
+			Args:     map[string]interface{}{"name": "DC2", "failover_delay": "2m", "min_online": "2", "description": "second site"},
+			Method:   "proxygroup.create",
+			Params:   `{"name":"DC2","failover_delay":"2m","min_online":"2","description":"second site"}`,
+			WantText: "Proxy group created",
+		},
+	})
+}
+
+func TestUpdateProxyGroup(t *testing.T) {
+	zabbixtest.RunToolCases(t, proxygroups.UpdateProxyGroup(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing proxy_groupid",
+			Args:      map[string]interface{}{"name": "x"},
+			WantError: "proxy_groupid is required",
+		},
+		{
+			Name:     "update",
+			Setup:    addProxyGroup,
+			Args:     map[string]interface{}{"proxy_groupid": "1", "min_online": "2"},
+			Method:   "proxygroup.update",
+			Params:   `{"proxy_groupid":"1","min_online":"2"}`,
+			WantText: "Proxy group updated",
+		},
+	})
+}
+
+func TestDeleteProxyGroups(t *testing.T) {
+	zabbixtest.RunToolCases(t, proxygroups.DeleteProxyGroups(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing proxy_groupids",
+			Args:      map[string]interface{}{},
+			WantError: "proxy_groupids is required",
+		},
+		{
+			Name:     "delete",
+			Setup:    addProxyGroup,
+			Args:     map[string]interface{}{"proxy_groupids": "1"},
+			Method:   "proxygroup.delete",
+			Params:   `["1"]`,
+			WantText: "Proxy groups deleted",
+		},
+	})
+}
@@ -12,12 +12,23 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // UpdateProxyGroup creates a tool for updating a Zabbix proxy group
 func UpdateProxyGroup(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("zabbix_update_proxy_group",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update Proxy Group",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Update an existing proxy group in Zabbix."),
 			mcp.WithString("proxy_groupid",
 				mcp.Description("ID of the proxy group to update"),
@@ -37,7 +48,7 @@ func UpdateProxyGroup(logger *log.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return updateProxyGroupHandler(ctx, req, logger)
+			return updateProxyGroupHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -52,6 +63,10 @@ func updateProxyGroupHandler(ctx context.Context, req mcp.CallToolRequest, logge
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get Zabbix client: %v", err)), nil
 	}
 
+	if err := zabbix.Require(client.ProxyGroupsVersion, "The proxy group API"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	args, ok := req.Params.Arguments.(map[string]interface{})
 	if !ok || args == nil {
 		return mcp.NewToolResultError("Invalid arguments"), nil
@@ -79,7 +94,7 @@ func updateProxyGroupHandler(ctx context.Context, req mcp.CallToolRequest, logge
 		params.Description = v
 	}
 
-	result, err := zabbix.Call("proxygroup.update", params)
+	result, err := zabbix.CallContext(ctx, "proxygroup.update", params)
 	if err != nil {
 		logger.WithError(err).Error("Failed to update proxy group")
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update proxy group: %v", err)), nil
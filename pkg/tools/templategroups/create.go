@@ -10,12 +10,23 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // CreateTemplateGroup returns the tool definition and handler for creating a new Zabbix template group
 func CreateTemplateGroup(logger *logrus.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("zabbix_create_template_group",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create Template Group",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new template group in Zabbix."),
 			mcp.WithString("name",
 				mcp.Description("Name of the template group"),
@@ -23,10 +34,14 @@ func CreateTemplateGroup(logger *logrus.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			logger := logging.FromContext(ctx, logger)
 			zabbixClient, err := client.GetZabbixClientFromContext(ctx, logger)
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
+			if err := zabbixClient.Require(client.HostGroupsVersion, "The template group API"); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
 
 			args, ok := request.Params.Arguments.(map[string]interface{})
 			if !ok {
@@ -44,7 +59,7 @@ func CreateTemplateGroup(logger *logrus.Logger) server.ServerTool {
 			}
 
 			// Make API call (Zabbix 7.0 uses templategroup.create)
-			result, err := zabbixClient.Call("templategroup.create", params)
+			result, err := zabbixClient.CallContext(ctx, "templategroup.create", params)
 			if err != nil {
 				logger.WithError(err).Error("Failed to create template group")
 				return mcp.NewToolResultError(err.Error()), nil
@@ -11,22 +11,37 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // DeleteTemplateGroup returns the tool definition and handler for deleting Zabbix template groups
 func DeleteTemplateGroup(logger *logrus.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("zabbix_delete_template_group",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete Template Group",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete template groups from Zabbix server."),
 			mcp.WithString("groupids",
 				mcp.Description("Comma-separated list of template group IDs to delete"),
 			),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			logger := logging.FromContext(ctx, logger)
 			zabbixClient, err := client.GetZabbixClientFromContext(ctx, logger)
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
+			if err := zabbixClient.Require(client.HostGroupsVersion, "The template group API"); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
 
 			args, ok := request.Params.Arguments.(map[string]interface{})
 			if !ok {
@@ -42,7 +57,7 @@ func DeleteTemplateGroup(logger *logrus.Logger) server.ServerTool {
 			groupIDs := strings.Split(groupIDsStr, ",")
 
 			// Make API call (Zabbix 7.0 uses templategroup.delete)
-			result, err := zabbixClient.Call("templategroup.delete", groupIDs)
+			result, err := zabbixClient.CallContext(ctx, "templategroup.delete", groupIDs)
 			if err != nil {
 				logger.WithError(err).Error("Failed to delete template groups")
 				return mcp.NewToolResultError(err.Error()), nil
@@ -11,12 +11,23 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // GetTemplateGroups returns the tool definition and handler for retrieving Zabbix template groups
 func GetTemplateGroups(logger *logrus.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("zabbix_get_template_groups",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Template Groups",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("List template groups from Zabbix server. Can filter by group IDs, template IDs, or search term."),
 			mcp.WithString("groupids",
 				mcp.Description("Comma-separated list of template group IDs to filter by"),
@@ -32,10 +43,14 @@ func GetTemplateGroups(logger *logrus.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			logger := logging.FromContext(ctx, logger)
 			zabbixClient, err := client.GetZabbixClientFromContext(ctx, logger)
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
+			if err := zabbixClient.Require(client.HostGroupsVersion, "The template group API"); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
 
 			args, ok := request.Params.Arguments.(map[string]interface{})
 			if !ok {
@@ -66,7 +81,7 @@ func GetTemplateGroups(logger *logrus.Logger) server.ServerTool {
 			}
 
 			// Make API call (Zabbix 7.0 uses templategroup.get)
-			result, err := zabbixClient.Call("templategroup.get", params)
+			result, err := zabbixClient.CallContext(ctx, "templategroup.get", params)
 			if err != nil {
 				logger.WithError(err).Error("Failed to get template groups")
 				return mcp.NewToolResultError(err.Error()), nil
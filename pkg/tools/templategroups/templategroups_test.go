@@ -0,0 +1,113 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package templategroups_test
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/templategroups"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addTemplateGroup(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add("templategroup", zabbixtest.Object{"groupid": "2", "name": "Templates/Operating systems"})
+}
+
+func TestGetTemplateGroups(t *testing.T) {
+	zabbixtest.RunToolCases(t, templategroups.GetTemplateGroups(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Method: "templategroup.get",
+			Params: `{"output":"extend","selectTemplates":["templateid","name"],"limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"groupids": "2,4", "templateids": "10001", "search": "Linux", "limit": float64(10)},
+			Method: "templategroup.get",
+			Params: `{"output":"extend","groupids":["2","4"],"templateids":["10001"],"search":{"name":"Linux"},"selectTemplates":["templateid","name"],"limit":10}`,
+		},
+		{
+			Name:     "returns model groups",
+			Setup:    addTemplateGroup,
+			Args:     map[string]interface{}{},
+			WantText: "Templates/Operating systems",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("templategroup.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Injected failure.",
+		},
+		{
+			Name:      "zabbix 6.0 is refused",
+			Setup:     zabbixtest.AtVersion("6.0.30"),
+			Args:      map[string]interface{}{},
+			WantError: "The template group API requires Zabbix 6.2 or later",
+			Check: func(t *testing.T, s *zabbixtest.Server, _ *mcp.CallToolResult) {
+				s.AssertNotCalled(t, "templategroup.get")
+			},
+		},
+	})
+}
+
+func TestCreateTemplateGroup(t *testing.T) {
+	zabbixtest.RunToolCases(t, templategroups.CreateTemplateGroup(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing name",
+			Args:      map[string]interface{}{},
+			WantError: "name is required",
+		},
+		{
+			Name:     "create",
+			Args:     map[string]interface{}{"name": "Databases"},
+			Method:   "templategroup.create",
+			Params:   `{"name":"Databases"}`,
+			WantText: `"groupids"`,
+		},
+		{
+			Name:      "zabbix 6.0 is refused",
+			Setup:     zabbixtest.AtVersion("6.0.30"),
+			Args:      map[string]interface{}{"name": "Databases"},
+			WantError: "The template group API requires Zabbix 6.2 or later",
+			Check: func(t *testing.T, s *zabbixtest.Server, _ *mcp.CallToolResult) {
+				s.AssertNotCalled(t, "templategroup.create")
+			},
+		},
+	})
+}
+
+func TestUpdateTemplateGroup(t *testing.T) {
+	zabbixtest.RunToolCases(t, templategroups.UpdateTemplateGroup(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing name",
+			Args:      map[string]interface{}{"groupid": "2"},
+			WantError: "name is required",
+		},
+		{
+			Name:   "rename",
+			Setup:  addTemplateGroup,
+			Args:   map[string]interface{}{"groupid": "2", "name": "Linux"},
+			Method: "templategroup.update",
+			Params: `{"groupid":"2","name":"Linux"}`,
+		},
+	})
+}
+
+func TestDeleteTemplateGroup(t *testing.T) {
+	zabbixtest.RunToolCases(t, templategroups.DeleteTemplateGroup(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing groupids",
+			Args:      map[string]interface{}{},
+			WantError: "groupids is required",
+		},
+		{
+			Name:   "delete",
+			Setup:  addTemplateGroup,
+			Args:   map[string]interface{}{"groupids": "2"},
+			Method: "templategroup.delete",
+			Params: `["2"]`,
+		},
+	})
+}
@@ -10,12 +10,23 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // UpdateTemplateGroup returns the tool definition and handler for updating a Zabbix template group
 func UpdateTemplateGroup(logger *logrus.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("zabbix_update_template_group",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update Template Group",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Update an existing template group in Zabbix."),
 			mcp.WithString("groupid",
 				mcp.Description("ID of the template group to update"),
@@ -27,10 +38,14 @@ func UpdateTemplateGroup(logger *logrus.Logger) server.ServerTool {
 			),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			logger := logging.FromContext(ctx, logger)
 			zabbixClient, err := client.GetZabbixClientFromContext(ctx, logger)
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
+			if err := zabbixClient.Require(client.HostGroupsVersion, "The template group API"); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
 
 			args, ok := request.Params.Arguments.(map[string]interface{})
 			if !ok {
@@ -54,7 +69,7 @@ func UpdateTemplateGroup(logger *logrus.Logger) server.ServerTool {
 			}
 
 			// Make API call (Zabbix 7.0 uses templategroup.update)
-			result, err := zabbixClient.Call("templategroup.update", params)
+			result, err := zabbixClient.CallContext(ctx, "templategroup.update", params)
 			if err != nil {
 				logger.WithError(err).Error("Failed to update template group")
 				return mcp.NewToolResultError(err.Error()), nil
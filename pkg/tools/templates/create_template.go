@@ -13,12 +13,23 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // CreateTemplate creates a tool for creating a new template in Zabbix
 func CreateTemplate(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("create_template",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create Template",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new template in Zabbix."),
 			mcp.WithString("host", mcp.Required(), mcp.Description("Technical name of the template")),
 			mcp.WithString("groupids", mcp.Required(), mcp.Description("Comma-separated list of template group IDs")),
@@ -27,7 +38,7 @@ func CreateTemplate(logger *log.Logger) server.ServerTool {
 			mcp.WithString("tags", mcp.Description("Tags in format key:value,key2:value2")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return createTemplateHandler(ctx, req, logger)
+			return createTemplateHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -82,7 +93,7 @@ func createTemplateHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		params.Tags = tags
 	}
 
-	result, err := zabbix.Call("template.create", params)
+	result, err := zabbix.CallContext(ctx, "template.create", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create template: %v", err)), nil
 	}
@@ -13,18 +13,30 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/confirm"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // DeleteTemplate creates a tool for deleting templates from Zabbix
 func DeleteTemplate(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("delete_template",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete Template",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete templates from Zabbix."),
 			mcp.WithString("templateids", mcp.Required(), mcp.Description("Comma-separated list of template IDs to delete")),
-			mcp.WithBoolean("clear", mcp.Description("If true, also delete items/triggers from unlinked templates (default: false)")),
+			confirm.WithConfirm(),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return deleteTemplateHandler(ctx, req, logger)
+			return deleteTemplateHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -47,7 +59,49 @@ func deleteTemplateHandler(ctx context.Context, req mcp.CallToolRequest, logger
 
 	params := utilSplitAndTrim(templateidsStr)
 
-	result, err := zabbix.Call("template.delete", params)
+	result, err := zabbix.CallContext(ctx, "template.get", map[string]interface{}{
+		"output":         []string{"templateid", "host"},
+		"templateids":    params,
+		"selectItems":    "count",
+		"selectTriggers": "count",
+		"selectHosts":    "count",
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to resolve templates: %v", err)), nil
+	}
+
+	var templates []struct {
+		TemplateID string        `json:"templateid"`
+		Host       string        `json:"host"`
+		Items      confirm.Count `json:"items"`
+		Triggers   confirm.Count `json:"triggers"`
+		Hosts      confirm.Count `json:"hosts"`
+	}
+	if err := json.Unmarshal(result, &templates); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse templates: %v", err)), nil
+	}
+
+	found := make([]confirm.Object, 0, len(templates))
+	for _, t := range templates {
+		found = append(found, confirm.Object{
+			ID:   t.TemplateID,
+			Name: t.Host,
+			Details: fmt.Sprintf("%s, %s, linked to %s",
+				confirm.Plural(t.Items, "item", "items"),
+				confirm.Plural(t.Triggers, "trigger", "triggers"),
+				confirm.Plural(t.Hosts, "host", "hosts")),
+		})
+	}
+
+	if res := confirm.Request(ctx, req, confirm.Operation{
+		Action:  "Delete " + confirm.Plural(len(params), "template", "templates"),
+		Objects: confirm.Resolve(params, found),
+		Warning: "Items and triggers inherited from these templates will be removed from all linked hosts.",
+	}, logger); res != nil {
+		return res, nil
+	}
+
+	result, err = zabbix.CallContext(ctx, "template.delete", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete templates: %v", err)), nil
 	}
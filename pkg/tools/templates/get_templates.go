@@ -12,6 +12,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -26,7 +27,15 @@ type Template struct {
 func GetTemplates(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_templates",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Templates",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("List templates from Zabbix."),
 			mcp.WithString("templateids", mcp.Description("Comma-separated template IDs")),
 			mcp.WithString("hostids", mcp.Description("Comma-separated host IDs")),
@@ -34,7 +43,7 @@ func GetTemplates(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("limit", mcp.Description("Max templates (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getTemplatesHandler(ctx, req, logger)
+			return getTemplatesHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -62,7 +71,7 @@ func getTemplatesHandler(ctx context.Context, req mcp.CallToolRequest, logger *l
 		}
 	}
 
-	result, err := zabbix.Call("template.get", params)
+	result, err := zabbix.CallContext(ctx, "template.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed: %v", err)), nil
 	}
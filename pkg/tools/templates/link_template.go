@@ -12,17 +12,28 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func LinkTemplate(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("link_template",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Link Template",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Link templates to a host."),
 			mcp.WithString("hostid", mcp.Required(), mcp.Description("Host ID")),
 			mcp.WithString("templateids", mcp.Required(), mcp.Description("Comma-separated template IDs")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return linkTemplateHandler(ctx, req, logger)
+			return linkTemplateHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -54,7 +65,7 @@ func linkTemplateHandler(ctx context.Context, req mcp.CallToolRequest, logger *l
 		"templates": templates,
 	}
 
-	result, err := zabbix.Call("host.update", params)
+	result, err := zabbix.CallContext(ctx, "host.update", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed: %v", err)), nil
 	}
@@ -0,0 +1,216 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package templates_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/templates"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+// addTemplate stores a template with two items and a trigger linked to one host
+func addTemplate(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add(zabbixtest.Templates, zabbixtest.Object{"templateid": "10001", "host": "Linux by Zabbix agent", "name": "Linux by Zabbix agent"})
+	s.Model.Add(zabbixtest.Hosts, zabbixtest.Object{"hostid": "10084", "host": "web01", "parentTemplates": []interface{}{map[string]interface{}{"templateid": "10001"}}})
+	s.Model.Add(zabbixtest.Items, zabbixtest.Object{"hostid": "10001", "name": "CPU"})
+	s.Model.Add(zabbixtest.Items, zabbixtest.Object{"hostid": "10001", "name": "Memory"})
+	s.Model.Add(zabbixtest.Triggers, zabbixtest.Object{"hostid": "10001", "description": "High CPU"})
+}
+
+func TestGetTemplates(t *testing.T) {
+	zabbixtest.RunToolCases(t, templates.GetTemplates(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "template.get",
+			Params: `{"output":"extend","selectHosts":"extend","selectTags":"extend","limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"templateids": "10001 ,10002", "hostids": "10084", "search": "Linux", "limit": float64(10)},
+			Method: "template.get",
+			Params: `{"output":"extend","templateids":["10001","10002"],"hostids":["10084"],"search":{"name":"Linux"},"selectHosts":"extend","selectTags":"extend","limit":10}`,
+		},
+		{
+			Name:     "returns model templates",
+			Setup:    addTemplate,
+			Args:     map[string]interface{}{"templateids": "10001"},
+			WantText: "Linux by Zabbix agent",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("template.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Injected failure.",
+		},
+	})
+}
+
+func TestCreateTemplate(t *testing.T) {
+	zabbixtest.RunToolCases(t, templates.CreateTemplate(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing groupids",
+			Args:      map[string]interface{}{"host": "Custom"},
+			WantError: "host and groupids are required",
+		},
+		{
+			Name:   "minimal",
+			Args:   map[string]interface{}{"host": "Custom", "groupids": "1, 2"},
+			Method: "template.create",
+			Params: `{"host":"Custom","groups":[{"groupid":"1"},{"groupid":"2"}]}`,
+		},
+		{
+			Name:     "all fields",
+			Args:     map[string]interface{}{"host": "Custom", "groupids": "1", "name": "Custom template", "description": "d", "tags": "class:os,target"},
+			Method:   "template.create",
+			Params:   `{"host":"Custom","groups":[{"groupid":"1"}],"name":"Custom template","description":"d","tags":[{"tag":"class","value":"os"},{"tag":"target","value":""}]}`,
+			WantText: "Template created",
+		},
+	})
+}
+
+func TestUpdateTemplate(t *testing.T) {
+	zabbixtest.RunToolCases(t, templates.UpdateTemplate(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing templateid",
+			Args:      map[string]interface{}{"name": "x"},
+			WantError: "templateid is required",
+		},
+		{
+			Name:   "rename and tag",
+			Setup:  addTemplate,
+			Args:   map[string]interface{}{"templateid": "10001", "host": "Linux", "name": "Linux", "description": "", "tags": "class:os"},
+			Method: "template.update",
+			Params: `{"templateid":"10001","host":"Linux","name":"Linux","tags":[{"tag":"class","value":"os"}]}`,
+		},
+		{
+			Name: "reports changes",
+			Setup: func(t *testing.T, s *zabbixtest.Server) {
+				s.Model.Add(zabbixtest.Templates, zabbixtest.Object{
+					"templateid": "10001", "host": "Linux by Zabbix agent", "name": "Linux by Zabbix agent",
+					"tags":            []zabbixtest.Object{{"tag": "class", "value": "os"}},
+					"parentTemplates": []interface{}{},
+				})
+			},
+			Args: map[string]interface{}{"templateid": "10001", "name": "Linux", "tags": "class:os,target:linux"},
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				zabbixtest.AssertChanges(t, result, `[
+					{"field":"name","change":"changed","before":"Linux by Zabbix agent","after":"Linux"},
+					{"field":"tags[target:linux]","change":"added","after":{"tag":"target","value":"linux"}}
+				]`)
+			},
+		},
+		{
+			Name:     "diff unavailable",
+			Setup:    func(t *testing.T, s *zabbixtest.Server) { addTemplate(t, s); zabbixtest.Failing("template.get")(t, s) },
+			Args:     map[string]interface{}{"templateid": "10001", "name": "Linux"},
+			Method:   "template.update",
+			WantText: `"changes_unavailable": "template.get before the update failed`,
+		},
+		{
+			Name:      "unknown template",
+			Args:      map[string]interface{}{"templateid": "1", "name": "x"},
+			WantError: "Failed to update template",
+		},
+	})
+}
+
+func TestDeleteTemplate(t *testing.T) {
+	zabbixtest.RunToolCases(t, templates.DeleteTemplate(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing templateids",
+			Args:      map[string]interface{}{},
+			WantError: "templateids required",
+		},
+		{
+			Name:      "requires confirmation",
+			Setup:     addTemplate,
+			Args:      map[string]interface{}{"templateids": "10001,10002"},
+			Method:    "template.get",
+			Params:    `{"output":["templateid","host"],"templateids":["10001","10002"],"selectItems":"count","selectTriggers":"count","selectHosts":"count"}`,
+			WantError: `"details": "2 items, 1 trigger, linked to 1 host"`,
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				if text := zabbixtest.ResultText(result); !strings.Contains(text, `"id": "10002"`) {
+					t.Errorf("missing template not listed: %s", text)
+				}
+				s.AssertNotCalled(t, "template.delete")
+			},
+		},
+		{
+			Name:     "confirmed",
+			Setup:    addTemplate,
+			Args:     map[string]interface{}{"templateids": "10001", "confirm": true},
+			Method:   "template.delete",
+			Params:   `["10001"]`,
+			WantText: "Templates deleted",
+		},
+	})
+}
+
+func TestLinkTemplate(t *testing.T) {
+	zabbixtest.RunToolCases(t, templates.LinkTemplate(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing templateids",
+			Args:      map[string]interface{}{"hostid": "10084"},
+			WantError: "hostid and templateids required",
+		},
+		{
+			Name:     "link",
+			Setup:    addTemplate,
+			Args:     map[string]interface{}{"hostid": "10084", "templateids": "10001, 10002"},
+			Method:   "host.update",
+			Params:   `{"hostid":"10084","templates":[{"templateid":"10001"},{"templateid":"10002"}]}`,
+			WantText: "Templates linked",
+		},
+	})
+}
+
+func TestUnlinkTemplate(t *testing.T) {
+	zabbixtest.RunToolCases(t, templates.UnlinkTemplate(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing hostid",
+			Args:      map[string]interface{}{"templateids": "10001"},
+			WantError: "hostid and templateids required",
+		},
+		{
+			Name:      "unknown host",
+			Args:      map[string]interface{}{"hostid": "1", "templateids": "10001", "confirm": true},
+			WantError: "Host 1 not found",
+		},
+		{
+			Name:      "requires confirmation",
+			Setup:     addTemplate,
+			Args:      map[string]interface{}{"hostid": "10084", "templateids": "10001"},
+			WantError: "Unlink 1 template from host web01",
+			Check: func(t *testing.T, s *zabbixtest.Server, _ *mcp.CallToolResult) {
+				s.AssertNotCalled(t, "host.massremove")
+			},
+		},
+		{
+			Name:     "unlink keeps entities",
+			Setup:    addTemplate,
+			Args:     map[string]interface{}{"hostid": "10084", "templateids": "10001", "confirm": true},
+			Method:   "host.massremove",
+			Params:   `{"hostids":["10084"],"templateids":["10001"]}`,
+			WantText: "Templates unlinked",
+			Check: func(t *testing.T, s *zabbixtest.Server, _ *mcp.CallToolResult) {
+				// host.update with templates_clear would delete the entities
+				s.AssertNotCalled(t, "host.update")
+			},
+		},
+		{
+			Name:   "unlink and clear",
+			Setup:  addTemplate,
+			Args:   map[string]interface{}{"hostid": "10084", "templateids": "10001", "clear": true, "confirm": true},
+			Method: "host.massremove",
+			Params: `{"hostids":["10084"],"templateids_clear":["10001"]}`,
+			Check: func(t *testing.T, s *zabbixtest.Server, _ *mcp.CallToolResult) {
+				s.AssertNotCalled(t, "host.update")
+			},
+		},
+	})
+}
@@ -12,18 +12,31 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/confirm"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func UnlinkTemplate(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("unlink_template",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Unlink Template",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Unlink templates from a host."),
 			mcp.WithString("hostid", mcp.Required(), mcp.Description("Host ID")),
 			mcp.WithString("templateids", mcp.Required(), mcp.Description("Comma-separated template IDs")),
 			mcp.WithBoolean("clear", mcp.Description("If true, also delete items/triggers from unlinked templates")),
+			confirm.WithConfirm(),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return unlinkTemplateHandler(ctx, req, logger)
+			return unlinkTemplateHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -45,20 +58,76 @@ func unlinkTemplateHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		return mcp.NewToolResultError("hostid and templateids required"), nil
 	}
 
-	var templates []map[string]string
-	for _, tid := range splitAndTrim(templateidsStr) {
-		templates = append(templates, map[string]string{"templateid": tid})
+	templateids := splitAndTrim(templateidsStr)
+	clear, _ := args["clear"].(bool)
+
+	result, err := zabbix.CallContext(ctx, "host.get", map[string]interface{}{
+		"output":  []string{"hostid", "host"},
+		"hostids": []string{hostid},
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to resolve host: %v", err)), nil
+	}
+	var hosts []struct {
+		Host string `json:"host"`
+	}
+	if err := json.Unmarshal(result, &hosts); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse host: %v", err)), nil
+	}
+	if len(hosts) == 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("Host %s not found", hostid)), nil
 	}
 
-	params := map[string]interface{}{"hostid": hostid}
+	result, err = zabbix.CallContext(ctx, "template.get", map[string]interface{}{
+		"output":         []string{"templateid", "host"},
+		"templateids":    templateids,
+		"selectItems":    "count",
+		"selectTriggers": "count",
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to resolve templates: %v", err)), nil
+	}
+	var templates []struct {
+		TemplateID string        `json:"templateid"`
+		Host       string        `json:"host"`
+		Items      confirm.Count `json:"items"`
+		Triggers   confirm.Count `json:"triggers"`
+	}
+	if err := json.Unmarshal(result, &templates); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse templates: %v", err)), nil
+	}
+
+	found := make([]confirm.Object, 0, len(templates))
+	for _, t := range templates {
+		found = append(found, confirm.Object{
+			ID:      t.TemplateID,
+			Name:    t.Host,
+			Details: confirm.Plural(t.Items, "item", "items") + ", " + confirm.Plural(t.Triggers, "trigger", "triggers"),
+		})
+	}
+
+	op := confirm.Operation{
+		Action:  fmt.Sprintf("Unlink %s from host %s", confirm.Plural(len(templateids), "template", "templates"), hosts[0].Host),
+		Objects: confirm.Resolve(templateids, found),
+		Warning: "Inherited items and triggers will be kept on the host as unlinked copies.",
+	}
+	if clear {
+		op.Warning = "Inherited items and triggers, with their history, will be deleted from the host."
+	}
+	if res := confirm.Request(ctx, req, op, logger); res != nil {
+		return res, nil
+	}
 
-	if clear, ok := args["clear"].(bool); ok && clear {
-		params["templates_clear"] = templates
+	// host.massremove unlinks with templateids and unlinks and clears with
+	// templateids_clear
+	params := map[string]interface{}{"hostids": []string{hostid}}
+	if clear {
+		params["templateids_clear"] = templateids
 	} else {
-		params["templates_clear"] = templates
+		params["templateids"] = templateids
 	}
 
-	result, err := zabbix.Call("host.update", params)
+	result, err = zabbix.CallContext(ctx, "host.massremove", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed: %v", err)), nil
 	}
@@ -13,13 +13,25 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/diff"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 // UpdateTemplate creates a tool for updating an existing template in Zabbix
 func UpdateTemplate(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("update_template",
-			mcp.WithDescription("Update an existing template in Zabbix."),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update Template",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
+			mcp.WithDescription("Update an existing template in Zabbix. Returns the fields that changed, with their old and new values."),
 			mcp.WithString("templateid", mcp.Required(), mcp.Description("Template ID")),
 			mcp.WithString("host", mcp.Description("New technical name")),
 			mcp.WithString("name", mcp.Description("New visible name")),
@@ -27,11 +39,24 @@ func UpdateTemplate(logger *log.Logger) server.ServerTool {
 			mcp.WithString("tags", mcp.Description("Tags in format key:value,key2:value2")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return updateTemplateHandler(ctx, req, logger)
+			return updateTemplateHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
 
+// templateDiff fetches a template with its tags, macros, groups and
+// linked templates
+var templateDiff = diff.Getter{
+	Method:   "template.get",
+	IDsParam: "templateids",
+	Params: map[string]interface{}{
+		"selectTags":            "extend",
+		"selectMacros":          "extend",
+		"selectTemplateGroups":  "extend",
+		"selectParentTemplates": []string{"templateid", "host"},
+	},
+}
+
 func updateTemplateHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	zabbix, err := client.GetZabbixClientFromContext(ctx, logger)
 	if err != nil {
@@ -75,7 +100,9 @@ func updateTemplateHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		params.Tags = tags
 	}
 
-	result, err := zabbix.Call("template.update", params)
+	update := templateDiff.Track(ctx, zabbix, templateid)
+
+	result, err := zabbix.CallContext(ctx, "template.update", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update template: %v", err)), nil
 	}
@@ -84,6 +111,11 @@ func updateTemplateHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		TemplateIDs []string `json:"templateids"`
 	}
 	json.Unmarshal(result, &response)
-	jsonData, _ := json.MarshalIndent(map[string]interface{}{"message": "Template updated", "templateids": response.TemplateIDs}, "", "  ")
+	responseData := map[string]interface{}{"message": "Template updated", "templateids": response.TemplateIDs}
+	if err := update.Report(ctx, responseData); err != nil {
+		logger.WithError(err).Warn("Failed to compare template after update")
+	}
+
+	jsonData, _ := json.MarshalIndent(responseData, "", "  ")
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
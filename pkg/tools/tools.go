@@ -4,19 +4,29 @@
 package tools
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/federation"
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools/alerts"
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools/auditlog"
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools/docs"
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools/events"
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools/hostgroups"
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools/hosts"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/instances"
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools/itemprototypes"
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools/items"
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools/lld"
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools/macros"
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools/maintenance"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/mcpaudit"
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools/problems"
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools/proxies"
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools/proxygroups"
@@ -30,266 +40,582 @@ import (
 	"github.com/vfcastr/Zabbix-MCP/pkg/tools/users"
 )
 
-// InitTools registers all Zabbix MCP tools with the server
-func InitTools(mcpServer *server.MCPServer, logger *log.Logger) {
-
-	// Tools for Host management
-	getHostsTool := hosts.GetHosts(logger)
-	mcpServer.AddTool(getHostsTool.Tool, getHostsTool.Handler)
-
-	createHostTool := hosts.CreateHost(logger)
-	mcpServer.AddTool(createHostTool.Tool, createHostTool.Handler)
-
-	updateHostTool := hosts.UpdateHost(logger)
-	mcpServer.AddTool(updateHostTool.Tool, updateHostTool.Handler)
-
-	deleteHostTool := hosts.DeleteHost(logger)
-	mcpServer.AddTool(deleteHostTool.Tool, deleteHostTool.Handler)
-
-	// Tools for Item management
-	getItemsTool := items.GetItems(logger)
-	mcpServer.AddTool(getItemsTool.Tool, getItemsTool.Handler)
-
-	createItemTool := items.CreateItem(logger)
-	mcpServer.AddTool(createItemTool.Tool, createItemTool.Handler)
-
-	updateItemTool := items.UpdateItem(logger)
-	mcpServer.AddTool(updateItemTool.Tool, updateItemTool.Handler)
-
-	deleteItemTool := items.DeleteItem(logger)
-	mcpServer.AddTool(deleteItemTool.Tool, deleteItemTool.Handler)
-
-	getHistoryTool := items.GetHistory(logger)
-	mcpServer.AddTool(getHistoryTool.Tool, getHistoryTool.Handler)
-
-	// Tools for Trigger management
-	getTriggersTool := triggers.GetTriggers(logger)
-	mcpServer.AddTool(getTriggersTool.Tool, getTriggersTool.Handler)
-
-	createTriggerTool := triggers.CreateTrigger(logger)
-	mcpServer.AddTool(createTriggerTool.Tool, createTriggerTool.Handler)
-
-	updateTriggerTool := triggers.UpdateTrigger(logger)
-	mcpServer.AddTool(updateTriggerTool.Tool, updateTriggerTool.Handler)
-
-	deleteTriggerTool := triggers.DeleteTrigger(logger)
-	mcpServer.AddTool(deleteTriggerTool.Tool, deleteTriggerTool.Handler)
-
-	// Tools for Template management
-	getTemplatesTool := templates.GetTemplates(logger)
-	mcpServer.AddTool(getTemplatesTool.Tool, getTemplatesTool.Handler)
-
-	linkTemplateTool := templates.LinkTemplate(logger)
-	mcpServer.AddTool(linkTemplateTool.Tool, linkTemplateTool.Handler)
-
-	unlinkTemplateTool := templates.UnlinkTemplate(logger)
-	mcpServer.AddTool(unlinkTemplateTool.Tool, unlinkTemplateTool.Handler)
-
-	createTemplateTool := templates.CreateTemplate(logger)
-	mcpServer.AddTool(createTemplateTool.Tool, createTemplateTool.Handler)
-
-	updateTemplateTool := templates.UpdateTemplate(logger)
-	mcpServer.AddTool(updateTemplateTool.Tool, updateTemplateTool.Handler)
-
-	deleteTemplateTool := templates.DeleteTemplate(logger)
-	mcpServer.AddTool(deleteTemplateTool.Tool, deleteTemplateTool.Handler)
-
-	// Tools for Maintenance management
-	getMaintenanceTool := maintenance.GetMaintenance(logger)
-	mcpServer.AddTool(getMaintenanceTool.Tool, getMaintenanceTool.Handler)
-
-	createMaintenanceTool := maintenance.CreateMaintenance(logger)
-	mcpServer.AddTool(createMaintenanceTool.Tool, createMaintenanceTool.Handler)
-
-	updateMaintenanceTool := maintenance.UpdateMaintenance(logger)
-	mcpServer.AddTool(updateMaintenanceTool.Tool, updateMaintenanceTool.Handler)
-
-	deleteMaintenanceTool := maintenance.DeleteMaintenance(logger)
-	mcpServer.AddTool(deleteMaintenanceTool.Tool, deleteMaintenanceTool.Handler)
-
-	// Tools for Host Group management
-	getHostGroupsTool := hostgroups.GetHostGroups(logger)
-	mcpServer.AddTool(getHostGroupsTool.Tool, getHostGroupsTool.Handler)
-
-	createHostGroupTool := hostgroups.CreateHostGroup(logger)
-	mcpServer.AddTool(createHostGroupTool.Tool, createHostGroupTool.Handler)
-
-	updateHostGroupTool := hostgroups.UpdateHostGroup(logger)
-	mcpServer.AddTool(updateHostGroupTool.Tool, updateHostGroupTool.Handler)
-
-	deleteHostGroupTool := hostgroups.DeleteHostGroup(logger)
-	mcpServer.AddTool(deleteHostGroupTool.Tool, deleteHostGroupTool.Handler)
-
-	// Tools for Template Group management
-	getTemplateGroupsTool := templategroups.GetTemplateGroups(logger)
-	mcpServer.AddTool(getTemplateGroupsTool.Tool, getTemplateGroupsTool.Handler)
-
-	createTemplateGroupTool := templategroups.CreateTemplateGroup(logger)
-	mcpServer.AddTool(createTemplateGroupTool.Tool, createTemplateGroupTool.Handler)
-
-	updateTemplateGroupTool := templategroups.UpdateTemplateGroup(logger)
-	mcpServer.AddTool(updateTemplateGroupTool.Tool, updateTemplateGroupTool.Handler)
-
-	deleteTemplateGroupTool := templategroups.DeleteTemplateGroup(logger)
-	mcpServer.AddTool(deleteTemplateGroupTool.Tool, deleteTemplateGroupTool.Handler)
-
-	// Tools for Proxy management
-	getProxiesTool := proxies.GetProxies(logger)
-	mcpServer.AddTool(getProxiesTool.Tool, getProxiesTool.Handler)
-
-	createProxyTool := proxies.CreateProxy(logger)
-	mcpServer.AddTool(createProxyTool.Tool, createProxyTool.Handler)
-
-	updateProxyTool := proxies.UpdateProxy(logger)
-	mcpServer.AddTool(updateProxyTool.Tool, updateProxyTool.Handler)
-
-	deleteProxyTool := proxies.DeleteProxies(logger)
-	mcpServer.AddTool(deleteProxyTool.Tool, deleteProxyTool.Handler)
-
-	// Tools for Proxy Group management
-	getProxyGroupsTool := proxygroups.GetProxyGroups(logger)
-	mcpServer.AddTool(getProxyGroupsTool.Tool, getProxyGroupsTool.Handler)
-
-	createProxyGroupTool := proxygroups.CreateProxyGroup(logger)
-	mcpServer.AddTool(createProxyGroupTool.Tool, createProxyGroupTool.Handler)
-
-	updateProxyGroupTool := proxygroups.UpdateProxyGroup(logger)
-	mcpServer.AddTool(updateProxyGroupTool.Tool, updateProxyGroupTool.Handler)
-
-	deleteProxyGroupTool := proxygroups.DeleteProxyGroups(logger)
-	mcpServer.AddTool(deleteProxyGroupTool.Tool, deleteProxyGroupTool.Handler)
-
-	// Tools for Problem management
-	getProblemsTool := problems.GetProblems(logger)
-	mcpServer.AddTool(getProblemsTool.Tool, getProblemsTool.Handler)
-
-	// Tools for Event management
-	getEventsTool := events.GetEvents(logger)
-	mcpServer.AddTool(getEventsTool.Tool, getEventsTool.Handler)
-
-	acknowledgeEventTool := events.AcknowledgeEvent(logger)
-	mcpServer.AddTool(acknowledgeEventTool.Tool, acknowledgeEventTool.Handler)
-
-	// Tools for Trend management
-	getTrendsTool := trends.GetTrends(logger)
-	mcpServer.AddTool(getTrendsTool.Tool, getTrendsTool.Handler)
-
-	// Tools for Alert management
-	getAlertsTool := alerts.GetAlerts(logger)
-	mcpServer.AddTool(getAlertsTool.Tool, getAlertsTool.Handler)
-
-	// Tools for User management
-	getUsersTool := users.GetUsers(logger)
-	mcpServer.AddTool(getUsersTool.Tool, getUsersTool.Handler)
-
-	createUserTool := users.CreateUser(logger)
-	mcpServer.AddTool(createUserTool.Tool, createUserTool.Handler)
-
-	updateUserTool := users.UpdateUser(logger)
-	mcpServer.AddTool(updateUserTool.Tool, updateUserTool.Handler)
-
-	deleteUserTool := users.DeleteUser(logger)
-	mcpServer.AddTool(deleteUserTool.Tool, deleteUserTool.Handler)
-
-	// Tools for User Group management
-	getUserGroupsTool := usergroups.GetUserGroups(logger)
-	mcpServer.AddTool(getUserGroupsTool.Tool, getUserGroupsTool.Handler)
-
-	createUserGroupTool := usergroups.CreateUserGroup(logger)
-	mcpServer.AddTool(createUserGroupTool.Tool, createUserGroupTool.Handler)
-
-	updateUserGroupTool := usergroups.UpdateUserGroup(logger)
-	mcpServer.AddTool(updateUserGroupTool.Tool, updateUserGroupTool.Handler)
-
-	deleteUserGroupTool := usergroups.DeleteUserGroup(logger)
-	mcpServer.AddTool(deleteUserGroupTool.Tool, deleteUserGroupTool.Handler)
-
-	// Tools for User Role management
-	getUserRolesTool := userroles.GetUserRoles(logger)
-	mcpServer.AddTool(getUserRolesTool.Tool, getUserRolesTool.Handler)
-
-	createUserRoleTool := userroles.CreateUserRole(logger)
-	mcpServer.AddTool(createUserRoleTool.Tool, createUserRoleTool.Handler)
-
-	updateUserRoleTool := userroles.UpdateUserRole(logger)
-	mcpServer.AddTool(updateUserRoleTool.Tool, updateUserRoleTool.Handler)
-
-	deleteUserRoleTool := userroles.DeleteUserRole(logger)
-	mcpServer.AddTool(deleteUserRoleTool.Tool, deleteUserRoleTool.Handler)
-
-	// Tools for User Macro management (host-level)
-	getUserMacrosTool := macros.GetUserMacros(logger)
-	mcpServer.AddTool(getUserMacrosTool.Tool, getUserMacrosTool.Handler)
-
-	createUserMacroTool := macros.CreateUserMacro(logger)
-	mcpServer.AddTool(createUserMacroTool.Tool, createUserMacroTool.Handler)
-
-	updateUserMacroTool := macros.UpdateUserMacro(logger)
-	mcpServer.AddTool(updateUserMacroTool.Tool, updateUserMacroTool.Handler)
-
-	deleteUserMacroTool := macros.DeleteUserMacro(logger)
-	mcpServer.AddTool(deleteUserMacroTool.Tool, deleteUserMacroTool.Handler)
-
-	// Tools for Global Macro management
-	getGlobalMacrosTool := macros.GetGlobalMacros(logger)
-	mcpServer.AddTool(getGlobalMacrosTool.Tool, getGlobalMacrosTool.Handler)
-
-	createGlobalMacroTool := macros.CreateGlobalMacro(logger)
-	mcpServer.AddTool(createGlobalMacroTool.Tool, createGlobalMacroTool.Handler)
-
-	updateGlobalMacroTool := macros.UpdateGlobalMacro(logger)
-	mcpServer.AddTool(updateGlobalMacroTool.Tool, updateGlobalMacroTool.Handler)
-
-	deleteGlobalMacroTool := macros.DeleteGlobalMacro(logger)
-	mcpServer.AddTool(deleteGlobalMacroTool.Tool, deleteGlobalMacroTool.Handler)
-
-	// Tools for LLD Rule management
-	getLLDRulesTool := lld.GetLLDRules(logger)
-	mcpServer.AddTool(getLLDRulesTool.Tool, getLLDRulesTool.Handler)
-
-	createLLDRuleTool := lld.CreateLLDRule(logger)
-	mcpServer.AddTool(createLLDRuleTool.Tool, createLLDRuleTool.Handler)
+// Toolset is a named group of tools. Toolset names match the pkg/tools
+// subpackages.
+type Toolset struct {
+	Name        string
+	Description string
+	// MinVersion is the oldest Zabbix version the toolset works with. Its
+	// tools are hidden from sessions connected to older servers.
+	MinVersion client.Version
+	// Local toolsets do not call Zabbix, so their tools take no instance
+	// argument
+	Local bool
+	Tools func(logger *log.Logger) []server.ServerTool
+}
 
-	updateLLDRuleTool := lld.UpdateLLDRule(logger)
-	mcpServer.AddTool(updateLLDRuleTool.Tool, updateLLDRuleTool.Handler)
+// Toolsets lists every toolset in registration order
+var Toolsets = []Toolset{
+	{
+		Name:        "hosts",
+		Description: "Host management",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				hosts.GetHosts(logger),
+				hosts.CreateHost(logger),
+				hosts.UpdateHost(logger),
+				hosts.DeleteHost(logger),
+			}
+		},
+	},
+	{
+		Name:        "items",
+		Description: "Item management and history",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				items.GetItems(logger),
+				items.CreateItem(logger),
+				items.UpdateItem(logger),
+				items.DeleteItem(logger),
+				items.GetHistory(logger),
+			}
+		},
+	},
+	{
+		Name:        "triggers",
+		Description: "Trigger management",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				triggers.GetTriggers(logger),
+				triggers.CreateTrigger(logger),
+				triggers.UpdateTrigger(logger),
+				triggers.DeleteTrigger(logger),
+			}
+		},
+	},
+	{
+		Name:        "templates",
+		Description: "Template management and linking",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				templates.GetTemplates(logger),
+				templates.LinkTemplate(logger),
+				templates.UnlinkTemplate(logger),
+				templates.CreateTemplate(logger),
+				templates.UpdateTemplate(logger),
+				templates.DeleteTemplate(logger),
+			}
+		},
+	},
+	{
+		Name:        "maintenance",
+		Description: "Maintenance windows",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				maintenance.GetMaintenance(logger),
+				maintenance.CreateMaintenance(logger),
+				maintenance.UpdateMaintenance(logger),
+				maintenance.DeleteMaintenance(logger),
+			}
+		},
+	},
+	{
+		Name:        "hostgroups",
+		Description: "Host group management",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				hostgroups.GetHostGroups(logger),
+				hostgroups.CreateHostGroup(logger),
+				hostgroups.UpdateHostGroup(logger),
+				hostgroups.DeleteHostGroup(logger),
+			}
+		},
+	},
+	{
+		Name:        "templategroups",
+		Description: "Template group management",
+		MinVersion:  client.HostGroupsVersion,
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				templategroups.GetTemplateGroups(logger),
+				templategroups.CreateTemplateGroup(logger),
+				templategroups.UpdateTemplateGroup(logger),
+				templategroups.DeleteTemplateGroup(logger),
+			}
+		},
+	},
+	{
+		Name:        "proxies",
+		Description: "Proxy management",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				proxies.GetProxies(logger),
+				proxies.CreateProxy(logger),
+				proxies.UpdateProxy(logger),
+				proxies.DeleteProxies(logger),
+			}
+		},
+	},
+	{
+		Name:        "proxygroups",
+		Description: "Proxy group management",
+		MinVersion:  client.ProxyGroupsVersion,
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				proxygroups.GetProxyGroups(logger),
+				proxygroups.CreateProxyGroup(logger),
+				proxygroups.UpdateProxyGroup(logger),
+				proxygroups.DeleteProxyGroups(logger),
+			}
+		},
+	},
+	{
+		Name:        "problems",
+		Description: "Current problems",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				problems.GetProblems(logger),
+			}
+		},
+	},
+	{
+		Name:        "events",
+		Description: "Events and acknowledgement",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				events.GetEvents(logger),
+				events.AcknowledgeEvent(logger),
+			}
+		},
+	},
+	{
+		Name:        "trends",
+		Description: "Trend data",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				trends.GetTrends(logger),
+			}
+		},
+	},
+	{
+		Name:        "alerts",
+		Description: "Alerts",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				alerts.GetAlerts(logger),
+			}
+		},
+	},
+	{
+		Name:        "users",
+		Description: "User management",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				users.GetUsers(logger),
+				users.CreateUser(logger),
+				users.UpdateUser(logger),
+				users.DeleteUser(logger),
+			}
+		},
+	},
+	{
+		Name:        "usergroups",
+		Description: "User group management",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				usergroups.GetUserGroups(logger),
+				usergroups.CreateUserGroup(logger),
+				usergroups.UpdateUserGroup(logger),
+				usergroups.DeleteUserGroup(logger),
+			}
+		},
+	},
+	{
+		Name:        "userroles",
+		Description: "User role management",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				userroles.GetUserRoles(logger),
+				userroles.CreateUserRole(logger),
+				userroles.UpdateUserRole(logger),
+				userroles.DeleteUserRole(logger),
+			}
+		},
+	},
+	{
+		Name:        "macros",
+		Description: "Host and global user macros",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				macros.GetUserMacros(logger),
+				macros.CreateUserMacro(logger),
+				macros.UpdateUserMacro(logger),
+				macros.DeleteUserMacro(logger),
+				macros.GetGlobalMacros(logger),
+				macros.CreateGlobalMacro(logger),
+				macros.UpdateGlobalMacro(logger),
+				macros.DeleteGlobalMacro(logger),
+			}
+		},
+	},
+	{
+		Name:        "lld",
+		Description: "Low-level discovery rules",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				lld.GetLLDRules(logger),
+				lld.CreateLLDRule(logger),
+				lld.UpdateLLDRule(logger),
+				lld.DeleteLLDRule(logger),
+				lld.CopyLLDRule(logger),
+			}
+		},
+	},
+	{
+		Name:        "itemprototypes",
+		Description: "Item prototypes",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				itemprototypes.GetItemPrototypes(logger),
+				itemprototypes.CreateItemPrototype(logger),
+				itemprototypes.UpdateItemPrototype(logger),
+				itemprototypes.DeleteItemPrototype(logger),
+			}
+		},
+	},
+	{
+		Name:        "triggerprototypes",
+		Description: "Trigger prototypes",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				triggerprototypes.GetTriggerPrototypes(logger),
+				triggerprototypes.CreateTriggerPrototype(logger),
+				triggerprototypes.UpdateTriggerPrototype(logger),
+				triggerprototypes.DeleteTriggerPrototype(logger),
+			}
+		},
+	},
+	{
+		Name:        "auditlog",
+		Description: "Zabbix audit log",
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				auditlog.GetAuditLog(logger),
+			}
+		},
+	},
+	{
+		Name:        "mcpaudit",
+		Description: "MCP server audit trail of mutating tool calls",
+		Local:       true,
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				mcpaudit.GetMCPAuditLog(logger),
+			}
+		},
+	},
+	{
+		Name:        "instances",
+		Description: "Named Zabbix instances",
+		Local:       true,
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				instances.ListZabbixInstances(logger),
+			}
+		},
+	},
+	{
+		Name:        "docs",
+		Description: "Zabbix API documentation search",
+		Local:       true,
+		Tools: func(logger *log.Logger) []server.ServerTool {
+			return []server.ServerTool{
+				docs.GetZabbixDocs(logger),
+			}
+		},
+	},
+}
 
-	deleteLLDRuleTool := lld.DeleteLLDRule(logger)
-	mcpServer.AddTool(deleteLLDRuleTool.Tool, deleteLLDRuleTool.Handler)
+// Config controls which tools are registered
+type Config struct {
+	// ReadOnly registers only tools that do not modify Zabbix
+	ReadOnly bool
+	// Toolsets restricts registration to the named toolsets. When both
+	// Toolsets and EnableTools are empty every toolset is enabled.
+	Toolsets []string
+	// EnableTools adds individual tools on top of the enabled toolsets
+	EnableTools []string
+	// DisableTools removes individual tools
+	DisableTools []string
+	// Instances are the names of the configured Zabbix instances. When set,
+	// every tool that calls Zabbix takes an optional instance argument.
+	Instances []string
+	// DryRun makes every call of a mutating tool a dry run
+	DryRun bool
+}
 
-	copyLLDRuleTool := lld.CopyLLDRule(logger)
-	mcpServer.AddTool(copyLLDRuleTool.Tool, copyLLDRuleTool.Handler)
+// SelectedTool is a tool chosen for registration along with its toolset
+type SelectedTool struct {
+	Toolset string
+	server.ServerTool
+}
 
-	// Tools for Item Prototype management
-	getItemPrototypesTool := itemprototypes.GetItemPrototypes(logger)
-	mcpServer.AddTool(getItemPrototypesTool.Tool, getItemPrototypesTool.Handler)
+// InitTools registers the Zabbix MCP tools allowed by config with the server
+func InitTools(mcpServer *server.MCPServer, logger *log.Logger, config Config) error {
+	if config.ReadOnly {
+		logger.Info("Read-only mode: registering only non-mutating tools")
+	} else if config.DryRun {
+		logger.Info("Dry-run mode: mutating tools only preview their changes")
+	}
+
+	selected, err := SelectTools(logger, config)
+	if err != nil {
+		return err
+	}
+
+	for _, tool := range selected {
+		mcpServer.AddTool(tool.Tool, tool.Handler)
+	}
+
+	logger.WithField("tool_count", len(selected)).Info("Registered Zabbix MCP tools")
+	return nil
+}
 
-	createItemPrototypeTool := itemprototypes.CreateItemPrototype(logger)
-	mcpServer.AddTool(createItemPrototypeTool.Tool, createItemPrototypeTool.Handler)
+// SelectTools returns the tools config allows, in registration order.
+// Unknown toolset or tool names are reported as errors.
+func SelectTools(logger *log.Logger, config Config) ([]SelectedTool, error) {
+	enabledSets := make(map[string]bool)
+	for _, name := range config.Toolsets {
+		enabledSets[name] = true
+	}
+	enabledTools := make(map[string]bool)
+	for _, name := range config.EnableTools {
+		enabledTools[name] = true
+	}
+	disabledTools := make(map[string]bool)
+	for _, name := range config.DisableTools {
+		disabledTools[name] = true
+	}
+	allEnabled := len(enabledSets) == 0 && len(enabledTools) == 0
+
+	knownTools := make(map[string]bool)
+	var selected []SelectedTool
+	for _, set := range Toolsets {
+		setEnabled := allEnabled || enabledSets[set.Name]
+		delete(enabledSets, set.Name)
+
+		for _, tool := range set.Tools(logger) {
+			name := tool.Tool.Name
+			knownTools[name] = true
+
+			if err := ValidateAnnotations(tool.Tool); err != nil {
+				return nil, err
+			}
+
+			if !setEnabled && !enabledTools[name] {
+				continue
+			}
+			if disabledTools[name] {
+				continue
+			}
+			if config.ReadOnly && !IsReadOnlyTool(name) {
+				continue
+			}
+			if !IsReadOnlyTool(name) {
+				tool = withDryRun(tool, config.DryRun)
+			}
+			if len(config.Instances) > 0 && !set.Local {
+				tool = withInstanceArgument(tool, config.Instances)
+			}
+			selected = append(selected, SelectedTool{Toolset: set.Name, ServerTool: tool})
+		}
+	}
+
+	for name := range enabledSets {
+		return nil, fmt.Errorf("unknown toolset %q", name)
+	}
+	for _, name := range append(config.EnableTools, config.DisableTools...) {
+		if !knownTools[name] {
+			return nil, fmt.Errorf("unknown tool %q", name)
+		}
+	}
+
+	return selected, nil
+}
 
-	updateItemPrototypeTool := itemprototypes.UpdateItemPrototype(logger)
-	mcpServer.AddTool(updateItemPrototypeTool.Tool, updateItemPrototypeTool.Handler)
+// federatedTools can query several instances in one call with the
+// instances argument
+var federatedTools = map[string]bool{
+	"get_hosts":    true,
+	"get_problems": true,
+	"get_events":   true,
+}
 
-	deleteItemPrototypeTool := itemprototypes.DeleteItemPrototype(logger)
-	mcpServer.AddTool(deleteItemPrototypeTool.Tool, deleteItemPrototypeTool.Handler)
+// withInstanceArgument adds the optional instance argument to tool and routes
+// its calls to the named instance
+func withInstanceArgument(tool server.ServerTool, names []string) server.ServerTool {
+	tool.Tool.InputSchema.Properties["instance"] = map[string]any{
+		"type":        "string",
+		"description": "Name of the Zabbix instance to query (see list_zabbix_instances). Defaults to the configured default instance, or the session's Zabbix server.",
+		"enum":        names,
+	}
+
+	if federatedTools[tool.Tool.Name] {
+		tool.Tool.InputSchema.Properties[federation.Argument] = map[string]any{
+			"type":        "string",
+			"description": "Comma-separated instance names to query in parallel, or \"all\". Results are merged and tagged with their instance; instances that fail are listed under failures. The limit applies to the merged results.",
+		}
+	}
+
+	handler := tool.Handler
+	tool.Handler = func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if name, ok := req.GetArguments()["instance"].(string); ok && name != "" {
+			ctx = client.WithInstance(ctx, name)
+		}
+		return handler(ctx, req)
+	}
+	return tool
+}
 
-	// Tools for Trigger Prototype management
-	getTriggerPrototypesTool := triggerprototypes.GetTriggerPrototypes(logger)
-	mcpServer.AddTool(getTriggerPrototypesTool.Tool, getTriggerPrototypesTool.Handler)
+// DryRunArgument is the boolean argument of mutating tools that previews a
+// call instead of making it
+const DryRunArgument = "dry_run"
+
+// dryRunPreview is the result of a dry run
+type dryRunPreview struct {
+	DryRun bool `json:"dry_run"`
+	// Valid is false when the call refers to objects that do not exist
+	Valid   bool     `json:"valid"`
+	Summary []string `json:"summary"`
+	// Requests are the JSON-RPC requests the call would send
+	Requests   []client.PlannedCall `json:"requests"`
+	References []client.Reference   `json:"references,omitempty"`
+	Errors     []string             `json:"errors,omitempty"`
+}
 
-	createTriggerPrototypeTool := triggerprototypes.CreateTriggerPrototype(logger)
-	mcpServer.AddTool(createTriggerPrototypeTool.Tool, createTriggerPrototypeTool.Handler)
+// withDryRun adds the dry_run argument to a mutating tool. A dry run
+// validates the arguments and looks up the objects the call refers to, then
+// returns the requests it would send instead of sending them. always makes
+// every call a dry run, so the argument is left out.
+func withDryRun(tool server.ServerTool, always bool) server.ServerTool {
+	if !always {
+		tool.Tool.InputSchema.Properties[DryRunArgument] = map[string]any{
+			"type":        "boolean",
+			"description": "Preview the call: validate it and return the Zabbix API requests it would send with a summary of the changes, without making them",
+		}
+	}
+
+	handler := tool.Handler
+	tool.Handler = func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if dryRun, _ := req.GetArguments()[DryRunArgument].(bool); !dryRun && !always {
+			return handler(ctx, req)
+		}
+
+		ctx, plan := client.WithDryRun(ctx)
+		result, err := handler(ctx, req)
+		if err != nil || result == nil || result.IsError {
+			return result, err
+		}
+
+		refs, err := plan.Resolve(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Dry run failed: %v", err)), nil
+		}
+		preview := dryRunPreview{
+			DryRun:     true,
+			Valid:      true,
+			Summary:    plan.Summary(refs),
+			Requests:   plan.Calls(),
+			References: refs,
+		}
+		for _, ref := range refs {
+			if !ref.Found {
+				preview.Valid = false
+				preview.Errors = append(preview.Errors, fmt.Sprintf("%s %s does not exist", ref.Type, ref.ID))
+			}
+		}
+		if preview.Summary == nil {
+			preview.Summary = []string{}
+		}
+		if preview.Requests == nil {
+			preview.Requests = []client.PlannedCall{}
+		}
+
+		jsonData, err := json.MarshalIndent(preview, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error marshaling JSON: %v", err)), nil
+		}
+		if !preview.Valid {
+			return mcp.NewToolResultError(string(jsonData)), nil
+		}
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+	return tool
+}
 
-	updateTriggerPrototypeTool := triggerprototypes.UpdateTriggerPrototype(logger)
-	mcpServer.AddTool(updateTriggerPrototypeTool.Tool, updateTriggerPrototypeTool.Handler)
+// VersionFilter returns a tools/list filter that hides tools whose toolset
+// needs a newer Zabbix than the session's clients are connected to. When
+// tools take an instance argument, a tool stays listed while any instance
+// it can target may support it.
+func VersionFilter(logger *log.Logger) server.ToolFilterFunc {
+	minVersions := make(map[string]client.Version)
+	for _, set := range Toolsets {
+		if set.MinVersion.IsZero() {
+			continue
+		}
+		for _, tool := range set.Tools(logger) {
+			minVersions[tool.Tool.Name] = set.MinVersion
+		}
+	}
+
+	return func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+		session := server.ClientSessionFromContext(ctx)
+		if session == nil {
+			return tools
+		}
+		versions := client.SessionVersions(session.SessionID())
+
+		filtered := make([]mcp.Tool, 0, len(tools))
+		for _, tool := range tools {
+			if min, ok := minVersions[tool.Name]; ok && !anyAtLeast(versions, min) {
+				continue
+			}
+			filtered = append(filtered, tool)
+		}
+		return filtered
+	}
+}
 
-	deleteTriggerPrototypeTool := triggerprototypes.DeleteTriggerPrototype(logger)
-	mcpServer.AddTool(deleteTriggerPrototypeTool.Tool, deleteTriggerPrototypeTool.Handler)
+// anyAtLeast reports whether any of versions is min or newer
+func anyAtLeast(versions []client.Version, min client.Version) bool {
+	for _, version := range versions {
+		if version.AtLeast(min) {
+			return true
+		}
+	}
+	return false
+}
 
-	// Tools for Audit Log
-	getAuditLogTool := auditlog.GetAuditLog(logger)
-	mcpServer.AddTool(getAuditLogTool.Tool, getAuditLogTool.Handler)
+// ValidateAnnotations checks that a tool declares a title and every behavior
+// hint, and that its read-only hint agrees with its name, so MCP clients can
+// tell reads from destructive operations
+func ValidateAnnotations(tool mcp.Tool) error {
+	a := tool.Annotations
+	switch {
+	case a.Title == "":
+		return fmt.Errorf("tool %q has no title annotation", tool.Name)
+	case a.ReadOnlyHint == nil, a.DestructiveHint == nil, a.IdempotentHint == nil, a.OpenWorldHint == nil:
+		return fmt.Errorf("tool %q is missing behavior hint annotations", tool.Name)
+	case *a.ReadOnlyHint != IsReadOnlyTool(tool.Name):
+		return fmt.Errorf("tool %q has a read-only hint that does not match its name", tool.Name)
+	case *a.ReadOnlyHint && *a.DestructiveHint:
+		return fmt.Errorf("tool %q is annotated as both read-only and destructive", tool.Name)
+	}
+	return nil
+}
 
-	// Tools for Documentation
-	getZabbixDocsTool := docs.GetZabbixDocs(logger)
-	mcpServer.AddTool(getZabbixDocsTool.Tool, getZabbixDocsTool.Handler)
+// IsReadOnlyTool reports whether a tool only reads from Zabbix. All such
+// tools are named get_*, list_* or zabbix_get_*.
+func IsReadOnlyTool(name string) bool {
+	return strings.HasPrefix(name, "get_") || strings.HasPrefix(name, "list_") || strings.HasPrefix(name, "zabbix_get_")
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -36,7 +37,15 @@ type TrendGetParams struct {
 func GetTrends(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_trends",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Trends",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Retrieve trend values calculated by Zabbix server for presentation or further processing. Trends are hourly aggregated data (min, avg, max)."),
 			mcp.WithString("itemids", mcp.Description("Comma-separated list of item IDs to get trends for"), mcp.Required()),
 			mcp.WithNumber("time_from", mcp.Description("Return only trends after this Unix timestamp")),
@@ -44,7 +53,7 @@ func GetTrends(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("limit", mcp.Description("Max trend records to return (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getTrendsHandler(ctx, req, logger)
+			return getTrendsHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -81,7 +90,7 @@ func getTrendsHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.
 		params.Limit = int(v)
 	}
 
-	result, err := zabbix.Call("trend.get", params)
+	result, err := zabbix.CallContext(ctx, "trend.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get trends: %v", err)), nil
 	}
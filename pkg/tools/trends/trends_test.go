@@ -0,0 +1,47 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package trends_test
+
+import (
+	"testing"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/trends"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func TestGetTrends(t *testing.T) {
+	zabbixtest.RunToolCases(t, trends.GetTrends(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing itemids",
+			Args:      map[string]interface{}{},
+			WantError: "itemids is required",
+		},
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{"itemids": "23456"},
+			Method: "trend.get",
+			Params: `{"output":"extend","itemids":["23456"],"limit":100}`,
+		},
+		{
+			Name:   "time range",
+			Args:   map[string]interface{}{"itemids": "23456, 23457", "time_from": float64(1700000000), "time_till": float64(1700086400), "limit": float64(24)},
+			Method: "trend.get",
+			Params: `{"output":"extend","itemids":["23456","23457"],"time_from":1700000000,"time_till":1700086400,"limit":24}`,
+		},
+		{
+			Name: "returns model trends",
+			Setup: func(t *testing.T, s *zabbixtest.Server) {
+				s.Model.Add(zabbixtest.Trends, zabbixtest.Object{"itemid": "23456", "clock": "1700000000", "num": "60", "value_avg": "42.5"})
+			},
+			Args:     map[string]interface{}{"itemids": "23456"},
+			WantText: "42.5",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("trend.get"),
+			Args:      map[string]interface{}{"itemids": "23456"},
+			WantError: "Failed to get trends",
+		},
+	})
+}
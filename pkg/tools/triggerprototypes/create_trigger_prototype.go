@@ -12,6 +12,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type TriggerPrototypeCreateParams struct {
@@ -27,6 +29,15 @@ type TriggerPrototypeCreateParams struct {
 func CreateTriggerPrototype(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("create_trigger_prototype",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create Trigger Prototype",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new trigger prototype in Zabbix."),
 			mcp.WithString("description", mcp.Description("Trigger prototype name/description"), mcp.Required()),
 			mcp.WithString("expression", mcp.Description("Trigger expression"), mcp.Required()),
@@ -36,7 +47,7 @@ func CreateTriggerPrototype(logger *log.Logger) server.ServerTool {
 			mcp.WithString("comments", mcp.Description("Comments/description")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return createTriggerPrototypeHandler(ctx, req, logger)
+			return createTriggerPrototypeHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -77,7 +88,7 @@ func createTriggerPrototypeHandler(ctx context.Context, req mcp.CallToolRequest,
 		params.Comments = v
 	}
 
-	result, err := zabbix.Call("triggerprototype.create", params)
+	result, err := zabbix.CallContext(ctx, "triggerprototype.create", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create trigger prototype: %v", err)), nil
 	}
@@ -13,16 +13,27 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func DeleteTriggerPrototype(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("delete_trigger_prototype",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete Trigger Prototype",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete trigger prototypes from Zabbix."),
 			mcp.WithString("triggerids", mcp.Description("Comma-separated list of trigger prototype IDs to delete"), mcp.Required()),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return deleteTriggerPrototypeHandler(ctx, req, logger)
+			return deleteTriggerPrototypeHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -50,7 +61,7 @@ func deleteTriggerPrototypeHandler(ctx context.Context, req mcp.CallToolRequest,
 		}
 	}
 
-	result, err := zabbix.Call("triggerprototype.delete", triggerids)
+	result, err := zabbix.CallContext(ctx, "triggerprototype.delete", triggerids)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete trigger prototypes: %v", err)), nil
 	}
@@ -13,6 +13,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -44,7 +45,15 @@ type TriggerPrototypeGetParams struct {
 func GetTriggerPrototypes(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_trigger_prototypes",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Trigger Prototypes",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Retrieve trigger prototypes from Zabbix."),
 			mcp.WithString("triggerids", mcp.Description("Comma-separated list of trigger prototype IDs to filter by")),
 			mcp.WithString("hostids", mcp.Description("Comma-separated list of host IDs to filter by")),
@@ -55,7 +64,7 @@ func GetTriggerPrototypes(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("limit", mcp.Description("Max trigger prototypes to return (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getTriggerPrototypesHandler(ctx, req, logger)
+			return getTriggerPrototypesHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -98,7 +107,7 @@ func getTriggerPrototypesHandler(ctx context.Context, req mcp.CallToolRequest, l
 		}
 	}
 
-	result, err := zabbix.Call("triggerprototype.get", params)
+	result, err := zabbix.CallContext(ctx, "triggerprototype.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get trigger prototypes: %v", err)), nil
 	}
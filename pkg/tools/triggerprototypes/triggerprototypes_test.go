@@ -0,0 +1,100 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package triggerprototypes_test
+
+import (
+	"testing"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/triggerprototypes"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addPrototype(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add("triggerprototype", zabbixtest.Object{"triggerid": "40020", "hostid": "10084", "description": "Low space on {#FSNAME}", "priority": "2"})
+}
+
+func TestGetTriggerPrototypes(t *testing.T) {
+	zabbixtest.RunToolCases(t, triggerprototypes.GetTriggerPrototypes(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "triggerprototype.get",
+			Params: `{"output":"extend","selectDiscoveryRule":"extend","selectTags":"extend","limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"hostids": "10084", "search": "space", "min_severity": float64(0), "limit": float64(5)},
+			Method: "triggerprototype.get",
+			Params: `{"output":"extend","hostids":["10084"],"search":{"description":"space"},"selectDiscoveryRule":"extend","selectTags":"extend","min_severity":0,"limit":5}`,
+		},
+		{
+			Name:     "returns model prototypes",
+			Setup:    addPrototype,
+			Args:     map[string]interface{}{"hostids": "10084"},
+			WantText: "Low space on {#FSNAME}",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("triggerprototype.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Failed to get trigger prototypes",
+		},
+	})
+}
+
+func TestCreateTriggerPrototype(t *testing.T) {
+	zabbixtest.RunToolCases(t, triggerprototypes.CreateTriggerPrototype(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing expression",
+			Args:      map[string]interface{}{"description": "Low space"},
+			WantError: "description and expression are required",
+		},
+		{
+			Name: "create",
+			Args: map[string]interface{}{
+				"description": "Low space on {#FSNAME}", "expression": "last(/web01/vfs.fs.size[{#FSNAME},pfree])<10",
+				"priority": float64(2), "recovery_mode": float64(1), "recovery_expression": "last(/web01/vfs.fs.size[{#FSNAME},pfree])>20",
+			},
+			Method:   "triggerprototype.create",
+			Params:   `{"description":"Low space on {#FSNAME}","expression":"last(/web01/vfs.fs.size[{#FSNAME},pfree])<10","priority":2,"recovery_mode":1,"recovery_expression":"last(/web01/vfs.fs.size[{#FSNAME},pfree])>20"}`,
+			WantText: "Trigger prototype created",
+		},
+	})
+}
+
+func TestUpdateTriggerPrototype(t *testing.T) {
+	zabbixtest.RunToolCases(t, triggerprototypes.UpdateTriggerPrototype(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing triggerid",
+			Args:      map[string]interface{}{"priority": float64(1)},
+			WantError: "triggerid is required",
+		},
+		{
+			Name:     "update",
+			Setup:    addPrototype,
+			Args:     map[string]interface{}{"triggerid": "40020", "priority": float64(0), "status": float64(1)},
+			Method:   "triggerprototype.update",
+			Params:   `{"triggerid":"40020","priority":0,"status":1}`,
+			WantText: "Trigger prototype updated",
+		},
+	})
+}
+
+func TestDeleteTriggerPrototype(t *testing.T) {
+	zabbixtest.RunToolCases(t, triggerprototypes.DeleteTriggerPrototype(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing triggerids",
+			Args:      map[string]interface{}{},
+			WantError: "triggerids is required",
+		},
+		{
+			Name:     "delete",
+			Setup:    addPrototype,
+			Args:     map[string]interface{}{"triggerids": "40020"},
+			Method:   "triggerprototype.delete",
+			Params:   `["40020"]`,
+			WantText: "Trigger prototypes deleted",
+		},
+	})
+}
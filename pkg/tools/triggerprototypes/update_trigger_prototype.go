@@ -12,6 +12,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type TriggerPrototypeUpdateParams struct {
@@ -27,6 +29,15 @@ type TriggerPrototypeUpdateParams struct {
 func UpdateTriggerPrototype(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("update_trigger_prototype",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update Trigger Prototype",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Update an existing trigger prototype in Zabbix."),
 			mcp.WithString("triggerid", mcp.Description("Trigger prototype ID to update"), mcp.Required()),
 			mcp.WithString("description", mcp.Description("New description")),
@@ -37,7 +48,7 @@ func UpdateTriggerPrototype(logger *log.Logger) server.ServerTool {
 			mcp.WithString("recovery_expression", mcp.Description("New recovery expression")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return updateTriggerPrototypeHandler(ctx, req, logger)
+			return updateTriggerPrototypeHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -81,7 +92,7 @@ func updateTriggerPrototypeHandler(ctx context.Context, req mcp.CallToolRequest,
 		params.RecoveryExpression = v
 	}
 
-	result, err := zabbix.Call("triggerprototype.update", params)
+	result, err := zabbix.CallContext(ctx, "triggerprototype.update", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update trigger prototype: %v", err)), nil
 	}
@@ -13,11 +13,22 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func CreateTrigger(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("create_trigger",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create Trigger",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new trigger in Zabbix."),
 			mcp.WithString("description", mcp.Required(), mcp.Description("Trigger name")),
 			mcp.WithString("expression", mcp.Required(), mcp.Description("Trigger expression")),
@@ -26,7 +37,7 @@ func CreateTrigger(logger *log.Logger) server.ServerTool {
 			mcp.WithString("tags", mcp.Description("Tags in format key:value,key2:value2")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return createTriggerHandler(ctx, req, logger)
+			return createTriggerHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -69,7 +80,7 @@ func createTriggerHandler(ctx context.Context, req mcp.CallToolRequest, logger *
 		params.Tags = tags
 	}
 
-	result, err := zabbix.Call("trigger.create", params)
+	result, err := zabbix.CallContext(ctx, "trigger.create", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed: %v", err)), nil
 	}
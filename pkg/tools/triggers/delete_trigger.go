@@ -12,16 +12,27 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func DeleteTrigger(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("delete_trigger",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete Trigger",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete triggers from Zabbix."),
 			mcp.WithString("triggerids", mcp.Required(), mcp.Description("Comma-separated trigger IDs")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return deleteTriggerHandler(ctx, req, logger)
+			return deleteTriggerHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -42,7 +53,7 @@ func deleteTriggerHandler(ctx context.Context, req mcp.CallToolRequest, logger *
 		return mcp.NewToolResultError("triggerids required"), nil
 	}
 
-	result, err := zabbix.Call("trigger.delete", splitAndTrim(triggeridsStr))
+	result, err := zabbix.CallContext(ctx, "trigger.delete", splitAndTrim(triggeridsStr))
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed: %v", err)), nil
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -27,7 +28,15 @@ type Trigger struct {
 func GetTriggers(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_triggers",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Triggers",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("List triggers from Zabbix."),
 			mcp.WithString("triggerids", mcp.Description("Comma-separated trigger IDs")),
 			mcp.WithString("hostids", mcp.Description("Comma-separated host IDs")),
@@ -35,7 +44,7 @@ func GetTriggers(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("limit", mcp.Description("Max triggers (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getTriggersHandler(ctx, req, logger)
+			return getTriggersHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -63,7 +72,7 @@ func getTriggersHandler(ctx context.Context, req mcp.CallToolRequest, logger *lo
 		}
 	}
 
-	result, err := zabbix.Call("trigger.get", params)
+	result, err := zabbix.CallContext(ctx, "trigger.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed: %v", err)), nil
 	}
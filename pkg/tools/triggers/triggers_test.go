@@ -0,0 +1,131 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package triggers_test
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/triggers"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addTrigger(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add(zabbixtest.Triggers, zabbixtest.Object{"triggerid": "16001", "hostid": "10084", "description": "High CPU", "priority": "4"})
+}
+
+func TestGetTriggers(t *testing.T) {
+	zabbixtest.RunToolCases(t, triggers.GetTriggers(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "trigger.get",
+			Params: `{"output":"extend","selectHosts":"extend","selectTags":"extend","limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"triggerids": "16001", "hostids": "10084,10085", "min_severity": float64(3), "limit": float64(20)},
+			Method: "trigger.get",
+			Params: `{"output":"extend","triggerids":["16001"],"hostids":["10084","10085"],"min_severity":3,"selectHosts":"extend","selectTags":"extend","limit":20}`,
+		},
+		{
+			Name:     "returns model triggers",
+			Setup:    addTrigger,
+			Args:     map[string]interface{}{"hostids": "10084"},
+			WantText: "High CPU",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("trigger.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Injected failure.",
+		},
+	})
+}
+
+func TestCreateTrigger(t *testing.T) {
+	zabbixtest.RunToolCases(t, triggers.CreateTrigger(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing expression",
+			Args:      map[string]interface{}{"description": "High CPU"},
+			WantError: "description and expression required",
+		},
+		{
+			Name:   "minimal",
+			Args:   map[string]interface{}{"description": "High CPU", "expression": "last(/web01/system.cpu.util)>90"},
+			Method: "trigger.create",
+			Params: `{"description":"High CPU","expression":"last(/web01/system.cpu.util)>90"}`,
+		},
+		{
+			Name:     "all fields",
+			Args:     map[string]interface{}{"description": "High CPU", "expression": "1=1", "priority": float64(4), "comments": "runbook", "tags": "scope:performance"},
+			Method:   "trigger.create",
+			Params:   `{"description":"High CPU","expression":"1=1","priority":4,"comments":"runbook","tags":[{"tag":"scope","value":"performance"}]}`,
+			WantText: "Trigger created",
+		},
+	})
+}
+
+func TestUpdateTrigger(t *testing.T) {
+	zabbixtest.RunToolCases(t, triggers.UpdateTrigger(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing triggerid",
+			Args:      map[string]interface{}{"priority": float64(1)},
+			WantError: "triggerid required",
+		},
+		{
+			Name:   "priority and status",
+			Setup:  addTrigger,
+			Args:   map[string]interface{}{"triggerid": "16001", "priority": float64(0), "status": float64(1)},
+			Method: "trigger.update",
+			Params: `{"triggerid":"16001","priority":0,"status":1}`,
+		},
+		{
+			Name:     "rename and tags",
+			Setup:    addTrigger,
+			Args:     map[string]interface{}{"triggerid": "16001", "description": "CPU", "tags": "a:b,c"},
+			Method:   "trigger.update",
+			Params:   `{"triggerid":"16001","description":"CPU","tags":[{"tag":"a","value":"b"},{"tag":"c","value":""}]}`,
+			WantText: "Trigger updated",
+		},
+		{
+			Name: "reports changes",
+			Setup: func(t *testing.T, s *zabbixtest.Server) {
+				s.Model.Add(zabbixtest.Triggers, zabbixtest.Object{
+					"triggerid": "16001", "hostid": "10084", "description": "High CPU", "priority": "4", "value": "1",
+				})
+			},
+			Args: map[string]interface{}{"triggerid": "16001", "priority": float64(5)},
+			Check: func(t *testing.T, s *zabbixtest.Server, result *mcp.CallToolResult) {
+				zabbixtest.AssertChanges(t, result, `[{"field":"priority","change":"changed","before":"4","after":5}]`)
+			},
+		},
+		{
+			Name:     "diff unavailable",
+			Setup:    func(t *testing.T, s *zabbixtest.Server) { addTrigger(t, s); zabbixtest.Failing("trigger.get")(t, s) },
+			Args:     map[string]interface{}{"triggerid": "16001", "priority": float64(5)},
+			Method:   "trigger.update",
+			WantText: `"changes_unavailable": "trigger.get before the update failed`,
+		},
+	})
+}
+
+func TestDeleteTrigger(t *testing.T) {
+	zabbixtest.RunToolCases(t, triggers.DeleteTrigger(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing triggerids",
+			Args:      map[string]interface{}{},
+			WantError: "triggerids required",
+		},
+		{
+			Name:     "delete",
+			Setup:    addTrigger,
+			Args:     map[string]interface{}{"triggerids": " 16001 "},
+			Method:   "trigger.delete",
+			Params:   `["16001"]`,
+			WantText: "Triggers deleted",
+		},
+	})
+}
@@ -13,12 +13,24 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/diff"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func UpdateTrigger(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("update_trigger",
-			mcp.WithDescription("Update an existing trigger in Zabbix."),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update Trigger",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
+			mcp.WithDescription("Update an existing trigger in Zabbix. Returns the fields that changed, with their old and new values."),
 			mcp.WithString("triggerid", mcp.Required(), mcp.Description("Trigger ID")),
 			mcp.WithString("description", mcp.Description("New name")),
 			mcp.WithNumber("priority", mcp.Description("New priority (0-5)")),
@@ -26,11 +38,24 @@ func UpdateTrigger(logger *log.Logger) server.ServerTool {
 			mcp.WithString("tags", mcp.Description("Tags in format key:value,key2:value2")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return updateTriggerHandler(ctx, req, logger)
+			return updateTriggerHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
 
+// triggerDiff fetches a trigger with its tags and dependencies. Its value
+// and state follow the problem, not the update.
+var triggerDiff = diff.Getter{
+	Method:   "trigger.get",
+	IDsParam: "triggerids",
+	Params: map[string]interface{}{
+		"expandExpression":   true,
+		"selectTags":         "extend",
+		"selectDependencies": []string{"triggerid", "description"},
+	},
+	Ignore: []string{"value", "lastchange", "state", "error"},
+}
+
 func updateTriggerHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	zabbix, err := client.GetZabbixClientFromContext(ctx, logger)
 	if err != nil {
@@ -73,7 +98,9 @@ func updateTriggerHandler(ctx context.Context, req mcp.CallToolRequest, logger *
 		params.Tags = tags
 	}
 
-	result, err := zabbix.Call("trigger.update", params)
+	update := triggerDiff.Track(ctx, zabbix, triggerid)
+
+	result, err := zabbix.CallContext(ctx, "trigger.update", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed: %v", err)), nil
 	}
@@ -82,6 +109,11 @@ func updateTriggerHandler(ctx context.Context, req mcp.CallToolRequest, logger *
 		TriggerIDs []string `json:"triggerids"`
 	}
 	json.Unmarshal(result, &response)
-	jsonData, _ := json.MarshalIndent(map[string]interface{}{"message": "Trigger updated", "triggerids": response.TriggerIDs}, "", "  ")
+	responseData := map[string]interface{}{"message": "Trigger updated", "triggerids": response.TriggerIDs}
+	if err := update.Report(ctx, responseData); err != nil {
+		logger.WithError(err).Warn("Failed to compare trigger after update")
+	}
+
+	jsonData, _ := json.MarshalIndent(responseData, "", "  ")
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
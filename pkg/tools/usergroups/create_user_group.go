@@ -12,6 +12,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type UserGroupCreateParams struct {
@@ -24,6 +26,15 @@ type UserGroupCreateParams struct {
 func CreateUserGroup(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("create_user_group",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create User Group",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new user group in Zabbix."),
 			mcp.WithString("name", mcp.Description("Name of the user group"), mcp.Required()),
 			mcp.WithNumber("gui_access", mcp.Description("GUI access: 0=system default, 1=internal auth, 2=LDAP, 3=disabled")),
@@ -31,7 +42,7 @@ func CreateUserGroup(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("debug_mode", mcp.Description("Debug mode: 0=disabled, 1=enabled")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return createUserGroupHandler(ctx, req, logger)
+			return createUserGroupHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -64,7 +75,7 @@ func createUserGroupHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		params.DebugMode = int(v)
 	}
 
-	result, err := zabbix.Call("usergroup.create", params)
+	result, err := zabbix.CallContext(ctx, "usergroup.create", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create user group: %v", err)), nil
 	}
@@ -13,16 +13,27 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func DeleteUserGroup(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("delete_user_group",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete User Group",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete user groups from Zabbix."),
 			mcp.WithString("usrgrpids", mcp.Description("Comma-separated list of user group IDs to delete"), mcp.Required()),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return deleteUserGroupHandler(ctx, req, logger)
+			return deleteUserGroupHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -50,7 +61,7 @@ func deleteUserGroupHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		}
 	}
 
-	result, err := zabbix.Call("usergroup.delete", usrgrpids)
+	result, err := zabbix.CallContext(ctx, "usergroup.delete", usrgrpids)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete user groups: %v", err)), nil
 	}
@@ -13,6 +13,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -36,7 +37,15 @@ type UserGroupGetParams struct {
 func GetUserGroups(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_user_groups",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get User Groups",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Retrieve user groups from Zabbix."),
 			mcp.WithString("usrgrpids", mcp.Description("Comma-separated list of user group IDs to filter by")),
 			mcp.WithString("userids", mcp.Description("Comma-separated list of user IDs to filter by")),
@@ -44,7 +53,7 @@ func GetUserGroups(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("limit", mcp.Description("Max user groups to return (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getUserGroupsHandler(ctx, req, logger)
+			return getUserGroupsHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -76,7 +85,7 @@ func getUserGroupsHandler(ctx context.Context, req mcp.CallToolRequest, logger *
 		}
 	}
 
-	result, err := zabbix.Call("usergroup.get", params)
+	result, err := zabbix.CallContext(ctx, "usergroup.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get user groups: %v", err)), nil
 	}
@@ -12,6 +12,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type UserGroupUpdateParams struct {
@@ -25,6 +27,15 @@ type UserGroupUpdateParams struct {
 func UpdateUserGroup(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("update_user_group",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update User Group",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Update an existing user group in Zabbix."),
 			mcp.WithString("usrgrpid", mcp.Description("User group ID to update"), mcp.Required()),
 			mcp.WithString("name", mcp.Description("New name of the user group")),
@@ -33,7 +44,7 @@ func UpdateUserGroup(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("debug_mode", mcp.Description("Debug mode: 0=disabled, 1=enabled")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return updateUserGroupHandler(ctx, req, logger)
+			return updateUserGroupHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -72,7 +83,7 @@ func updateUserGroupHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		params.DebugMode = &val
 	}
 
-	result, err := zabbix.Call("usergroup.update", params)
+	result, err := zabbix.CallContext(ctx, "usergroup.update", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update user group: %v", err)), nil
 	}
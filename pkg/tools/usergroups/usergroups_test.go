@@ -0,0 +1,97 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package usergroups_test
+
+import (
+	"testing"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/usergroups"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addUserGroup(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add("usergroup", zabbixtest.Object{"usrgrpid": "7", "name": "Operators", "gui_access": "0"})
+}
+
+func TestGetUserGroups(t *testing.T) {
+	zabbixtest.RunToolCases(t, usergroups.GetUserGroups(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "usergroup.get",
+			Params: `{"output":"extend","selectUsers":"extend","limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"usrgrpids": "7", "userids": "5", "search": "Oper", "limit": float64(1)},
+			Method: "usergroup.get",
+			Params: `{"output":"extend","usrgrpids":["7"],"userids":["5"],"search":{"name":"Oper"},"selectUsers":"extend","limit":1}`,
+		},
+		{
+			Name:     "returns model groups",
+			Setup:    addUserGroup,
+			Args:     map[string]interface{}{},
+			WantText: "Operators",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("usergroup.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Failed to get user groups",
+		},
+	})
+}
+
+func TestCreateUserGroup(t *testing.T) {
+	zabbixtest.RunToolCases(t, usergroups.CreateUserGroup(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing name",
+			Args:      map[string]interface{}{},
+			WantError: "name is required",
+		},
+		{
+			Name:     "create",
+			Args:     map[string]interface{}{"name": "Operators", "gui_access": float64(2), "debug_mode": float64(1)},
+			Method:   "usergroup.create",
+			Params:   `{"name":"Operators","gui_access":2,"debug_mode":1}`,
+			WantText: "User group created",
+		},
+	})
+}
+
+func TestUpdateUserGroup(t *testing.T) {
+	zabbixtest.RunToolCases(t, usergroups.UpdateUserGroup(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing usrgrpid",
+			Args:      map[string]interface{}{"name": "x"},
+			WantError: "usrgrpid is required",
+		},
+		{
+			Name:     "zero values are sent",
+			Setup:    addUserGroup,
+			Args:     map[string]interface{}{"usrgrpid": "7", "users_status": float64(0), "debug_mode": float64(0)},
+			Method:   "usergroup.update",
+			Params:   `{"usrgrpid":"7","users_status":0,"debug_mode":0}`,
+			WantText: "User group updated",
+		},
+	})
+}
+
+func TestDeleteUserGroup(t *testing.T) {
+	zabbixtest.RunToolCases(t, usergroups.DeleteUserGroup(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing usrgrpids",
+			Args:      map[string]interface{}{},
+			WantError: "usrgrpids is required",
+		},
+		{
+			Name:     "delete",
+			Setup:    addUserGroup,
+			Args:     map[string]interface{}{"usrgrpids": "7,"},
+			Method:   "usergroup.delete",
+			Params:   `["7"]`,
+			WantText: "User groups deleted",
+		},
+	})
+}
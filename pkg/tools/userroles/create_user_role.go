@@ -12,6 +12,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type UserRoleCreateParams struct {
@@ -22,12 +24,21 @@ type UserRoleCreateParams struct {
 func CreateUserRole(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("create_user_role",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create User Role",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new user role in Zabbix."),
 			mcp.WithString("name", mcp.Description("Name of the role"), mcp.Required()),
 			mcp.WithNumber("type", mcp.Description("Role type: 1=User, 2=Admin, 3=Super admin"), mcp.Required()),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return createUserRoleHandler(ctx, req, logger)
+			return createUserRoleHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -58,7 +69,7 @@ func createUserRoleHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		Type: int(roleType),
 	}
 
-	result, err := zabbix.Call("role.create", params)
+	result, err := zabbix.CallContext(ctx, "role.create", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create user role: %v", err)), nil
 	}
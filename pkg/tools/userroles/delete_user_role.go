@@ -13,16 +13,27 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func DeleteUserRole(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("delete_user_role",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete User Role",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete user roles from Zabbix."),
 			mcp.WithString("roleids", mcp.Description("Comma-separated list of role IDs to delete"), mcp.Required()),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return deleteUserRoleHandler(ctx, req, logger)
+			return deleteUserRoleHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -50,7 +61,7 @@ func deleteUserRoleHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		}
 	}
 
-	result, err := zabbix.Call("role.delete", roleids)
+	result, err := zabbix.CallContext(ctx, "role.delete", roleids)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete user roles: %v", err)), nil
 	}
@@ -13,6 +13,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -35,14 +36,22 @@ type UserRoleGetParams struct {
 func GetUserRoles(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_user_roles",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get User Roles",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Retrieve user roles from Zabbix."),
 			mcp.WithString("roleids", mcp.Description("Comma-separated list of role IDs to filter by")),
 			mcp.WithString("search", mcp.Description("Search roles by name")),
 			mcp.WithNumber("limit", mcp.Description("Max roles to return (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getUserRolesHandler(ctx, req, logger)
+			return getUserRolesHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -71,7 +80,7 @@ func getUserRolesHandler(ctx context.Context, req mcp.CallToolRequest, logger *l
 		}
 	}
 
-	result, err := zabbix.Call("role.get", params)
+	result, err := zabbix.CallContext(ctx, "role.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get user roles: %v", err)), nil
 	}
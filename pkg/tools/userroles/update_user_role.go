@@ -12,6 +12,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type UserRoleUpdateParams struct {
@@ -22,12 +24,21 @@ type UserRoleUpdateParams struct {
 func UpdateUserRole(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("update_user_role",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update User Role",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Update an existing user role in Zabbix."),
 			mcp.WithString("roleid", mcp.Description("Role ID to update"), mcp.Required()),
 			mcp.WithString("name", mcp.Description("New name for the role")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return updateUserRoleHandler(ctx, req, logger)
+			return updateUserRoleHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -54,7 +65,7 @@ func updateUserRoleHandler(ctx context.Context, req mcp.CallToolRequest, logger
 		params.Name = v
 	}
 
-	result, err := zabbix.Call("role.update", params)
+	result, err := zabbix.CallContext(ctx, "role.update", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update user role: %v", err)), nil
 	}
@@ -0,0 +1,97 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package userroles_test
+
+import (
+	"testing"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/userroles"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addRole(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add("role", zabbixtest.Object{"roleid": "4", "name": "Read-only operator", "type": "1"})
+}
+
+func TestGetUserRoles(t *testing.T) {
+	zabbixtest.RunToolCases(t, userroles.GetUserRoles(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "role.get",
+			Params: `{"output":"extend","selectRules":"extend","limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"roleids": "4", "search": "operator", "limit": float64(3)},
+			Method: "role.get",
+			Params: `{"output":"extend","roleids":["4"],"search":{"name":"operator"},"selectRules":"extend","limit":3}`,
+		},
+		{
+			Name:     "returns model roles",
+			Setup:    addRole,
+			Args:     map[string]interface{}{},
+			WantText: "Read-only operator",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("role.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Failed to get user roles",
+		},
+	})
+}
+
+func TestCreateUserRole(t *testing.T) {
+	zabbixtest.RunToolCases(t, userroles.CreateUserRole(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing type",
+			Args:      map[string]interface{}{"name": "Auditor"},
+			WantError: "type is required",
+		},
+		{
+			Name:     "create",
+			Args:     map[string]interface{}{"name": "Auditor", "type": float64(1)},
+			Method:   "role.create",
+			Params:   `{"name":"Auditor","type":1}`,
+			WantText: "User role created",
+		},
+	})
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	zabbixtest.RunToolCases(t, userroles.UpdateUserRole(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing roleid",
+			Args:      map[string]interface{}{"name": "x"},
+			WantError: "roleid is required",
+		},
+		{
+			Name:     "rename",
+			Setup:    addRole,
+			Args:     map[string]interface{}{"roleid": "4", "name": "Auditor"},
+			Method:   "role.update",
+			Params:   `{"roleid":"4","name":"Auditor"}`,
+			WantText: "User role updated",
+		},
+	})
+}
+
+func TestDeleteUserRole(t *testing.T) {
+	zabbixtest.RunToolCases(t, userroles.DeleteUserRole(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing roleids",
+			Args:      map[string]interface{}{},
+			WantError: "roleids is required",
+		},
+		{
+			Name:     "delete",
+			Setup:    addRole,
+			Args:     map[string]interface{}{"roleids": "4"},
+			Method:   "role.delete",
+			Params:   `["4"]`,
+			WantText: "User roles deleted",
+		},
+	})
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type UserCreateParams struct {
@@ -27,6 +29,15 @@ type UserCreateParams struct {
 func CreateUser(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("create_user",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Create User",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(false),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Create a new user in Zabbix."),
 			mcp.WithString("username", mcp.Description("Username for login"), mcp.Required()),
 			mcp.WithString("passwd", mcp.Description("User password"), mcp.Required()),
@@ -36,7 +47,7 @@ func CreateUser(logger *log.Logger) server.ServerTool {
 			mcp.WithString("surname", mcp.Description("Last name of the user")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return createUserHandler(ctx, req, logger)
+			return createUserHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -82,7 +93,7 @@ func createUserHandler(ctx context.Context, req mcp.CallToolRequest, logger *log
 		params.Surname = v
 	}
 
-	result, err := zabbix.Call("user.create", params)
+	result, err := zabbix.CallContext(ctx, "user.create", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create user: %v", err)), nil
 	}
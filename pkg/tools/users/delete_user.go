@@ -13,16 +13,29 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/confirm"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 func DeleteUser(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("delete_user",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Delete User",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Delete users from Zabbix."),
 			mcp.WithString("userids", mcp.Description("Comma-separated list of user IDs to delete"), mcp.Required()),
+			confirm.WithConfirm(),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return deleteUserHandler(ctx, req, logger)
+			return deleteUserHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -50,7 +63,41 @@ func deleteUserHandler(ctx context.Context, req mcp.CallToolRequest, logger *log
 		}
 	}
 
-	result, err := zabbix.Call("user.delete", userids)
+	result, err := zabbix.CallContext(ctx, "user.get", map[string]interface{}{
+		"output":  []string{"userid", "username", "name", "surname"},
+		"userids": userids,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to resolve users: %v", err)), nil
+	}
+
+	var users []struct {
+		UserID   string `json:"userid"`
+		Username string `json:"username"`
+		Name     string `json:"name"`
+		Surname  string `json:"surname"`
+	}
+	if err := json.Unmarshal(result, &users); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse users: %v", err)), nil
+	}
+
+	found := make([]confirm.Object, 0, len(users))
+	for _, u := range users {
+		found = append(found, confirm.Object{
+			ID:      u.UserID,
+			Name:    u.Username,
+			Details: strings.TrimSpace(u.Name + " " + u.Surname),
+		})
+	}
+
+	if res := confirm.Request(ctx, req, confirm.Operation{
+		Action:  "Delete " + confirm.Plural(len(userids), "user", "users"),
+		Objects: confirm.Resolve(userids, found),
+	}, logger); res != nil {
+		return res, nil
+	}
+
+	result, err = zabbix.CallContext(ctx, "user.delete", userids)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete users: %v", err)), nil
 	}
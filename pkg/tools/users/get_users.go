@@ -13,6 +13,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
 	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
@@ -47,7 +48,15 @@ type UserGetParams struct {
 func GetUsers(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_users",
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{IdempotentHint: utils.ToBoolPtr(true)}),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Get Users",
+					ReadOnlyHint:    utils.ToBoolPtr(true),
+					DestructiveHint: utils.ToBoolPtr(false),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Retrieve users from Zabbix."),
 			mcp.WithString("userids", mcp.Description("Comma-separated list of user IDs to filter by")),
 			mcp.WithString("usrgrpids", mcp.Description("Comma-separated list of user group IDs to filter by")),
@@ -55,7 +64,7 @@ func GetUsers(logger *log.Logger) server.ServerTool {
 			mcp.WithNumber("limit", mcp.Description("Max users to return (default: 100)")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return getUsersHandler(ctx, req, logger)
+			return getUsersHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -88,7 +97,7 @@ func getUsersHandler(ctx context.Context, req mcp.CallToolRequest, logger *log.L
 		}
 	}
 
-	result, err := zabbix.Call("user.get", params)
+	result, err := zabbix.CallContext(ctx, "user.get", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get users: %v", err)), nil
 	}
@@ -13,6 +13,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/logging"
+	"github.com/vfcastr/Zabbix-MCP/pkg/utils"
 )
 
 type UserUpdateParams struct {
@@ -28,6 +30,15 @@ type UserUpdateParams struct {
 func UpdateUser(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("update_user",
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:           "Update User",
+					ReadOnlyHint:    utils.ToBoolPtr(false),
+					DestructiveHint: utils.ToBoolPtr(true),
+					IdempotentHint:  utils.ToBoolPtr(true),
+					OpenWorldHint:   utils.ToBoolPtr(false),
+				},
+			),
 			mcp.WithDescription("Update an existing user in Zabbix."),
 			mcp.WithString("userid", mcp.Description("User ID to update"), mcp.Required()),
 			mcp.WithString("username", mcp.Description("New username")),
@@ -38,7 +49,7 @@ func UpdateUser(logger *log.Logger) server.ServerTool {
 			mcp.WithString("usrgrpids", mcp.Description("Comma-separated list of user group IDs")),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return updateUserHandler(ctx, req, logger)
+			return updateUserHandler(ctx, req, logging.FromContext(ctx, logger))
 		},
 	}
 }
@@ -86,7 +97,7 @@ func updateUserHandler(ctx context.Context, req mcp.CallToolRequest, logger *log
 		params.UserGroups = groups
 	}
 
-	result, err := zabbix.Call("user.update", params)
+	result, err := zabbix.CallContext(ctx, "user.update", params)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update user: %v", err)), nil
 	}
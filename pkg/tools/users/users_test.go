@@ -0,0 +1,114 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package users_test
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tools/users"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+)
+
+func addUser(t *testing.T, s *zabbixtest.Server) {
+	s.Model.Add("user", zabbixtest.Object{"userid": "5", "username": "jdoe", "name": "Jane", "surname": "Doe", "roleid": "1"})
+}
+
+func TestGetUsers(t *testing.T) {
+	zabbixtest.RunToolCases(t, users.GetUsers(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:   "defaults",
+			Args:   map[string]interface{}{},
+			Method: "user.get",
+			Params: `{"output":"extend","selectUsrgrps":"extend","selectRole":"extend","limit":100}`,
+		},
+		{
+			Name:   "filters",
+			Args:   map[string]interface{}{"userids": "5, 6", "usrgrpids": "7", "search": "doe", "limit": float64(10)},
+			Method: "user.get",
+			Params: `{"output":"extend","userids":["5","6"],"usrgrpids":["7"],"search":{"username":"doe","name":"doe"},"selectUsrgrps":"extend","selectRole":"extend","limit":10}`,
+		},
+		{
+			Name:     "returns model users",
+			Setup:    addUser,
+			Args:     map[string]interface{}{"userids": "5"},
+			WantText: "jdoe",
+		},
+		{
+			Name:      "api error",
+			Setup:     zabbixtest.Failing("user.get"),
+			Args:      map[string]interface{}{},
+			WantError: "Failed to get users",
+		},
+	})
+}
+
+func TestCreateUser(t *testing.T) {
+	zabbixtest.RunToolCases(t, users.CreateUser(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing usrgrpids",
+			Args:      map[string]interface{}{"username": "jdoe", "passwd": "secret", "roleid": "1"},
+			WantError: "username, passwd, roleid, and usrgrpids are required",
+		},
+		{
+			Name:     "create",
+			Args:     map[string]interface{}{"username": "jdoe", "passwd": "secret", "roleid": "1", "usrgrpids": "7, 8", "name": "Jane", "surname": "Doe"},
+			Method:   "user.create",
+			Params:   `{"username":"jdoe","name":"Jane","surname":"Doe","passwd":"secret","roleid":"1","usrgrps":[{"usrgrpid":"7"},{"usrgrpid":"8"}]}`,
+			WantText: "User created",
+		},
+	})
+}
+
+func TestUpdateUser(t *testing.T) {
+	zabbixtest.RunToolCases(t, users.UpdateUser(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing userid",
+			Args:      map[string]interface{}{"name": "x"},
+			WantError: "userid is required",
+		},
+		{
+			Name:     "update",
+			Setup:    addUser,
+			Args:     map[string]interface{}{"userid": "5", "surname": "Smith", "roleid": "2", "usrgrpids": "9"},
+			Method:   "user.update",
+			Params:   `{"userid":"5","surname":"Smith","roleid":"2","usrgrps":[{"usrgrpid":"9"}]}`,
+			WantText: "User updated",
+		},
+		{
+			Name:      "unknown user",
+			Args:      map[string]interface{}{"userid": "1", "name": "x"},
+			WantError: "Failed to update user",
+		},
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	zabbixtest.RunToolCases(t, users.DeleteUser(zabbixtest.Logger()), []zabbixtest.ToolCase{
+		{
+			Name:      "missing userids",
+			Args:      map[string]interface{}{},
+			WantError: "userids is required",
+		},
+		{
+			Name:      "requires confirmation",
+			Setup:     addUser,
+			Args:      map[string]interface{}{"userids": "5"},
+			Method:    "user.get",
+			Params:    `{"output":["userid","username","name","surname"],"userids":["5"]}`,
+			WantError: `"details": "Jane Doe"`,
+			Check: func(t *testing.T, s *zabbixtest.Server, _ *mcp.CallToolResult) {
+				s.AssertNotCalled(t, "user.delete")
+			},
+		},
+		{
+			Name:     "confirmed",
+			Setup:    addUser,
+			Args:     map[string]interface{}{"userids": "5", "confirm": true},
+			Method:   "user.delete",
+			Params:   `["5"]`,
+			WantText: "Users deleted",
+		},
+	})
+}
@@ -0,0 +1,181 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package tracing sets up OpenTelemetry tracing of MCP tool calls and the
+// Zabbix API requests they make. Incoming W3C trace context is continued,
+// so the spans join the caller's trace.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing environment variables. The OTLP exporter also honours the
+// standard OTEL_EXPORTER_OTLP_* variables, such as headers and timeouts.
+const (
+	TracingExporter = "MCP_TRACING_EXPORTER"
+	TracingEndpoint = "MCP_TRACING_ENDPOINT"
+	TracingFile     = "MCP_TRACING_FILE"
+)
+
+// Exporters
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+	ExporterFile = "file"
+)
+
+// ServiceName is the service.name of exported spans
+const ServiceName = "zabbix-mcp-server"
+
+const instrumentationName = "github.com/vfcastr/Zabbix-MCP"
+
+// Config selects where spans are exported
+type Config struct {
+	// Exporter is none, otlp or file
+	Exporter string `yaml:"exporter" toml:"exporter"`
+	// Endpoint is the OTLP/HTTP endpoint URL, e.g.
+	// http://collector:4318. When empty the OTEL_EXPORTER_OTLP_* variables
+	// apply.
+	Endpoint string `yaml:"endpoint" toml:"endpoint"`
+	// File receives one JSON document per span with the file exporter
+	File string `yaml:"file" toml:"file"`
+}
+
+// Validate checks the exporter settings
+func (c Config) Validate() error {
+	switch c.Exporter {
+	case "", ExporterNone, ExporterOTLP:
+	case ExporterFile:
+		if c.File == "" {
+			return fmt.Errorf("the file exporter needs a file")
+		}
+	default:
+		return fmt.Errorf("exporter must be none, otlp or file, got %q", c.Exporter)
+	}
+	return nil
+}
+
+// Init installs the global tracer provider and W3C trace context
+// propagation. The returned function flushes and stops the exporter. With
+// no exporter, spans are not recorded but trace context still propagates.
+func Init(ctx context.Context, config Config, version string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var closeFile func() error
+	switch config.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if config.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(config.Endpoint))
+		}
+		var err error
+		if exporter, err = otlptracehttp.New(ctx, opts...); err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+	case ExporterFile:
+		file, err := os.OpenFile(config.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open trace file: %w", err)
+		}
+		if exporter, err = stdouttrace.New(stdouttrace.WithWriter(file)); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to create file exporter: %w", err)
+		}
+		closeFile = file.Close
+	default:
+		return nil, config.Validate()
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(ServiceName),
+			semconv.ServiceVersion(version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeFile != nil {
+			err = errors.Join(err, closeFile())
+		}
+		return err
+	}, nil
+}
+
+// Tracer returns the tracer of the server's spans
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Middleware continues the trace context of incoming HTTP requests
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ToolMiddleware opens a span for every tool call with the tool name,
+// session ID and argument names. Argument values are not recorded as they
+// may hold credentials.
+func ToolMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool := request.Params.Name
+		attrs := []attribute.KeyValue{
+			attribute.String("mcp.method", string(mcp.MethodToolsCall)),
+			attribute.String("mcp.tool.name", tool),
+			attribute.StringSlice("mcp.tool.argument_keys", argumentKeys(request)),
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			attrs = append(attrs, attribute.String("mcp.session.id", session.SessionID()))
+		}
+
+		ctx, span := Tracer().Start(ctx, "tools/call "+tool, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+		defer span.End()
+
+		result, err := next(ctx, request)
+		switch {
+		case err != nil:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		case result != nil && result.IsError:
+			span.SetStatus(codes.Error, "tool returned an error result")
+		}
+		return result, err
+	}
+}
+
+// argumentKeys returns the sorted names of the call's arguments
+func argumentKeys(request mcp.CallToolRequest) []string {
+	args := request.GetArguments()
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,148 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+	"github.com/vfcastr/Zabbix-MCP/pkg/tracing"
+	"github.com/vfcastr/Zabbix-MCP/pkg/zabbixtest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// record installs a tracer provider that keeps the ended spans in memory
+func record(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestToolSpanContinuesIncomingTrace(t *testing.T) {
+	recorder := record(t)
+	s := zabbixtest.NewServer(t)
+	s.Fail("item.get", zabbixtest.Error{Code: zabbixtest.CodeInvalidParams, Message: "Invalid params.", Data: "Unknown field"}, 1)
+	session := zabbixtest.NewSession()
+	sessionCtx := s.SessionContext(t, session)
+
+	tool := tracing.ToolMiddleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zabbix, err := client.GetZabbixClientFromContext(ctx, zabbixtest.Logger())
+		if err != nil {
+			return nil, err
+		}
+		if _, err := zabbix.CallContext(ctx, "host.get", map[string]interface{}{}); err != nil {
+			return nil, err
+		}
+		if _, err := zabbix.CallContext(ctx, "item.get", map[string]interface{}{}); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	// The HTTP middleware extracts the caller's traceparent
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	handler := tracing.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := trace.ContextWithRemoteSpanContext(sessionCtx, trace.SpanContextFromContext(r.Context()))
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "get_hosts"
+		request.Params.Arguments = map[string]interface{}{"search": "web", "limit": 5}
+		if _, err := tool(ctx, request); err != nil {
+			t.Error(err)
+		}
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	toolSpan, hostSpan, itemSpan := spans["tools/call get_hosts"], spans["zabbix host.get"], spans["zabbix item.get"]
+	if toolSpan == nil || hostSpan == nil || itemSpan == nil {
+		t.Fatalf("expected tool and Zabbix spans, got %v", spans)
+	}
+
+	if got := toolSpan.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("expected the incoming trace %s, got %s", traceID, got)
+	}
+	attrs := attributes(toolSpan)
+	if attrs["mcp.session.id"].AsString() != session.ID || strings.Join(attrs["mcp.tool.argument_keys"].AsStringSlice(), ",") != "limit,search" {
+		t.Errorf("unexpected tool span attributes: %v", attrs)
+	}
+	if toolSpan.Status().Code != codes.Error {
+		t.Errorf("expected the error result to mark the tool span, got %v", toolSpan.Status())
+	}
+
+	for _, span := range []sdktrace.ReadOnlySpan{hostSpan, itemSpan} {
+		if span.Parent().SpanID() != toolSpan.SpanContext().SpanID() {
+			t.Errorf("%s is not a child of the tool span", span.Name())
+		}
+	}
+	if attrs := attributes(hostSpan); attrs["zabbix.instance"].AsString() != "default" || attrs["zabbix.response.size"].AsInt64() == 0 {
+		t.Errorf("unexpected host.get span attributes: %v", attrs)
+	}
+	if attrs := attributes(itemSpan); attrs["zabbix.error.code"].AsInt64() != zabbixtest.CodeInvalidParams || itemSpan.Status().Code != codes.Error {
+		t.Errorf("unexpected item.get span: %v %v", attrs, itemSpan.Status())
+	}
+}
+
+func TestFileExporter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "traces.json")
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	shutdown, err := tracing.Init(context.Background(), tracing.Config{Exporter: tracing.ExporterFile, File: file}, "1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := tracing.Tracer().Start(context.Background(), "tools/call get_hosts")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"Name":"tools/call get_hosts"`, `"Value":"zabbix-mcp-server"`, `"Value":"1.2.3"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %s in the trace file:\n%s", want, data)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	for _, config := range []tracing.Config{{Exporter: "jaeger"}, {Exporter: tracing.ExporterFile}} {
+		if err := config.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", config)
+		}
+	}
+}
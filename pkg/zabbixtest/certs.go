@@ -0,0 +1,145 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package zabbixtest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// CA is a throwaway certificate authority for TLS tests
+type CA struct {
+	// File is the PEM file of the CA certificate
+	File string
+
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	dir  string
+}
+
+// NewCA creates a certificate authority whose files are removed when the
+// test ends
+func NewCA(t testing.TB) *CA {
+	t.Helper()
+
+	key := newKey(t)
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber(t),
+		Subject:               pkix.Name{CommonName: "zabbixtest CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca := &CA{cert: cert, key: key, dir: t.TempDir()}
+	ca.File = ca.writePEM(t, "ca.pem", "CERTIFICATE", der)
+	return ca
+}
+
+// Pool returns a pool holding the CA certificate
+func (ca *CA) Pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+// Issue creates a certificate for commonName that is valid for server and
+// client authentication. Names that parse as IP addresses become IP SANs.
+// It returns the PEM certificate and key files.
+func (ca *CA) Issue(t testing.TB, commonName string, names ...string) (certFile, keyFile string) {
+	t.Helper()
+
+	key := newKey(t)
+	template := &x509.Certificate{
+		SerialNumber: serialNumber(t),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	for _, name := range names {
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, name)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile = ca.writePEM(t, commonName+".pem", "CERTIFICATE", der)
+	keyFile = ca.writePEM(t, commonName+"-key.pem", "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+// Certificate issues a certificate like Issue and loads it
+func (ca *CA) Certificate(t testing.TB, commonName string, names ...string) tls.Certificate {
+	t.Helper()
+
+	cert, err := tls.LoadX509KeyPair(ca.Issue(t, commonName, names...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func (ca *CA) writePEM(t testing.TB, name, blockType string, der []byte) string {
+	t.Helper()
+
+	file := filepath.Join(ca.dir, name)
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func newKey(t testing.TB) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func serialNumber(t testing.TB) *big.Int {
+	t.Helper()
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return serial
+}
@@ -0,0 +1,457 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package zabbixtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Object is a Zabbix API object as returned by *.get
+type Object map[string]interface{}
+
+// Object types with first-class support in the model. Any other API object
+// type is stored generically under the name used in its method prefix.
+const (
+	Hosts        = "host"
+	Items        = "item"
+	Triggers     = "trigger"
+	Problems     = "problem"
+	Events       = "event"
+	Templates    = "template"
+	UserMacros   = "usermacro"
+	GlobalMacros = "globalmacro"
+	HostGroups   = "hostgroup"
+	History      = "history"
+	Trends       = "trend"
+)
+
+// idFields lists object types whose ID field is not "<type>id"
+var idFields = map[string]string{
+	"hostgroup":        "groupid",
+	"templategroup":    "groupid",
+	"problem":          "eventid",
+	"event":            "eventid",
+	"usermacro":        "hostmacroid",
+	"globalmacro":      "globalmacroid",
+	"discoveryrule":    "itemid",
+	"itemprototype":    "itemid",
+	"triggerprototype": "triggerid",
+	"history":          "itemid",
+	"trend":            "itemid",
+	"proxygroup":       "proxy_groupid",
+	"usergroup":        "usrgrpid",
+	"role":             "roleid",
+	"auditlog":         "auditid",
+}
+
+// relatedTypes maps select* result fields to the object type they contain
+var relatedTypes = map[string]string{
+	"groups":          "hostgroup",
+	"hostgroups":      "hostgroup",
+	"templategroups":  "templategroup",
+	"parentTemplates": "template",
+	"macros":          "usermacro",
+}
+
+// selectFields maps select* parameters whose result field is not the
+// parameter name with a lower-case first letter
+var selectFields = map[string]string{
+	"selectHostGroups":     "hostgroups",
+	"selectTemplateGroups": "templategroups",
+}
+
+// IDField returns the name of the ID field of an object type
+func IDField(objectType string) string {
+	if field, ok := idFields[objectType]; ok {
+		return field
+	}
+	return objectType + "id"
+}
+
+// Model is an in-memory store of Zabbix objects that serves the generic
+// get/create/update/delete API methods
+type Model struct {
+	mu      sync.Mutex
+	objects map[string][]Object
+	nextID  int
+}
+
+// NewModel creates an empty model
+func NewModel() *Model {
+	return &Model{
+		objects: map[string][]Object{},
+		nextID:  10000,
+	}
+}
+
+// Add stores obj and returns its ID, assigning one if obj has none
+func (m *Model) Add(objectType string, obj Object) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.add(objectType, obj)
+}
+
+func (m *Model) add(objectType string, obj Object) string {
+	field := IDField(objectType)
+	id, _ := obj[field].(string)
+	if id == "" {
+		m.nextID++
+		id = strconv.Itoa(m.nextID)
+	}
+
+	// Round trip through JSON so nested values have the types the API
+	// decoder produces
+	var stored Object
+	data, _ := json.Marshal(obj)
+	json.Unmarshal(data, &stored)
+	if stored == nil {
+		stored = Object{}
+	}
+	stored[field] = id
+	m.objects[objectType] = append(m.objects[objectType], stored)
+
+	return id
+}
+
+// Get returns a copy of the object with the given ID
+func (m *Model) Get(objectType, id string) (Object, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if i := m.index(objectType, id); i >= 0 {
+		return copyObject(m.objects[objectType][i]), true
+	}
+	return nil, false
+}
+
+// List returns copies of all objects of a type
+func (m *Model) List(objectType string) []Object {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := make([]Object, 0, len(m.objects[objectType]))
+	for _, obj := range m.objects[objectType] {
+		list = append(list, copyObject(obj))
+	}
+	return list
+}
+
+// index returns the position of an object or -1. The caller must hold m.mu.
+func (m *Model) index(objectType, id string) int {
+	field := IDField(objectType)
+	for i, obj := range m.objects[objectType] {
+		if obj[field] == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Call serves an API method from the model
+func (m *Model) Call(method string, params json.RawMessage) (interface{}, *Error) {
+	objectType, verb, ok := strings.Cut(method, ".")
+	if !ok {
+		return nil, &Error{Code: CodeMethodUnknown, Message: "Method not found.", Data: fmt.Sprintf("Incorrect method %q.", method)}
+	}
+
+	// Global macros share the usermacro API
+	if objectType == UserMacros {
+		switch verb {
+		case "createglobal", "updateglobal", "deleteglobal":
+			objectType, verb = GlobalMacros, strings.TrimSuffix(verb, "global")
+		case "get":
+			var p struct {
+				GlobalMacro bool `json:"globalmacro"`
+			}
+			json.Unmarshal(params, &p)
+			if p.GlobalMacro {
+				objectType = GlobalMacros
+			}
+		}
+	}
+
+	var decoded interface{}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &decoded); err != nil {
+			return nil, invalidParams("invalid JSON: %v", err)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch verb {
+	case "get":
+		p, _ := decoded.(map[string]interface{})
+		return m.get(objectType, p), nil
+	case "create":
+		return m.create(objectType, decoded)
+	case "update":
+		return m.update(objectType, decoded)
+	case "delete":
+		return m.delete(objectType, decoded)
+	default:
+		// Mass operations, acknowledge, copy and similar return the IDs
+		// they were given
+		field := IDField(objectType) + "s"
+		if p, ok := decoded.(map[string]interface{}); ok {
+			if ids, ok := p[field]; ok {
+				return map[string]interface{}{field: toStrings(ids)}, nil
+			}
+		}
+		return true, nil
+	}
+}
+
+func (m *Model) get(objectType string, params map[string]interface{}) interface{} {
+	var matched []Object
+	for _, obj := range m.objects[objectType] {
+		if matches(obj, params) {
+			matched = append(matched, obj)
+		}
+	}
+
+	if count, _ := params["countOutput"].(bool); count {
+		return strconv.Itoa(len(matched))
+	}
+	if limit, ok := params["limit"].(float64); ok && limit > 0 && int(limit) < len(matched) {
+		matched = matched[:int(limit)]
+	}
+
+	result := make([]Object, 0, len(matched))
+	for _, obj := range matched {
+		out := project(obj, params["output"], IDField(objectType))
+		for key, value := range params {
+			if strings.HasPrefix(key, "select") {
+				field := selectField(key)
+				out[field] = m.related(objectType, obj, field, value)
+			}
+		}
+		result = append(result, out)
+	}
+	return result
+}
+
+// related resolves a select* parameter for obj. Fields already stored on
+// the object win; otherwise objects of the related type that reference obj
+// are returned.
+func (m *Model) related(objectType string, obj Object, field string, selector interface{}) interface{} {
+	var list []interface{}
+	if value, ok := obj[field]; ok {
+		items, isList := value.([]interface{})
+		if !isList {
+			return value
+		}
+		list = items
+	} else {
+		relatedType, ok := relatedTypes[field]
+		if !ok {
+			relatedType = strings.TrimSuffix(field, "s")
+		}
+		keys := []string{IDField(objectType)}
+		if objectType == Templates {
+			// Templates are hosts for the purpose of item and trigger ownership
+			keys = append(keys, "hostid")
+		}
+		relatedID := IDField(relatedType)
+		for _, rel := range m.objects[relatedType] {
+			// Match children that reference obj and parents obj references
+			if references(rel, keys, obj[IDField(objectType)]) ||
+				(obj[relatedID] != nil && rel[relatedID] == obj[relatedID]) {
+				list = append(list, map[string]interface{}(copyObject(rel)))
+			}
+		}
+	}
+
+	if selector == "count" {
+		return strconv.Itoa(len(list))
+	}
+	if list == nil {
+		return []interface{}{}
+	}
+	return list
+}
+
+func (m *Model) create(objectType string, params interface{}) (interface{}, *Error) {
+	var ids []string
+	for _, entry := range toList(params) {
+		obj, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, invalidParams("object expected, got %T", entry)
+		}
+		// Creating an object never reuses a client supplied ID
+		delete(obj, IDField(objectType))
+		ids = append(ids, m.add(objectType, obj))
+	}
+	return map[string]interface{}{IDField(objectType) + "s": ids}, nil
+}
+
+func (m *Model) update(objectType string, params interface{}) (interface{}, *Error) {
+	field := IDField(objectType)
+	var ids []string
+	for _, entry := range toList(params) {
+		obj, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, invalidParams("object expected, got %T", entry)
+		}
+		id, _ := obj[field].(string)
+		i := m.index(objectType, id)
+		if i < 0 {
+			return nil, noPermissions()
+		}
+		for k, v := range obj {
+			m.objects[objectType][i][k] = v
+		}
+		ids = append(ids, id)
+	}
+	return map[string]interface{}{field + "s": ids}, nil
+}
+
+func (m *Model) delete(objectType string, params interface{}) (interface{}, *Error) {
+	ids := toStrings(params)
+	for _, id := range ids {
+		if m.index(objectType, id) < 0 {
+			return nil, noPermissions()
+		}
+	}
+	for _, id := range ids {
+		i := m.index(objectType, id)
+		m.objects[objectType] = append(m.objects[objectType][:i], m.objects[objectType][i+1:]...)
+	}
+	return map[string]interface{}{IDField(objectType) + "s": ids}, nil
+}
+
+// matches applies the *ids, filter and search parameters of a get request
+func matches(obj Object, params map[string]interface{}) bool {
+	for key, value := range params {
+		switch {
+		case key == "filter":
+			filter, _ := value.(map[string]interface{})
+			for field, want := range filter {
+				if !contains(toStrings(want), fmt.Sprint(obj[field])) {
+					return false
+				}
+			}
+		case key == "search":
+			search, _ := value.(map[string]interface{})
+			for field, want := range search {
+				got := strings.ToLower(fmt.Sprint(obj[field]))
+				if !strings.Contains(got, strings.ToLower(fmt.Sprint(want))) {
+					return false
+				}
+			}
+		case strings.HasSuffix(key, "ids"):
+			if !references(obj, []string{strings.TrimSuffix(key, "s")}, toStrings(value)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// references reports whether obj has one of keys set to one of ids, either
+// directly or in a nested list of objects
+func references(obj Object, keys []string, ids interface{}) bool {
+	want := toStrings(ids)
+	for _, key := range keys {
+		if value, ok := obj[key]; ok && contains(want, fmt.Sprint(value)) {
+			return true
+		}
+	}
+	for _, value := range obj {
+		nested, ok := value.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, entry := range nested {
+			if child, ok := entry.(map[string]interface{}); ok && references(child, keys, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// project applies the output parameter to obj
+func project(obj Object, output interface{}, idField string) Object {
+	fields, ok := output.([]interface{})
+	if !ok {
+		return copyObject(obj)
+	}
+	out := Object{idField: obj[idField]}
+	for _, f := range fields {
+		name := fmt.Sprint(f)
+		if value, ok := obj[name]; ok {
+			out[name] = value
+		}
+	}
+	return out
+}
+
+// selectField returns the result field of a select* parameter
+func selectField(param string) string {
+	if field, ok := selectFields[param]; ok {
+		return field
+	}
+	name := strings.TrimPrefix(param, "select")
+	if name == "" {
+		return param
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+func copyObject(obj Object) Object {
+	out := make(Object, len(obj))
+	for k, v := range obj {
+		out[k] = v
+	}
+	return out
+}
+
+func toList(value interface{}) []interface{} {
+	if list, ok := value.([]interface{}); ok {
+		return list
+	}
+	if value == nil {
+		return nil
+	}
+	return []interface{}{value}
+}
+
+// toStrings converts an ID or list of IDs to strings
+func toStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, entry := range v {
+			out = append(out, fmt.Sprint(entry))
+		}
+		return out
+	case nil:
+		return nil
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, entry := range list {
+		if entry == s {
+			return true
+		}
+	}
+	return false
+}
+
+func invalidParams(format string, args ...interface{}) *Error {
+	return &Error{Code: CodeInvalidParams, Message: "Invalid params.", Data: fmt.Sprintf(format, args...)}
+}
+
+func noPermissions() *Error {
+	return &Error{Code: CodeInvalidParams, Message: "Invalid params.", Data: "No permissions to referred object or it does not exist!"}
+}
@@ -0,0 +1,376 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package zabbixtest provides an in-process fake Zabbix 7.0 JSON-RPC server
+// with a programmable in-memory model, request recording and error injection.
+package zabbixtest
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	stdlog "log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+)
+
+// APIVersion is the version a new server reports from apiinfo.version
+const APIVersion = "7.0.0"
+
+// Token is the API token accepted by a new server
+const Token = "zabbixtest-token"
+
+// APIPath is the path the JSON-RPC endpoint is served on
+const APIPath = "/api_jsonrpc.php"
+
+// Zabbix API error codes
+const (
+	CodeInvalidParams = -32602
+	CodeInternal      = -32500
+	CodeMethodUnknown = -32601
+)
+
+// Request is a JSON-RPC request received by the server
+type Request struct {
+	Method string
+	Params json.RawMessage
+	// Auth is the bearer token from the Authorization header, or the
+	// "auth" body field used before Zabbix 6.4
+	Auth   string
+	Header http.Header
+}
+
+// Error is a Zabbix JSON-RPC error
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data"`
+}
+
+// HandlerFunc overrides the response to a method. It returns the result or
+// an error to send back.
+type HandlerFunc func(req Request) (interface{}, *Error)
+
+// failure is an injected error for a method
+type failure struct {
+	err    *Error
+	status int
+	times  int
+}
+
+// Server is a fake Zabbix API server
+type Server struct {
+	*httptest.Server
+	Model *Model
+	// Version is reported by apiinfo.version. Set it before creating
+	// clients to emulate an older Zabbix.
+	Version string
+
+	mu       sync.Mutex
+	tokens   map[string]bool
+	users    map[string]string
+	requests []Request
+	failures map[string]*failure
+	handlers map[string]HandlerFunc
+	sessions int
+}
+
+// NewServer starts a fake Zabbix server that is closed when the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := newServer()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// NewTLSServer starts a fake Zabbix server that serves HTTPS with config,
+// which must hold the server certificate
+func NewTLSServer(t testing.TB, config *tls.Config) *Server {
+	t.Helper()
+
+	s := newServer()
+	s.Server = httptest.NewUnstartedServer(http.HandlerFunc(s.serveHTTP))
+	s.Server.TLS = config
+	// Rejected handshakes are expected in TLS tests
+	s.Server.Config.ErrorLog = stdlog.New(io.Discard, "", 0)
+	s.Server.StartTLS()
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func newServer() *Server {
+	return &Server{
+		Model:    NewModel(),
+		Version:  APIVersion,
+		tokens:   map[string]bool{Token: true},
+		users:    map[string]string{},
+		failures: map[string]*failure{},
+		handlers: map[string]HandlerFunc{},
+	}
+}
+
+// APIURL returns the URL of the JSON-RPC endpoint
+func (s *Server) APIURL() string {
+	return s.URL + APIPath
+}
+
+// AddToken makes the server accept an additional API token
+func (s *Server) AddToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token] = true
+}
+
+// RevokeToken makes the server reject a token, as if its session expired
+func (s *Server) RevokeToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, token)
+}
+
+// AddUser registers credentials accepted by user.login
+func (s *Server) AddUser(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[username] = password
+}
+
+// Handle overrides the response to method
+func (s *Server) Handle(method string, fn HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = fn
+}
+
+// Fail makes the next times calls to method return err. A times of zero
+// fails every call.
+func (s *Server) Fail(method string, err Error, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[method] = &failure{err: &err, times: times}
+}
+
+// FailHTTP makes the next times calls to method return an HTTP status
+// instead of a JSON-RPC response. A times of zero fails every call.
+func (s *Server) FailHTTP(method string, status int, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[method] = &failure{status: status, times: times}
+}
+
+// Requests returns every request received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Calls returns the requests received for method
+func (s *Server) Calls(method string) []Request {
+	var calls []Request
+	for _, req := range s.Requests() {
+		if req.Method == method {
+			calls = append(calls, req)
+		}
+	}
+	return calls
+}
+
+// LastCall returns the most recent request for method
+func (s *Server) LastCall(method string) (Request, bool) {
+	calls := s.Calls(method)
+	if len(calls) == 0 {
+		return Request{}, false
+	}
+	return calls[len(calls)-1], true
+}
+
+// Reset forgets recorded requests and injected failures
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.failures = map[string]*failure{}
+}
+
+// rpcRequest is the wire format of a JSON-RPC request
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      interface{}     `json:"id"`
+	// Auth is the pre-6.4 way of passing the session token
+	Auth string `json:"auth,omitempty"`
+}
+
+// rpcResponse is the wire format of a JSON-RPC response
+type rpcResponse struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   *Error      `json:"error,omitempty"`
+	ID      interface{} `json:"id"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != APIPath {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var rpc rpcRequest
+	if err := json.Unmarshal(body, &rpc); err != nil {
+		http.Error(w, "invalid JSON-RPC request", http.StatusBadRequest)
+		return
+	}
+
+	req := Request{
+		Method: rpc.Method,
+		Params: rpc.Params,
+		Auth:   strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
+		Header: r.Header.Clone(),
+	}
+	if req.Auth == "" {
+		req.Auth = rpc.Auth
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	fail := s.takeFailure(rpc.Method)
+	handler := s.handlers[rpc.Method]
+	s.mu.Unlock()
+
+	if fail != nil && fail.status != 0 {
+		http.Error(w, http.StatusText(fail.status), fail.status)
+		return
+	}
+
+	resp := rpcResponse{JSONRPC: "2.0", ID: rpc.ID}
+	switch {
+	case fail != nil:
+		resp.Error = fail.err
+	case handler != nil:
+		resp.Result, resp.Error = handler(req)
+	default:
+		resp.Result, resp.Error = s.dispatch(req)
+	}
+	if resp.Error == nil && resp.Result == nil {
+		resp.Result = []interface{}{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// takeFailure returns the injected failure for method, consuming one use.
+// The caller must hold s.mu.
+func (s *Server) takeFailure(method string) *failure {
+	fail, ok := s.failures[method]
+	if !ok {
+		return nil
+	}
+	if fail.times > 0 {
+		fail.times--
+		if fail.times == 0 {
+			delete(s.failures, method)
+		}
+	}
+	return fail
+}
+
+// dispatch serves a request from the built-in API model
+func (s *Server) dispatch(req Request) (interface{}, *Error) {
+	switch req.Method {
+	case "apiinfo.version":
+		if req.Auth != "" {
+			return nil, &Error{Code: CodeInvalidParams, Message: "Invalid params.", Data: `The "apiinfo.version" method must be called without the "auth" parameter.`}
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.Version, nil
+	case "user.login":
+		return s.login(req.Params)
+	}
+
+	if !s.authorized(req.Auth) {
+		return nil, &Error{Code: CodeInvalidParams, Message: "Invalid params.", Data: "Not authorised."}
+	}
+
+	switch req.Method {
+	case "user.logout":
+		s.RevokeToken(req.Auth)
+		return true, nil
+	case "user.checkAuthentication":
+		return map[string]interface{}{"sessionid": req.Auth}, nil
+	}
+
+	if err := s.checkGroupSelects(req); err != nil {
+		return nil, err
+	}
+	return s.Model.Call(req.Method, req.Params)
+}
+
+// checkGroupSelects rejects the host and template group select options
+// like servers older than Zabbix 6.2 do
+func (s *Server) checkGroupSelects(req Request) *Error {
+	s.mu.Lock()
+	version, err := client.ParseVersion(s.Version)
+	s.mu.Unlock()
+	if err != nil || version.AtLeast(client.HostGroupsVersion) {
+		return nil
+	}
+
+	var params map[string]interface{}
+	if json.Unmarshal(req.Params, &params) != nil {
+		return nil
+	}
+	for _, option := range []string{"selectHostGroups", "selectTemplateGroups"} {
+		if _, ok := params[option]; ok {
+			return &Error{Code: CodeInvalidParams, Message: "Invalid params.", Data: fmt.Sprintf(`Invalid parameter "/": unexpected parameter "%s".`, option)}
+		}
+	}
+	return nil
+}
+
+// authorized reports whether token is a valid API token or session ID
+func (s *Server) authorized(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[token]
+}
+
+// login implements user.login
+func (s *Server) login(params json.RawMessage) (interface{}, *Error) {
+	var creds struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.Unmarshal(params, &creds); err != nil {
+		return nil, &Error{Code: CodeInvalidParams, Message: "Invalid params.", Data: err.Error()}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if password, ok := s.users[creds.Username]; !ok || password != creds.Password {
+		return nil, &Error{Code: CodeInvalidParams, Message: "Invalid params.", Data: "Incorrect user name or password or account is temporarily blocked."}
+	}
+
+	s.sessions++
+	session := fmt.Sprintf("zabbixtest-session-%d", s.sessions)
+	s.tokens[session] = true
+	return session, nil
+}
@@ -0,0 +1,156 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package zabbixtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/vfcastr/Zabbix-MCP/pkg/client"
+)
+
+// contextServer only attaches sessions to contexts; it serves no requests
+var contextServer = server.NewMCPServer("zabbixtest", "0.0.0")
+
+var sessionCounter atomic.Int64
+
+// Session is a fake MCP client session
+type Session struct {
+	ID           string
+	Capabilities mcp.ClientCapabilities
+	// Elicit answers elicitation requests. Set Capabilities.Elicitation for
+	// tool handlers to use it.
+	Elicit func(ctx context.Context, req mcp.ElicitationRequest) (*mcp.ElicitationResult, error)
+
+	mu            sync.Mutex
+	info          mcp.Implementation
+	elicitations  []mcp.ElicitationRequest
+	notifications chan mcp.JSONRPCNotification
+}
+
+var (
+	_ server.SessionWithClientInfo  = (*Session)(nil)
+	_ server.SessionWithElicitation = (*Session)(nil)
+)
+
+// NewSession creates a session with a unique ID
+func NewSession() *Session {
+	return &Session{
+		ID:            fmt.Sprintf("zabbixtest-%d", sessionCounter.Add(1)),
+		notifications: make(chan mcp.JSONRPCNotification, 16),
+	}
+}
+
+// SessionID returns the session ID
+func (s *Session) SessionID() string { return s.ID }
+
+// Initialize is a no-op; fake sessions are always initialized
+func (s *Session) Initialize() {}
+
+// Initialized always reports true
+func (s *Session) Initialized() bool { return true }
+
+// NotificationChannel returns a buffered channel that is never read
+func (s *Session) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+
+// GetClientInfo returns the client implementation info
+func (s *Session) GetClientInfo() mcp.Implementation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.info
+}
+
+// SetClientInfo sets the client implementation info
+func (s *Session) SetClientInfo(info mcp.Implementation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.info = info
+}
+
+// GetClientCapabilities returns the advertised client capabilities
+func (s *Session) GetClientCapabilities() mcp.ClientCapabilities {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Capabilities
+}
+
+// SetClientCapabilities sets the advertised client capabilities
+func (s *Session) SetClientCapabilities(capabilities mcp.ClientCapabilities) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Capabilities = capabilities
+}
+
+// RequestElicitation records the request and answers it with Elicit
+func (s *Session) RequestElicitation(ctx context.Context, req mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	s.mu.Lock()
+	s.elicitations = append(s.elicitations, req)
+	elicit := s.Elicit
+	s.mu.Unlock()
+
+	if elicit == nil {
+		return nil, server.ErrElicitationNotSupported
+	}
+	return elicit(ctx, req)
+}
+
+// Elicitations returns the elicitation requests received so far
+func (s *Session) Elicitations() []mcp.ElicitationRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]mcp.ElicitationRequest(nil), s.elicitations...)
+}
+
+// Logger returns a logger that discards its output
+func Logger() *log.Logger {
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// Context returns a context carrying a new session whose Zabbix client
+// talks to s
+func (s *Server) Context(t testing.TB) context.Context {
+	return s.SessionContext(t, NewSession())
+}
+
+// SessionContext returns a context carrying session, with a Zabbix client
+// registered for it that talks to s. Retries and rate limiting are disabled
+// to keep tests fast.
+func (s *Server) SessionContext(t testing.TB, session *Session) context.Context {
+	t.Helper()
+
+	zabbix, err := client.NewZabbixClient(context.Background(), session.ID, s.APIURL(), client.TLSConfig{}, Token, Logger())
+	if err != nil {
+		t.Fatalf("failed to create Zabbix client: %v", err)
+	}
+	zabbix.Limiter = nil
+	zabbix.MaxRetries = 0
+	t.Cleanup(func() { client.DeleteZabbixClient(session.ID) })
+
+	return WithSession(context.Background(), session)
+}
+
+// UseConnection sets the connection settings of new Zabbix clients for the
+// test, starting from the defaults
+func UseConnection(t testing.TB, configure func(*client.ConnectionConfig)) {
+	t.Helper()
+	connection := client.DefaultConnectionConfig
+	configure(&connection)
+	client.SetConnection(&connection)
+	t.Cleanup(func() { client.SetConnection(nil) })
+}
+
+// WithSession returns ctx carrying session, for contexts that already hold
+// request values such as the authenticated principal
+func WithSession(ctx context.Context, session *Session) context.Context {
+	return contextServer.WithContext(ctx, session)
+}
@@ -0,0 +1,177 @@
+// Copyright vfcastr 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package zabbixtest
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ToolCase is a table-driven test case for a tool handler
+type ToolCase struct {
+	Name string
+	Args map[string]interface{}
+	// Setup prepares the model or injects failures before the call
+	Setup func(t *testing.T, s *Server)
+	// Method is the Zabbix method the handler is expected to call last.
+	// Params, if set, is the JSON its params must equal.
+	Method string
+	Params string
+	// WantError is a substring of the expected tool error; when empty the
+	// call must succeed
+	WantError string
+	// WantText is a substring expected in the result text
+	WantText string
+	// Check runs additional assertions
+	Check func(t *testing.T, s *Server, result *mcp.CallToolResult)
+}
+
+// RunToolCases runs each case against a fresh server
+func RunToolCases(t *testing.T, tool server.ServerTool, cases []ToolCase) {
+	t.Helper()
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			s := NewServer(t)
+			if tc.Setup != nil {
+				tc.Setup(t, s)
+			}
+
+			result := s.CallTool(t, s.Context(t), tool, tc.Args)
+			text := ResultText(result)
+
+			switch {
+			case tc.WantError != "" && !result.IsError:
+				t.Fatalf("expected error containing %q, got success: %s", tc.WantError, text)
+			case tc.WantError != "" && !strings.Contains(text, tc.WantError):
+				t.Fatalf("expected error containing %q, got: %s", tc.WantError, text)
+			case tc.WantError == "" && result.IsError:
+				t.Fatalf("unexpected error: %s", text)
+			}
+			if tc.WantText != "" && !strings.Contains(text, tc.WantText) {
+				t.Errorf("expected result to contain %q, got: %s", tc.WantText, text)
+			}
+
+			if tc.Method != "" {
+				req, ok := s.LastCall(tc.Method)
+				if !ok {
+					t.Fatalf("expected a %s call, got %v", tc.Method, s.Methods())
+				}
+				if tc.Params != "" {
+					AssertJSON(t, req.Params, tc.Params)
+				}
+			}
+
+			if tc.Check != nil {
+				tc.Check(t, s, result)
+			}
+		})
+	}
+}
+
+// CallTool invokes a tool handler directly with the given arguments
+func (s *Server) CallTool(t testing.TB, ctx context.Context, tool server.ServerTool, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = tool.Tool.Name
+	if args != nil {
+		req.Params.Arguments = args
+	}
+
+	result, err := tool.Handler(ctx, req)
+	if err != nil {
+		t.Fatalf("%s returned error: %v", tool.Tool.Name, err)
+	}
+	if result == nil {
+		t.Fatalf("%s returned no result", tool.Tool.Name)
+	}
+	return result
+}
+
+// Methods returns the methods called so far, in order
+func (s *Server) Methods() []string {
+	var methods []string
+	for _, req := range s.Requests() {
+		methods = append(methods, req.Method)
+	}
+	return methods
+}
+
+// AssertNotCalled fails the test if method was called
+func (s *Server) AssertNotCalled(t testing.TB, method string) {
+	t.Helper()
+	if calls := s.Calls(method); len(calls) > 0 {
+		t.Fatalf("expected no %s call, got %d", method, len(calls))
+	}
+}
+
+// ResultText joins the text content of a tool result
+func ResultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// AssertJSON fails the test unless got and want encode the same JSON value
+func AssertJSON(t testing.TB, got json.RawMessage, want string) {
+	t.Helper()
+
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("invalid JSON %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("invalid expected JSON %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		gotPretty, _ := json.MarshalIndent(gotValue, "", "  ")
+		wantPretty, _ := json.MarshalIndent(wantValue, "", "  ")
+		t.Fatalf("params mismatch\ngot:  %s\nwant: %s", gotPretty, wantPretty)
+	}
+}
+
+// DecodeResult unmarshals the text of a successful tool result into v
+func DecodeResult(t testing.TB, result *mcp.CallToolResult, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(ResultText(result)), v); err != nil {
+		t.Fatalf("failed to decode result %q: %v", ResultText(result), err)
+	}
+}
+
+// AssertChanges checks the "changes" an update tool reports
+func AssertChanges(t testing.TB, result *mcp.CallToolResult, want string) {
+	t.Helper()
+	var response struct {
+		Changes json.RawMessage `json:"changes"`
+	}
+	DecodeResult(t, result, &response)
+	AssertJSON(t, response.Changes, want)
+}
+
+// Failing returns a ToolCase setup that makes every call to method fail
+// with an application error
+func Failing(method string) func(t *testing.T, s *Server) {
+	return func(t *testing.T, s *Server) {
+		s.Fail(method, Error{Code: CodeInternal, Message: "Application error.", Data: "Injected failure."}, 0)
+	}
+}
+
+// AtVersion returns a ToolCase setup that makes the server report version
+// from apiinfo.version
+func AtVersion(version string) func(t *testing.T, s *Server) {
+	return func(t *testing.T, s *Server) {
+		s.Version = version
+	}
+}